@@ -4,6 +4,15 @@ import (
 	"fmt"
 	"github.com/spf13/viper"
 	"log"
+	"strings"
+	"time"
+)
+
+// value of environment server is deployed in, returned from DeployEnv
+const (
+	DeployEnvProduction  = "production"
+	DeployEnvStaging     = "staging"
+	DeployEnvDevelopment = "development"
 )
 
 // App is the application config using in main package
@@ -31,6 +40,9 @@ type appConfig struct {
 	// configFile represent aligo sender
 	aligoSender *string
 
+	// aligoQPS represent max number of call to aligo API per second
+	aligoQPS *float64
+
 	// jwtKey represent jwt key
 	jwtKey *string
 
@@ -47,8 +59,69 @@ type appConfig struct {
 	awsS3Key *string
 
 	esEndPoint *string
+
+	// passwordPepper represent server-side pepper combined with password before hashing (optional)
+	passwordPepper *string
+
+	// phoneNumberHMACKey represent key of HMAC hashing phone number for lookup, encoded in base64
+	phoneNumberHMACKey *string
+
+	// phoneNumberEncryptionKey represent AES key encrypting phone number stored in DB, encoded in base64
+	phoneNumberEncryptionKey *string
+
+	// trustedProxies represent IP or CIDR of proxy (ex, load balancer) trusted to set X-Forwarded-For
+	trustedProxies []string
+
+	// loadShedMaxInFlight represent max number of request in process, over which non-critical request is shed
+	loadShedMaxInFlight *int
+
+	// loadShedDBLatencySLO represent DB latency, over which non-critical request is shed
+	loadShedDBLatencySLO *time.Duration
+
+	// forceHTTPS represent if redirect HTTP request to HTTPS
+	forceHTTPS *bool
+
+	// tokenCookieMode represent mode of delivering token to client (body, cookie, both)
+	tokenCookieMode *string
+
+	// migrateOnStartup represent if versioned migration is applied to database when server start
+	migrateOnStartup *bool
+
+	// strictJSONBinding represent if JSON body having unknown field is rejected rather than ignored
+	strictJSONBinding *bool
+
+	// redisAddress represent address of redis keeping state shared across instances (kept in memory if empty)
+	redisAddress *string
+
+	// redisPassword represent password of redis (not authenticated if empty)
+	redisPassword *string
+
+	// rateLimitPerMinute represent max request of one client to one limited route in a minute
+	rateLimitPerMinute *int
+
+	// nameStrictness represent strictness of character allowed in name (control, html)
+	nameStrictness *string
+
+	// deployEnv represent environment server is deployed in (production, staging, development)
+	deployEnv *string
+
+	// requestTimeout represent timeout of request to route not sending message (ex, login)
+	requestTimeout *time.Duration
+
+	// messageRequestTimeout represent timeout of request to route sending message (ex, certify code), waiting provider
+	messageRequestTimeout *time.Duration
+
+	// tokenRefreshThreshold represent remaining duration of token under which client is told to refresh it
+	tokenRefreshThreshold *time.Duration
 }
 
+// default value of appConfig field, used if environment variable is not set
+const (
+	defaultRequestTimeout        = time.Second * 10
+	defaultMessageRequestTimeout = time.Second * 30
+	defaultTokenRefreshThreshold = time.Minute * 5
+)
+
 // ConfigFile return config file get from environment variable
 func (ac *appConfig) ConfigFile() string {
 	if ac.configFile != nil {
@@ -69,7 +142,7 @@ func (ac *appConfig) MysqlDataSource() string {
 		return *ac.mysqlDataSource
 	}
 
-	format := "%s:%s@tcp(%s)/%s"
+	format := "%s:%s@tcp(%s)/%s?parseTime=true"
 	var args []interface{}
 
 	if viper.IsSet("MYSQL_USERNAME") {
@@ -142,6 +215,16 @@ func (ac *appConfig) AligoSender() string {
 	return *ac.aligoSender
 }
 
+// AligoQPS return aligo QPS get from environment variable (not limited if not set)
+func (ac *appConfig) AligoQPS() float64 {
+	if ac.aligoQPS != nil {
+		return *ac.aligoQPS
+	}
+
+	ac.aligoQPS = _float64(viper.GetFloat64("ALIGO_QPS"))
+	return *ac.aligoQPS
+}
+
 // JwtKey return jwt key get from environment variable
 func (ac *appConfig) JwtKey() string {
 	if ac.jwtKey != nil {
@@ -225,4 +308,227 @@ func (ac *appConfig) EsEndPoint() string {
 	return *ac.esEndPoint
 }
 
-func _string(s string) *string { return &s }
+// PasswordPepper return password pepper get from environment variable (return empty string if not set)
+func (ac *appConfig) PasswordPepper() string {
+	if ac.passwordPepper != nil {
+		return *ac.passwordPepper
+	}
+
+	ac.passwordPepper = _string(viper.GetString("PASSWORD_PEPPER"))
+	return *ac.passwordPepper
+}
+
+// PhoneNumberHMACKey return key of HMAC hashing phone number get from environment variable
+func (ac *appConfig) PhoneNumberHMACKey() string {
+	if ac.phoneNumberHMACKey != nil {
+		return *ac.phoneNumberHMACKey
+	}
+
+	if viper.IsSet("PHONE_NUMBER_HMAC_KEY") {
+		ac.phoneNumberHMACKey = _string(viper.GetString("PHONE_NUMBER_HMAC_KEY"))
+	} else {
+		log.Fatal("please set PHONE_NUMBER_HMAC_KEY in environment variable")
+	}
+	return *ac.phoneNumberHMACKey
+}
+
+// PhoneNumberEncryptionKey return AES key encrypting phone number get from environment variable
+func (ac *appConfig) PhoneNumberEncryptionKey() string {
+	if ac.phoneNumberEncryptionKey != nil {
+		return *ac.phoneNumberEncryptionKey
+	}
+
+	if viper.IsSet("PHONE_NUMBER_ENCRYPTION_KEY") {
+		ac.phoneNumberEncryptionKey = _string(viper.GetString("PHONE_NUMBER_ENCRYPTION_KEY"))
+	} else {
+		log.Fatal("please set PHONE_NUMBER_ENCRYPTION_KEY in environment variable")
+	}
+	return *ac.phoneNumberEncryptionKey
+}
+
+// TrustedProxies return trusted proxies get from environment variable (comma separated, trust nothing if not set)
+func (ac *appConfig) TrustedProxies() []string {
+	if ac.trustedProxies != nil {
+		return ac.trustedProxies
+	}
+
+	ac.trustedProxies = []string{}
+	for _, proxy := range strings.Split(viper.GetString("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			ac.trustedProxies = append(ac.trustedProxies, proxy)
+		}
+	}
+	return ac.trustedProxies
+}
+
+// LoadShedMaxInFlight return max in-flight request get from environment variable (not limited if not set)
+func (ac *appConfig) LoadShedMaxInFlight() int {
+	if ac.loadShedMaxInFlight != nil {
+		return *ac.loadShedMaxInFlight
+	}
+
+	ac.loadShedMaxInFlight = _int(viper.GetInt("LOAD_SHED_MAX_IN_FLIGHT"))
+	return *ac.loadShedMaxInFlight
+}
+
+// LoadShedDBLatencySLO return DB latency SLO get from environment variable (not limited if not set)
+func (ac *appConfig) LoadShedDBLatencySLO() time.Duration {
+	if ac.loadShedDBLatencySLO != nil {
+		return *ac.loadShedDBLatencySLO
+	}
+
+	d, err := time.ParseDuration(viper.GetString("LOAD_SHED_DB_LATENCY_SLO"))
+	if err != nil && viper.GetString("LOAD_SHED_DB_LATENCY_SLO") != "" {
+		log.Fatal("please set LOAD_SHED_DB_LATENCY_SLO in environment variable as duration (ex, 200ms)")
+	}
+	ac.loadShedDBLatencySLO = &d
+	return *ac.loadShedDBLatencySLO
+}
+
+// ForceHTTPS return if redirect HTTP request to HTTPS get from environment variable (not redirected if not set)
+func (ac *appConfig) ForceHTTPS() bool {
+	if ac.forceHTTPS != nil {
+		return *ac.forceHTTPS
+	}
+
+	ac.forceHTTPS = _bool(viper.GetBool("FORCE_HTTPS"))
+	return *ac.forceHTTPS
+}
+
+// TokenCookieMode return mode of delivering token get from environment variable (delivered in body if not set)
+func (ac *appConfig) TokenCookieMode() string {
+	if ac.tokenCookieMode != nil {
+		return *ac.tokenCookieMode
+	}
+
+	ac.tokenCookieMode = _string(viper.GetString("TOKEN_COOKIE_MODE"))
+	return *ac.tokenCookieMode
+}
+
+// MigrateOnStartup return if apply migration when server start get from environment variable (not applied if not set)
+func (ac *appConfig) MigrateOnStartup() bool {
+	if ac.migrateOnStartup != nil {
+		return *ac.migrateOnStartup
+	}
+
+	ac.migrateOnStartup = _bool(viper.GetBool("MIGRATE_ON_STARTUP"))
+	return *ac.migrateOnStartup
+}
+
+// StrictJSONBinding return if reject JSON body having unknown field get from environment variable (ignored if not set)
+func (ac *appConfig) StrictJSONBinding() bool {
+	if ac.strictJSONBinding != nil {
+		return *ac.strictJSONBinding
+	}
+
+	ac.strictJSONBinding = _bool(viper.GetBool("STRICT_JSON_BINDING"))
+	return *ac.strictJSONBinding
+}
+
+// RedisAddress return address of redis get from environment variable (state is kept in memory if not set)
+func (ac *appConfig) RedisAddress() string {
+	if ac.redisAddress != nil {
+		return *ac.redisAddress
+	}
+
+	ac.redisAddress = _string(viper.GetString("REDIS_ADDRESS"))
+	return *ac.redisAddress
+}
+
+// RedisPassword return password of redis get from environment variable (not authenticated if not set)
+func (ac *appConfig) RedisPassword() string {
+	if ac.redisPassword != nil {
+		return *ac.redisPassword
+	}
+
+	ac.redisPassword = _string(viper.GetString("REDIS_PASSWORD"))
+	return *ac.redisPassword
+}
+
+// RateLimitPerMinute return max request of client to route in a minute get from environment variable (not limited if not set)
+func (ac *appConfig) RateLimitPerMinute() int {
+	if ac.rateLimitPerMinute != nil {
+		return *ac.rateLimitPerMinute
+	}
+
+	ac.rateLimitPerMinute = _int(viper.GetInt("RATE_LIMIT_PER_MINUTE"))
+	return *ac.rateLimitPerMinute
+}
+
+// NameStrictness return strictness of character allowed in name get from environment variable (control if not set)
+func (ac *appConfig) NameStrictness() string {
+	if ac.nameStrictness != nil {
+		return *ac.nameStrictness
+	}
+
+	ac.nameStrictness = _string(strings.ToLower(strings.TrimSpace(viper.GetString("NAME_STRICTNESS"))))
+	return *ac.nameStrictness
+}
+
+// DeployEnv return environment server is deployed in get from environment variable (production if not set)
+// feature only for test (ex, test phone number) is not allowed in production
+func (ac *appConfig) DeployEnv() string {
+	if ac.deployEnv != nil {
+		return *ac.deployEnv
+	}
+
+	env := strings.ToLower(strings.TrimSpace(viper.GetString("DEPLOY_ENV")))
+	switch env {
+	case DeployEnvProduction, DeployEnvStaging, DeployEnvDevelopment:
+		break
+	case "":
+		env = DeployEnvProduction
+	default:
+		log.Fatal("please set DEPLOY_ENV in environment variable to production, staging or development")
+	}
+	ac.deployEnv = &env
+	return *ac.deployEnv
+}
+
+// RequestTimeout return timeout of request to route not sending message get from environment variable (10s if not set)
+func (ac *appConfig) RequestTimeout() time.Duration {
+	if ac.requestTimeout != nil {
+		return *ac.requestTimeout
+	}
+
+	ac.requestTimeout = _duration("REQUEST_TIMEOUT", defaultRequestTimeout)
+	return *ac.requestTimeout
+}
+
+// MessageRequestTimeout return timeout of request to route sending message get from environment variable (30s if not set)
+// it is longer than RequestTimeout, since provider of message (ex, aligo) may be slow & request to it is retried
+func (ac *appConfig) MessageRequestTimeout() time.Duration {
+	if ac.messageRequestTimeout != nil {
+		return *ac.messageRequestTimeout
+	}
+
+	ac.messageRequestTimeout = _duration("MESSAGE_REQUEST_TIMEOUT", defaultMessageRequestTimeout)
+	return *ac.messageRequestTimeout
+}
+
+// TokenRefreshThreshold return remaining duration of token under which client is told to refresh it (5m if not set)
+func (ac *appConfig) TokenRefreshThreshold() time.Duration {
+	if ac.tokenRefreshThreshold != nil {
+		return *ac.tokenRefreshThreshold
+	}
+
+	ac.tokenRefreshThreshold = _duration("TOKEN_REFRESH_THRESHOLD", defaultTokenRefreshThreshold)
+	return *ac.tokenRefreshThreshold
+}
+
+// _duration return duration parsed from environment variable of key, or def if it is not set
+func _duration(key string, def time.Duration) *time.Duration {
+	if viper.GetString(key) == "" {
+		return &def
+	}
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		log.Fatalf("please set %s in environment variable as duration (ex, 10s)", key)
+	}
+	return &d
+}
+
+func _string(s string) *string    { return &s }
+func _int(i int) *int             { return &i }
+func _bool(b bool) *bool          { return &b }
+func _float64(f float64) *float64 { return &f }
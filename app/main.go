@@ -1,23 +1,37 @@
 package main
 
 import (
+	"context"
+	"expvar"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/MyFirstBabyTime/Server/app/config"
+	"github.com/MyFirstBabyTime/Server/clock"
+	"github.com/MyFirstBabyTime/Server/domain"
 	"github.com/MyFirstBabyTime/Server/elasticSearch"
 	"github.com/MyFirstBabyTime/Server/hash"
 	"github.com/MyFirstBabyTime/Server/jwt"
+	"github.com/MyFirstBabyTime/Server/kv"
 	"github.com/MyFirstBabyTime/Server/message"
+	"github.com/MyFirstBabyTime/Server/middleware"
+	"github.com/MyFirstBabyTime/Server/migrate"
 	"github.com/MyFirstBabyTime/Server/parser"
+	"github.com/MyFirstBabyTime/Server/pii"
 	"github.com/MyFirstBabyTime/Server/s3"
 	"github.com/MyFirstBabyTime/Server/tx"
 	"github.com/MyFirstBabyTime/Server/validate"
@@ -57,6 +71,12 @@ func main() {
 	if err != nil {
 		log.Fatal(errors.Wrap(err, "failed to create mysql connection").Error())
 	}
+	// migration is applied before repository is created, so that repository see schema of last version
+	if config.App.MigrateOnStartup() {
+		if err := migrate.Up(db, _authRepo.Migrations()); err != nil {
+			log.Fatal(errors.Wrap(err, "failed to apply migration of auth tables").Error())
+		}
+	}
 
 	s3Ses, err := session.NewSession(&aws.Config{
 		Region:      aws.String(config.App.S3Region()),
@@ -66,35 +86,132 @@ func main() {
 		log.Fatal(errors.Wrap(err, "failed to create aws connection").Error())
 	}
 
-	r := gin.Default()
+	// unknown field is reported in error of binding as json: unknown field "name", and responded with 400
+	binding.EnableDecoderDisallowUnknownFields = config.App.StrictJSONBinding()
+
+	// panic is recovered with response envelope, instead of plain 500 of gin.Recovery in gin.Default
+	r := gin.New()
+	r.Use(gin.Logger(), middleware.Recovery())
+	trustedProxy, err := middleware.TrustedProxy(config.App.TrustedProxies())
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "failed to create trusted proxy middleware").Error())
+	}
+	r.Use(trustedProxy)
+	r.Use(middleware.SecureHeader(middleware.SecureHeaderConfig{
+		HSTSMaxAge:    time.Hour * 24 * 365,
+		RedirectHTTPS: config.App.ForceHTTPS(),
+	}, []string{"/ping"}))
 
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Authorization", "authorization", "Request-Security")
+	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Authorization", "authorization", "Request-Security", "X-CSRF-Token")
 
 	r.Use(cors.New(corsConfig))
+	// token in cookie is sent by browser automatically, so request changing state with it require CSRF token
+	r.Use(middleware.CSRF(middleware.CSRFConfig{
+		Enabled:    config.App.TokenCookieMode() == jwt.TokenInCookie || config.App.TokenCookieMode() == jwt.TokenInBoth,
+		AuthCookie: jwt.AccessTokenCookie,
+	}))
+
+	// only non-critical route is shed under overload, and critical one (ex, login) is preserved
+	r.Use(middleware.LoadShedder(middleware.LoadShedConfig{
+		MaxInFlight:  int64(config.App.LoadShedMaxInFlight()),
+		DBLatencySLO: config.App.LoadShedDBLatencySLO(),
+		RetryAfter:   time.Second * 5,
+	}, middleware.MonitorDBLatency(db, time.Second), []string{
+		"/api/" + _authHttpDelivery.APIVersion + "/phones/phone-number/:phone_number/certify-code",
+		"/api/" + _authHttpDelivery.APIVersion + "/phones/certify-code",
+		"/api/" + _authHttpDelivery.APIVersion + "/phones/phone-number/:phone_number/eligibility",
+		"/api/" + _authHttpDelivery.APIVersion + "/parents/id/:parent_id/existence",
+	}))
+
+	// route sending message wait provider longer, so that slow provider doesn't fail it while login fail fast
+	r.Use(middleware.Timeout(middleware.TimeoutConfig{
+		Default: config.App.RequestTimeout(),
+		Routes: map[string]time.Duration{
+			"/api/" + _authHttpDelivery.APIVersion + "/phones/phone-number/:phone_number/certify-code":                config.App.MessageRequestTimeout(),
+			"/api/" + _authHttpDelivery.APIVersion + "/phones/certify-code":                                           config.App.MessageRequestTimeout(),
+			"/api/" + _authHttpDelivery.APIVersion + "/phones/phone-number/:phone_number/certify-code/reverification": config.App.MessageRequestTimeout(),
+			"/api/" + _authHttpDelivery.APIVersion + "/parents/me/step-up/certify-code":                               config.App.MessageRequestTimeout(),
+		},
+	}))
+
+	_clock := clock.Real()
+	// state of rate limit & cooldown is shared across instances in redis if set, or kept in memory for single node
+	var _kv kv.Store = kv.Memory(_clock)
+	if addr := config.App.RedisAddress(); addr != "" {
+		_kv = kv.Redis(addr, config.App.RedisPassword(), 10)
+	}
+	r.Use(middleware.RateLimiter(middleware.RateLimitConfig{
+		Limit:  int64(config.App.RateLimitPerMinute()),
+		Window: time.Minute,
+	}, _kv, []string{
+		"/api/" + _authHttpDelivery.APIVersion + "/phones/phone-number/:phone_number/certify-code",
+		"/api/" + _authHttpDelivery.APIVersion + "/phones/certify-code",
+		"/api/" + _authHttpDelivery.APIVersion + "/phones/phone-number/:phone_number/certification",
+		"/api/" + _authHttpDelivery.APIVersion + "/login/parent",
+		"/api/" + _authHttpDelivery.APIVersion + "/parents/id/:parent_id/reservation",
+		"/api/" + _authHttpDelivery.APIVersion + "/parents/me/step-up/certify-code",
+		"/api/" + _authHttpDelivery.APIVersion + "/parents/me/step-up",
+	}))
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"message": "pong",
 		})
 	})
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(middleware.NoRoute)
+	r.NoMethod(middleware.NoMethod)
 
 	_ps := parser.MysqlMsgParser()
 	_vl := validate.New()
+	if err := _vl.SetTextStrictness(config.App.NameStrictness()); err != nil {
+		log.Fatal(errors.Wrap(err, "please set NAME_STRICTNESS in environment variable to control or html").Error())
+	}
 	_tx := tx.NewSqlxHandler(db)
-	_msg := message.AligoAgent(config.App.AligoAPIKey(), config.App.AligoAccountID(), config.App.AligoSender())
-	_hash := hash.BcryptHandler()
-	_jwt := jwt.UUIDHandler(config.App.JwtKey())
+	// aligo send message only to domestic number, and provider for international number is not registered yet
+	_aligo := message.AligoAgent(config.App.AligoAPIKey(), config.App.AligoAccountID(), config.App.AligoSender())
+	_msg := message.RegionRouter().
+		Register(message.RegionDomestic, "aligo", message.Retried(message.RateLimited(_aligo, config.App.AligoQPS()), 3, time.Millisecond*200))
+	_hash := hash.BcryptHandler(config.App.PasswordPepper())
+	_pnc, err := pii.PhoneNumberCipher(config.App.PhoneNumberHMACKey(), config.App.PhoneNumberEncryptionKey())
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "please set PHONE_NUMBER_HMAC_KEY & PHONE_NUMBER_ENCRYPTION_KEY to key encoded in base64").Error())
+	}
+	_jwt := jwt.UUIDHandler(config.App.JwtKey(), _clock)
+	if err := _jwt.CheckSigningKey(); err != nil {
+		log.Fatal(errors.Wrap(err, "jwt key is not available to sign token").Error())
+	}
+	if err := _jwt.SetTokenCookieMode(config.App.TokenCookieMode()); err != nil {
+		log.Fatal(errors.Wrap(err, "please set TOKEN_COOKIE_MODE in environment variable to body, cookie or both").Error())
+	}
+	_jwt.SetRefreshThreshold(config.App.TokenRefreshThreshold())
 	_s3 := s3.New(s3Ses)
 	_es := elasticSearch.New(config.App.EsEndPoint())
 
+	// test phone is certified without proving ownership, so it must never be available in production
+	if len(_authConfig.App.TestPhoneNumbers()) > 0 && config.App.DeployEnv() == config.DeployEnvProduction {
+		log.Fatal("test phone number is not allowed in production, please unset auth.testPhoneNumbers or set DEPLOY_ENV")
+	}
 	au := _authUcase.AuthUsecase(
 		_authConfig.App,
-		_authRepo.ParentAuthRepository(_authConfig.App, db, _ps, _vl),
-		_authRepo.ParentPhoneCertifyRepository(_authConfig.App, db, _ps, _vl),
-		_tx, _msg, _hash, _jwt, _s3,
+		_authRepo.ParentAuthRepository(_authConfig.App, db, _ps, _vl, _pnc),
+		_authRepo.ParentPhoneCertifyRepository(_authConfig.App, db, _ps, _vl, _pnc),
+		_authRepo.ParentSessionRepository(_authConfig.App, db, _ps, _vl),
+		_authRepo.AuditLogRepository(_authConfig.App, db, _vl, _pnc),
+		_authRepo.ParentWebAuthnCredentialRepository(_authConfig.App, db, _ps, _vl),
+		_authRepo.WebAuthnCeremonyRepository(_authConfig.App, db, _ps, _vl),
+		_authRepo.DeviceVerificationRepository(_authConfig.App, db, _ps, _vl),
+		_authRepo.AuthEventRepository(_authConfig.App, db, _vl),
+		_authRepo.ParentNotificationPreferenceRepository(_authConfig.App, db, _ps, _vl),
+		_authRepo.PhoneCertifyEventRepository(_authConfig.App, db, _vl),
+		_tx, _msg, _hash, _jwt, _s3, nil, nil, _clock, _kv, hash.PwnedChecker(time.Second*2), _pnc,
 	)
-	_authHttpDelivery.NewAuthHandler(r, au, _vl, _jwt)
+	_jwt.SetAccountChecker(au)
+	_jwt.SetRequirePhoneCertified(_authConfig.App.RequirePhoneCertification())
+	_authHttpDelivery.NewAuthHandler(r.Group("api/"+_authHttpDelivery.APIVersion), au, _vl, _jwt)
+	// metric exposes internal state (ex, count of failed message), so it is served only to admin
+	r.GET("/debug/vars", _jwt.ParseUUIDFromToken, _jwt.RequireRole(domain.ParentRoleAdmin), gin.WrapH(expvar.Handler()))
 
 	eu := _expenditureUcase.ExpenditureUsecase(
 		_expenditureRepo.ExpenditureRepository(db, _ps, _vl),
@@ -113,5 +230,39 @@ func main() {
 	)
 	_childrenHttpDelivery.NewChildrenHandler(r, cu, _vl, _jwt)
 
-	log.Fatal(r.Run(":80"))
+	// http.Server is used rather than gin.Engine.Run, so that request in flight is finished before dependencies are released
+	// (client IP is resolved in TrustedProxy middleware, so trusted proxies prepared only in Run are not needed)
+	srv := &http.Server{Addr: ":80", Handler: r}
+	served := make(chan error, 1)
+	go func() { served <- srv.ListenAndServe() }()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	var serveErr error
+	select {
+	case <-quit:
+		// request is bounded by Timeout middleware, so shutdown wait a little longer than longest route timeout
+		ctx, cancel := context.WithTimeout(context.Background(), config.App.MessageRequestTimeout()+time.Second*5)
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Println(errors.Wrap(err, "failed to shut down http server gracefully").Error())
+		}
+		cancel()
+	case serveErr = <-served:
+	}
+	if serveErr != nil && serveErr != http.ErrServerClosed {
+		log.Println(errors.Wrap(serveErr, "failed to serve http").Error())
+	}
+
+	// dependencies built once above are released after server is shut down, in reverse order of dependency
+	if err := _authRepo.CloseStatements(); err != nil {
+		log.Println(errors.Wrap(err, "failed to close prepared statement").Error())
+	}
+	_aligo.Close()
+	_kv.Close()
+	if err := db.Close(); err != nil {
+		log.Println(errors.Wrap(err, "failed to close mysql connection").Error())
+	}
+	if serveErr != nil && serveErr != http.ErrServerClosed {
+		os.Exit(1)
+	}
 }
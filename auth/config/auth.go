@@ -2,7 +2,10 @@ package config
 
 import (
 	"github.com/spf13/viper"
+	"strings"
 	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
 )
 
 // App is the application config about syscheck domain
@@ -21,14 +24,217 @@ type authConfig struct {
 
 	// parentProfileS3Bucket represent aws s3 bucket for parent profile
 	parentProfileS3Bucket *string
+
+	// parentIDChangeInterval represent minimum interval between parent ID change (not limited if 0)
+	parentIDChangeInterval *time.Duration
+
+	// phoneStatusBatchSize represent max number of phone number in one certify status lookup
+	phoneStatusBatchSize *int
+
+	// certifyMessageTemplates represent template of certify code message for each purpose, having {code} placeholder
+	certifyMessageTemplates map[domain.CertifyPurpose]string
+
+	// certifyCodeCharset represent character set which certify code is generated from
+	certifyCodeCharset *string
+
+	// certifyCodeLength represent length of certify code
+	certifyCodeLength *int
+
+	// newDeviceLoginNotification represent if notify to parent phone when parent logged in from new device
+	newDeviceLoginNotification *bool
+
+	// parentDataExportInterval represent minimum interval between parent data export (not limited if 0)
+	parentDataExportInterval *time.Duration
+
+	// loginLockoutThreshold represent number of consecutive failed login locking account (not locked if 0)
+	loginLockoutThreshold *int
+
+	// loginLockoutDuration represent duration for which account is locked
+	loginLockoutDuration *time.Duration
+
+	// lockoutNotification represent if notify to parent phone when account is locked
+	lockoutNotification *bool
+
+	// lockoutNotificationInterval represent minimum interval between lockout notification to same parent
+	lockoutNotificationInterval *time.Duration
+
+	// certifyCodeMismatchAlertThreshold represent count of certify code mismatch in window firing alert (not alerted if 0)
+	certifyCodeMismatchAlertThreshold *int
+
+	// certifyCodeMismatchAlertWindow represent window in which certify code mismatch is counted for alert
+	certifyCodeMismatchAlertWindow *time.Duration
+
+	// requirePhoneCertification represent if certified phone is required to sign up
+	requirePhoneCertification *bool
+
+	// uncertifiedPhoneDetail represent if state of certify code is responded in sign up with uncertified phone
+	uncertifiedPhoneDetail *bool
+
+	// webAuthnCeremonyTimeout represent duration in which passkey ceremony must be finished after begun
+	webAuthnCeremonyTimeout *time.Duration
+
+	// stepUpTokenDuration represent time valid duration for elevated token issued with step-up auth
+	stepUpTokenDuration *time.Duration
+
+	// newDeviceVerification represent if login from unrecognized device require certify code sent to parent phone
+	newDeviceVerification *bool
+
+	// deviceVerificationTimeout represent duration in which device verification must be completed after login
+	deviceVerificationTimeout *time.Duration
+
+	// idempotentPhoneCertify represent if correct code submitted again to phone certified already is treated as success
+	idempotentPhoneCertify *bool
+
+	// welcomeMessage represent if welcome message is sent to parent phone after sign up
+	welcomeMessage *bool
+
+	// welcomeMessageTemplate represent template of welcome message, having {name} placeholder
+	welcomeMessageTemplate *string
+
+	// passwordBreachCheck represent if password found in data breach is rejected in sign up & password change
+	passwordBreachCheck *bool
+
+	// maxAccountsPerPhone represent max number of account created with one phone number in window (not limited if 0)
+	maxAccountsPerPhone *int
+
+	// accountsPerPhoneWindow represent window in which account created with one phone number is counted
+	accountsPerPhoneWindow *time.Duration
+
+	// passwordVerifyAttemptLimit represent max number of password mismatch in verification in window (not limited if 0)
+	passwordVerifyAttemptLimit *int
+
+	// passwordVerifyAttemptWindow represent window in which password mismatch in verification is counted
+	passwordVerifyAttemptWindow *time.Duration
+
+	// parentIDReservationTTL represent duration for which parent ID is held by reservation in multi-step sign up
+	parentIDReservationTTL *time.Duration
+
+	// parentIDReservationLimit represent max number of parent ID reserved by one client IP in window (not limited if 0)
+	parentIDReservationLimit *int
+
+	// parentIDReservationWindow represent window in which parent ID reserved by one client IP is counted
+	parentIDReservationWindow *time.Duration
+
+	// accountStatusCacheTTL represent duration for which account status checked with token is cached
+	accountStatusCacheTTL *time.Duration
+
+	// testPhoneNumbers represent phone number certified with fixed code without sending message (for review & E2E)
+	testPhoneNumbers []string
+
+	// testPhoneCertifyCode represent fixed certify code of test phone numbers
+	testPhoneCertifyCode *string
+
+	// maintenanceMode represent if maintenance mode is on at start, until it is changed by admin
+	maintenanceMode *bool
+
+	// maintenanceDisabledOperations represent operation rejected while maintenance mode is on
+	maintenanceDisabledOperations []string
+
+	// maxParentSessions represent max number of active session of one parent (not limited if 0)
+	// it is advisory, since access token isn't revoked with session (token of evicted session is valid until expired)
+	maxParentSessions *int
+
+	// sessionLimitPolicy represent how login exceeding maxParentSessions is handled (reject, evict_oldest)
+	// evict_oldest delete row of least recently used session, but doesn't log out device of it before token expire
+	sessionLimitPolicy *string
+
+	// sessionEvictionNotification represent if notify to parent phone when session is evicted by login of other device
+	sessionEvictionNotification *bool
+
+	// certifyChannels represent channel which certify code can be sent through (sms, voice, email)
+	certifyChannels []string
+
+	// codeVerifyAttemptLimit represent max number of certify code mismatch in verification in window (not limited if 0)
+	codeVerifyAttemptLimit *int
+
+	// codeVerifyAttemptWindow represent window in which certify code mismatch in verification is counted
+	codeVerifyAttemptWindow *time.Duration
+
+	// restartCertificationLimit represent max number of restarting certification of one phone in window (not limited if 0)
+	restartCertificationLimit *int
+
+	// restartCertificationWindow represent window in which restarting certification of one phone is counted
+	restartCertificationWindow *time.Duration
 }
 
 // default const value about authConfig field
 const (
-	defaultAccessTokenDuration   = time.Hour * 24
-	defaultParentProfileS3Bucket = "first-baby-time"
+	defaultAccessTokenDuration    = time.Hour * 24
+	defaultParentProfileS3Bucket  = "first-baby-time"
+	defaultParentIDChangeInterval = time.Hour * 24 * 30
+	defaultPhoneStatusBatchSize   = 100
+
+	defaultCertifyCodeMode   = "numeric"
+	defaultCertifyCodeLength = 6
+
+	defaultNewDeviceLoginNotification = false
+
+	defaultParentDataExportInterval = time.Hour * 24
+
+	defaultLoginLockoutThreshold       = 5
+	defaultLoginLockoutDuration        = time.Minute * 15
+	defaultLockoutNotification         = true
+	defaultLockoutNotificationInterval = time.Hour
+
+	defaultCertifyCodeMismatchAlertThreshold = 100
+	defaultCertifyCodeMismatchAlertWindow    = time.Minute
+
+	defaultRequirePhoneCertification = true
+	defaultUncertifiedPhoneDetail    = true
+	defaultWebAuthnCeremonyTimeout   = time.Minute * 5
+
+	defaultStepUpTokenDuration = time.Minute * 5
+
+	defaultNewDeviceVerification     = false
+	defaultDeviceVerificationTimeout = time.Minute * 5
+
+	defaultIdempotentPhoneCertify = false
+
+	defaultWelcomeMessage         = false
+	defaultWelcomeMessageTemplate = "[육아는 처음이지] {name}님, 가입을 환영합니다!"
+
+	defaultPasswordBreachCheck = false
+
+	defaultMaxAccountsPerPhone    = 0
+	defaultAccountsPerPhoneWindow = time.Hour * 24 * 30
+
+	defaultPasswordVerifyAttemptLimit  = 10
+	defaultPasswordVerifyAttemptWindow = time.Minute * 15
+
+	defaultMaintenanceMode = false
+
+	defaultParentIDReservationTTL    = time.Minute * 10
+	defaultParentIDReservationLimit  = 10
+	defaultParentIDReservationWindow = time.Hour
+
+	defaultAccountStatusCacheTTL = time.Second * 10
+
+	defaultMaxParentSessions           = 0
+	defaultSessionLimitPolicy          = "reject"
+	defaultSessionEvictionNotification = true
+
+	defaultCodeVerifyAttemptLimit  = 5
+	defaultCodeVerifyAttemptWindow = time.Minute * 15
+
+	defaultRestartCertificationLimit  = 5
+	defaultRestartCertificationWindow = time.Hour
 )
 
+// defaultMaintenanceDisabledOperations is default value of maintenanceDisabledOperations field
+var defaultMaintenanceDisabledOperations = []string{"sign_up", "send_certify_code"}
+
+// defaultCertifyChannels is default value of certifyChannels field (aligo support only SMS)
+var defaultCertifyChannels = []string{"sms"}
+
+// defaultCertifyMessageTemplates is default value of certifyMessageTemplates field
+var defaultCertifyMessageTemplates = map[domain.CertifyPurpose]string{
+	domain.CertifyPurposeSignUp:             "[육아는 처음이지 인증 번호]\n회원가입 인증 번호: {code}",
+	domain.CertifyPurposePasswordReset:      "[육아는 처음이지 인증 번호]\n비밀번호 재설정 인증 번호: {code}",
+	domain.CertifyPurposePhoneChange:        "[육아는 처음이지 인증 번호]\n휴대폰 번호 변경 인증 번호: {code}",
+	domain.CertifyPurposeStepUp:             "[육아는 처음이지 인증 번호]\n본인 확인 인증 번호: {code}",
+	domain.CertifyPurposeDeviceVerification: "[육아는 처음이지 인증 번호]\n새로운 기기 로그인 인증 번호: {code}",
+}
+
 // AccessTokenDuration return access token valid duration
 func (ac *authConfig) AccessTokenDuration() time.Duration {
 	var key = "auth.accessTokenDuration"
@@ -58,4 +264,627 @@ func (ac *authConfig) ParentProfileS3Bucket() string {
 	return *ac.parentProfileS3Bucket
 }
 
+// ParentIDChangeInterval implement ParentIDChangeInterval of authUsecaseConfig
+func (ac *authConfig) ParentIDChangeInterval() time.Duration {
+	var key = "auth.parentIDChangeInterval"
+	if ac.parentIDChangeInterval != nil {
+		return *ac.parentIDChangeInterval
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultParentIDChangeInterval.String())
+		d = defaultParentIDChangeInterval
+	}
+
+	ac.parentIDChangeInterval = &d
+	return *ac.parentIDChangeInterval
+}
+
+// ParentDataExportInterval implement ParentDataExportInterval of authUsecaseConfig
+func (ac *authConfig) ParentDataExportInterval() time.Duration {
+	var key = "auth.parentDataExportInterval"
+	if ac.parentDataExportInterval != nil {
+		return *ac.parentDataExportInterval
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultParentDataExportInterval.String())
+		d = defaultParentDataExportInterval
+	}
+
+	ac.parentDataExportInterval = &d
+	return *ac.parentDataExportInterval
+}
+
+// PhoneStatusBatchSize implement PhoneStatusBatchSize of authUsecaseConfig
+func (ac *authConfig) PhoneStatusBatchSize() int {
+	var key = "auth.phoneStatusBatchSize"
+	if ac.phoneStatusBatchSize == nil {
+		if v := viper.GetInt(key); v > 0 {
+			ac.phoneStatusBatchSize = _int(v)
+		} else {
+			viper.Set(key, defaultPhoneStatusBatchSize)
+			ac.phoneStatusBatchSize = _int(defaultPhoneStatusBatchSize)
+		}
+	}
+	return *ac.phoneStatusBatchSize
+}
+
+// CertifyMessageTemplate implement CertifyMessageTemplate of authUsecaseConfig
+// template of sign up is read from auth.certifyMessageTemplate if not set per purpose, for backward compatibility
+func (ac *authConfig) CertifyMessageTemplate(purpose domain.CertifyPurpose) string {
+	var key = "auth.certifyMessageTemplates." + string(purpose)
+	if ac.certifyMessageTemplates == nil {
+		ac.certifyMessageTemplates = map[domain.CertifyPurpose]string{}
+	}
+	if _, ok := ac.certifyMessageTemplates[purpose]; !ok {
+		if _, ok := viper.Get(key).(string); !ok {
+			if legacy, ok := viper.Get("auth.certifyMessageTemplate").(string); ok && purpose == domain.CertifyPurposeSignUp {
+				viper.Set(key, legacy)
+			} else {
+				viper.Set(key, defaultCertifyMessageTemplates[purpose])
+			}
+		}
+		ac.certifyMessageTemplates[purpose] = viper.GetString(key)
+	}
+	return ac.certifyMessageTemplates[purpose]
+}
+
+// CertifyCodeCharset implement CertifyCodeCharset of authUsecaseConfig
+// charset is decided by auth.certifyCodeMode, which is numeric (kept for backward compatibility) or alphanumeric
+func (ac *authConfig) CertifyCodeCharset() string {
+	var key = "auth.certifyCodeMode"
+	if ac.certifyCodeCharset == nil {
+		switch viper.GetString(key) {
+		case "alphanumeric":
+			ac.certifyCodeCharset = _string(domain.CertifyCodeAlphanumericCharset)
+		case "numeric":
+			ac.certifyCodeCharset = _string(domain.CertifyCodeNumericCharset)
+		default:
+			viper.Set(key, defaultCertifyCodeMode)
+			ac.certifyCodeCharset = _string(domain.CertifyCodeNumericCharset)
+		}
+	}
+	return *ac.certifyCodeCharset
+}
+
+// CertifyCodeLength implement CertifyCodeLength of authUsecaseConfig (4 ~ 10, fit in certify_code column)
+func (ac *authConfig) CertifyCodeLength() int {
+	var key = "auth.certifyCodeLength"
+	if ac.certifyCodeLength == nil {
+		if v := viper.GetInt(key); v >= 4 && v <= 10 {
+			ac.certifyCodeLength = _int(v)
+		} else {
+			viper.Set(key, defaultCertifyCodeLength)
+			ac.certifyCodeLength = _int(defaultCertifyCodeLength)
+		}
+	}
+	return *ac.certifyCodeLength
+}
+
+// NewDeviceLoginNotification implement NewDeviceLoginNotification of authUsecaseConfig
+func (ac *authConfig) NewDeviceLoginNotification() bool {
+	var key = "auth.newDeviceLoginNotification"
+	if ac.newDeviceLoginNotification == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultNewDeviceLoginNotification)
+		}
+		ac.newDeviceLoginNotification = _bool(viper.GetBool(key))
+	}
+	return *ac.newDeviceLoginNotification
+}
+
+// LoginLockoutThreshold implement LoginLockoutThreshold of authUsecaseConfig
+func (ac *authConfig) LoginLockoutThreshold() int {
+	var key = "auth.loginLockoutThreshold"
+	if ac.loginLockoutThreshold == nil {
+		if v := viper.GetInt(key); viper.IsSet(key) && v >= 0 {
+			ac.loginLockoutThreshold = _int(v)
+		} else {
+			viper.Set(key, defaultLoginLockoutThreshold)
+			ac.loginLockoutThreshold = _int(defaultLoginLockoutThreshold)
+		}
+	}
+	return *ac.loginLockoutThreshold
+}
+
+// LoginLockoutDuration implement LoginLockoutDuration of authUsecaseConfig
+func (ac *authConfig) LoginLockoutDuration() time.Duration {
+	var key = "auth.loginLockoutDuration"
+	if ac.loginLockoutDuration != nil {
+		return *ac.loginLockoutDuration
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || d <= 0 {
+		viper.Set(key, defaultLoginLockoutDuration.String())
+		d = defaultLoginLockoutDuration
+	}
+
+	ac.loginLockoutDuration = &d
+	return *ac.loginLockoutDuration
+}
+
+// LockoutNotification implement LockoutNotification of authUsecaseConfig
+func (ac *authConfig) LockoutNotification() bool {
+	var key = "auth.lockoutNotification"
+	if ac.lockoutNotification == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultLockoutNotification)
+		}
+		ac.lockoutNotification = _bool(viper.GetBool(key))
+	}
+	return *ac.lockoutNotification
+}
+
+// LockoutNotificationInterval implement LockoutNotificationInterval of authUsecaseConfig
+func (ac *authConfig) LockoutNotificationInterval() time.Duration {
+	var key = "auth.lockoutNotificationInterval"
+	if ac.lockoutNotificationInterval != nil {
+		return *ac.lockoutNotificationInterval
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultLockoutNotificationInterval.String())
+		d = defaultLockoutNotificationInterval
+	}
+
+	ac.lockoutNotificationInterval = &d
+	return *ac.lockoutNotificationInterval
+}
+
+// CertifyCodeMismatchAlertThreshold implement CertifyCodeMismatchAlertThreshold of authUsecaseConfig
+func (ac *authConfig) CertifyCodeMismatchAlertThreshold() int {
+	var key = "auth.certifyCodeMismatchAlertThreshold"
+	if ac.certifyCodeMismatchAlertThreshold == nil {
+		if v := viper.GetInt(key); viper.IsSet(key) && v >= 0 {
+			ac.certifyCodeMismatchAlertThreshold = _int(v)
+		} else {
+			viper.Set(key, defaultCertifyCodeMismatchAlertThreshold)
+			ac.certifyCodeMismatchAlertThreshold = _int(defaultCertifyCodeMismatchAlertThreshold)
+		}
+	}
+	return *ac.certifyCodeMismatchAlertThreshold
+}
+
+// CertifyCodeMismatchAlertWindow implement CertifyCodeMismatchAlertWindow of authUsecaseConfig
+func (ac *authConfig) CertifyCodeMismatchAlertWindow() time.Duration {
+	var key = "auth.certifyCodeMismatchAlertWindow"
+	if ac.certifyCodeMismatchAlertWindow != nil {
+		return *ac.certifyCodeMismatchAlertWindow
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || d <= 0 {
+		viper.Set(key, defaultCertifyCodeMismatchAlertWindow.String())
+		d = defaultCertifyCodeMismatchAlertWindow
+	}
+
+	ac.certifyCodeMismatchAlertWindow = &d
+	return *ac.certifyCodeMismatchAlertWindow
+}
+
+// RequirePhoneCertification implement RequirePhoneCertification of authUsecaseConfig
+// it can be false in deployment that verify parent on its own (ex, B2B), but then anyone can sign up with phone
+// number of others (phone is not proved to be owned), so phone must not be trusted as identity of parent
+func (ac *authConfig) RequirePhoneCertification() bool {
+	var key = "auth.requirePhoneCertification"
+	if ac.requirePhoneCertification == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultRequirePhoneCertification)
+		}
+		ac.requirePhoneCertification = _bool(viper.GetBool(key))
+	}
+	return *ac.requirePhoneCertification
+}
+
+// UncertifiedPhoneDetail implement UncertifiedPhoneDetail of authUsecaseConfig
+// it can be false to hide if certify code was sent to phone, from client not proved to own it
+func (ac *authConfig) UncertifiedPhoneDetail() bool {
+	var key = "auth.uncertifiedPhoneDetail"
+	if ac.uncertifiedPhoneDetail == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultUncertifiedPhoneDetail)
+		}
+		ac.uncertifiedPhoneDetail = _bool(viper.GetBool(key))
+	}
+	return *ac.uncertifiedPhoneDetail
+}
+
+// WebAuthnCeremonyTimeout implement WebAuthnCeremonyTimeout of authUsecaseConfig
+func (ac *authConfig) WebAuthnCeremonyTimeout() time.Duration {
+	var key = "auth.webAuthnCeremonyTimeout"
+	if ac.webAuthnCeremonyTimeout != nil {
+		return *ac.webAuthnCeremonyTimeout
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || d <= 0 {
+		viper.Set(key, defaultWebAuthnCeremonyTimeout.String())
+		d = defaultWebAuthnCeremonyTimeout
+	}
+
+	ac.webAuthnCeremonyTimeout = &d
+	return *ac.webAuthnCeremonyTimeout
+}
+
+// StepUpTokenDuration implement StepUpTokenDuration of authUsecaseConfig
+func (ac *authConfig) StepUpTokenDuration() time.Duration {
+	var key = "auth.stepUpTokenDuration"
+	if ac.stepUpTokenDuration != nil {
+		return *ac.stepUpTokenDuration
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || d <= 0 {
+		viper.Set(key, defaultStepUpTokenDuration.String())
+		d = defaultStepUpTokenDuration
+	}
+
+	ac.stepUpTokenDuration = &d
+	return *ac.stepUpTokenDuration
+}
+
+// NewDeviceVerification implement NewDeviceVerification of authUsecaseConfig
+func (ac *authConfig) NewDeviceVerification() bool {
+	var key = "auth.newDeviceVerification"
+	if ac.newDeviceVerification == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultNewDeviceVerification)
+		}
+		ac.newDeviceVerification = _bool(viper.GetBool(key))
+	}
+	return *ac.newDeviceVerification
+}
+
+// DeviceVerificationTimeout implement DeviceVerificationTimeout of authUsecaseConfig
+func (ac *authConfig) DeviceVerificationTimeout() time.Duration {
+	var key = "auth.deviceVerificationTimeout"
+	if ac.deviceVerificationTimeout != nil {
+		return *ac.deviceVerificationTimeout
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || d <= 0 {
+		viper.Set(key, defaultDeviceVerificationTimeout.String())
+		d = defaultDeviceVerificationTimeout
+	}
+
+	ac.deviceVerificationTimeout = &d
+	return *ac.deviceVerificationTimeout
+}
+
+// IdempotentPhoneCertify implement IdempotentPhoneCertify of authUsecaseConfig
+func (ac *authConfig) IdempotentPhoneCertify() bool {
+	var key = "auth.idempotentPhoneCertify"
+	if ac.idempotentPhoneCertify == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultIdempotentPhoneCertify)
+		}
+		ac.idempotentPhoneCertify = _bool(viper.GetBool(key))
+	}
+	return *ac.idempotentPhoneCertify
+}
+
+// WelcomeMessage implement WelcomeMessage of authUsecaseConfig
+func (ac *authConfig) WelcomeMessage() bool {
+	var key = "auth.welcomeMessage"
+	if ac.welcomeMessage == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultWelcomeMessage)
+		}
+		ac.welcomeMessage = _bool(viper.GetBool(key))
+	}
+	return *ac.welcomeMessage
+}
+
+// WelcomeMessageTemplate implement WelcomeMessageTemplate of authUsecaseConfig
+func (ac *authConfig) WelcomeMessageTemplate() string {
+	var key = "auth.welcomeMessageTemplate"
+	if ac.welcomeMessageTemplate == nil {
+		if _, ok := viper.Get(key).(string); !ok {
+			viper.Set(key, defaultWelcomeMessageTemplate)
+		}
+		ac.welcomeMessageTemplate = _string(viper.GetString(key))
+	}
+	return *ac.welcomeMessageTemplate
+}
+
+// PasswordBreachCheck implement PasswordBreachCheck of authUsecaseConfig
+func (ac *authConfig) PasswordBreachCheck() bool {
+	var key = "auth.passwordBreachCheck"
+	if ac.passwordBreachCheck == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultPasswordBreachCheck)
+		}
+		ac.passwordBreachCheck = _bool(viper.GetBool(key))
+	}
+	return *ac.passwordBreachCheck
+}
+
+// MaxAccountsPerPhone implement MaxAccountsPerPhone of authUsecaseConfig
+func (ac *authConfig) MaxAccountsPerPhone() int {
+	var key = "auth.maxAccountsPerPhone"
+	if ac.maxAccountsPerPhone == nil {
+		if _, ok := viper.Get(key).(int); !ok {
+			viper.Set(key, defaultMaxAccountsPerPhone)
+		}
+		ac.maxAccountsPerPhone = _int(viper.GetInt(key))
+	}
+	return *ac.maxAccountsPerPhone
+}
+
+// AccountsPerPhoneWindow implement AccountsPerPhoneWindow of authUsecaseConfig
+func (ac *authConfig) AccountsPerPhoneWindow() time.Duration {
+	var key = "auth.accountsPerPhoneWindow"
+	if ac.accountsPerPhoneWindow != nil {
+		return *ac.accountsPerPhoneWindow
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultAccountsPerPhoneWindow.String())
+		d = defaultAccountsPerPhoneWindow
+	}
+	ac.accountsPerPhoneWindow = &d
+	return *ac.accountsPerPhoneWindow
+}
+
+// PasswordVerifyAttemptLimit implement PasswordVerifyAttemptLimit of authUsecaseConfig
+func (ac *authConfig) PasswordVerifyAttemptLimit() int {
+	var key = "auth.passwordVerifyAttemptLimit"
+	if ac.passwordVerifyAttemptLimit == nil {
+		if _, ok := viper.Get(key).(int); !ok {
+			viper.Set(key, defaultPasswordVerifyAttemptLimit)
+		}
+		ac.passwordVerifyAttemptLimit = _int(viper.GetInt(key))
+	}
+	return *ac.passwordVerifyAttemptLimit
+}
+
+// PasswordVerifyAttemptWindow implement PasswordVerifyAttemptWindow of authUsecaseConfig
+func (ac *authConfig) PasswordVerifyAttemptWindow() time.Duration {
+	var key = "auth.passwordVerifyAttemptWindow"
+	if ac.passwordVerifyAttemptWindow != nil {
+		return *ac.passwordVerifyAttemptWindow
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultPasswordVerifyAttemptWindow.String())
+		d = defaultPasswordVerifyAttemptWindow
+	}
+	ac.passwordVerifyAttemptWindow = &d
+	return *ac.passwordVerifyAttemptWindow
+}
+
+// ParentIDReservationTTL implement ParentIDReservationTTL of authUsecaseConfig
+func (ac *authConfig) ParentIDReservationTTL() time.Duration {
+	var key = "auth.parentIDReservationTTL"
+	if ac.parentIDReservationTTL != nil {
+		return *ac.parentIDReservationTTL
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || d <= 0 {
+		viper.Set(key, defaultParentIDReservationTTL.String())
+		d = defaultParentIDReservationTTL
+	}
+	ac.parentIDReservationTTL = &d
+	return *ac.parentIDReservationTTL
+}
+
+// ParentIDReservationLimit implement ParentIDReservationLimit of authUsecaseConfig
+func (ac *authConfig) ParentIDReservationLimit() int {
+	var key = "auth.parentIDReservationLimit"
+	if ac.parentIDReservationLimit == nil {
+		if _, ok := viper.Get(key).(int); !ok {
+			viper.Set(key, defaultParentIDReservationLimit)
+		}
+		ac.parentIDReservationLimit = _int(viper.GetInt(key))
+	}
+	return *ac.parentIDReservationLimit
+}
+
+// ParentIDReservationWindow implement ParentIDReservationWindow of authUsecaseConfig
+func (ac *authConfig) ParentIDReservationWindow() time.Duration {
+	var key = "auth.parentIDReservationWindow"
+	if ac.parentIDReservationWindow != nil {
+		return *ac.parentIDReservationWindow
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultParentIDReservationWindow.String())
+		d = defaultParentIDReservationWindow
+	}
+	ac.parentIDReservationWindow = &d
+	return *ac.parentIDReservationWindow
+}
+
+// AccountStatusCacheTTL implement AccountStatusCacheTTL of authUsecaseConfig
+func (ac *authConfig) AccountStatusCacheTTL() time.Duration {
+	var key = "auth.accountStatusCacheTTL"
+	if ac.accountStatusCacheTTL != nil {
+		return *ac.accountStatusCacheTTL
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultAccountStatusCacheTTL.String())
+		d = defaultAccountStatusCacheTTL
+	}
+	ac.accountStatusCacheTTL = &d
+	return *ac.accountStatusCacheTTL
+}
+
+// TestPhoneNumbers implement TestPhoneNumbers of authUsecaseConfig
+// test phone is not available in production deployment, which is checked in server bootstrap
+func (ac *authConfig) TestPhoneNumbers() []string {
+	var key = "auth.testPhoneNumbers"
+	if ac.testPhoneNumbers == nil {
+		ac.testPhoneNumbers = []string{}
+		for _, pn := range viper.GetStringSlice(key) {
+			if pn = strings.TrimSpace(pn); pn != "" {
+				ac.testPhoneNumbers = append(ac.testPhoneNumbers, pn)
+			}
+		}
+	}
+	return ac.testPhoneNumbers
+}
+
+// TestPhoneCertifyCode implement TestPhoneCertifyCode of authUsecaseConfig
+func (ac *authConfig) TestPhoneCertifyCode() string {
+	var key = "auth.testPhoneCertifyCode"
+	if ac.testPhoneCertifyCode == nil {
+		ac.testPhoneCertifyCode = _string(viper.GetString(key))
+	}
+	return *ac.testPhoneCertifyCode
+}
+
+// MaintenanceMode implement MaintenanceMode of authUsecaseConfig
+func (ac *authConfig) MaintenanceMode() bool {
+	var key = "auth.maintenanceMode"
+	if ac.maintenanceMode == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultMaintenanceMode)
+		}
+		ac.maintenanceMode = _bool(viper.GetBool(key))
+	}
+	return *ac.maintenanceMode
+}
+
+// MaintenanceDisabledOperations implement MaintenanceDisabledOperations of authUsecaseConfig
+func (ac *authConfig) MaintenanceDisabledOperations() []string {
+	var key = "auth.maintenanceDisabledOperations"
+	if ac.maintenanceDisabledOperations == nil {
+		if !viper.IsSet(key) {
+			viper.Set(key, defaultMaintenanceDisabledOperations)
+		}
+		ac.maintenanceDisabledOperations = []string{}
+		for _, op := range viper.GetStringSlice(key) {
+			if op = strings.TrimSpace(op); op != "" {
+				ac.maintenanceDisabledOperations = append(ac.maintenanceDisabledOperations, op)
+			}
+		}
+	}
+	return ac.maintenanceDisabledOperations
+}
+
+// MaxParentSessions implement MaxParentSessions of authUsecaseConfig
+func (ac *authConfig) MaxParentSessions() int {
+	var key = "auth.maxParentSessions"
+	if ac.maxParentSessions == nil {
+		if v := viper.GetInt(key); viper.IsSet(key) && v >= 0 {
+			ac.maxParentSessions = _int(v)
+		} else {
+			viper.Set(key, defaultMaxParentSessions)
+			ac.maxParentSessions = _int(defaultMaxParentSessions)
+		}
+	}
+	return *ac.maxParentSessions
+}
+
+// SessionLimitPolicy implement SessionLimitPolicy of authUsecaseConfig
+func (ac *authConfig) SessionLimitPolicy() string {
+	var key = "auth.sessionLimitPolicy"
+	if ac.sessionLimitPolicy == nil {
+		if _, ok := viper.Get(key).(string); !ok {
+			viper.Set(key, defaultSessionLimitPolicy)
+		}
+		ac.sessionLimitPolicy = _string(viper.GetString(key))
+	}
+	return *ac.sessionLimitPolicy
+}
+
+// SessionEvictionNotification implement SessionEvictionNotification of authUsecaseConfig
+func (ac *authConfig) SessionEvictionNotification() bool {
+	var key = "auth.sessionEvictionNotification"
+	if ac.sessionEvictionNotification == nil {
+		if _, ok := viper.Get(key).(bool); !ok {
+			viper.Set(key, defaultSessionEvictionNotification)
+		}
+		ac.sessionEvictionNotification = _bool(viper.GetBool(key))
+	}
+	return *ac.sessionEvictionNotification
+}
+
+// CertifyChannels implement CertifyChannels of authUsecaseConfig
+func (ac *authConfig) CertifyChannels() []string {
+	var key = "auth.certifyChannels"
+	if ac.certifyChannels == nil {
+		if !viper.IsSet(key) {
+			viper.Set(key, defaultCertifyChannels)
+		}
+		ac.certifyChannels = []string{}
+		for _, c := range viper.GetStringSlice(key) {
+			if c = strings.TrimSpace(c); c != "" {
+				ac.certifyChannels = append(ac.certifyChannels, c)
+			}
+		}
+	}
+	return ac.certifyChannels
+}
+
+// CodeVerifyAttemptLimit implement CodeVerifyAttemptLimit of authUsecaseConfig
+func (ac *authConfig) CodeVerifyAttemptLimit() int {
+	var key = "auth.codeVerifyAttemptLimit"
+	if ac.codeVerifyAttemptLimit == nil {
+		if _, ok := viper.Get(key).(int); !ok {
+			viper.Set(key, defaultCodeVerifyAttemptLimit)
+		}
+		ac.codeVerifyAttemptLimit = _int(viper.GetInt(key))
+	}
+	return *ac.codeVerifyAttemptLimit
+}
+
+// CodeVerifyAttemptWindow implement CodeVerifyAttemptWindow of authUsecaseConfig
+func (ac *authConfig) CodeVerifyAttemptWindow() time.Duration {
+	var key = "auth.codeVerifyAttemptWindow"
+	if ac.codeVerifyAttemptWindow != nil {
+		return *ac.codeVerifyAttemptWindow
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultCodeVerifyAttemptWindow.String())
+		d = defaultCodeVerifyAttemptWindow
+	}
+	ac.codeVerifyAttemptWindow = &d
+	return *ac.codeVerifyAttemptWindow
+}
+
+// RestartCertificationLimit implement RestartCertificationLimit of authUsecaseConfig
+func (ac *authConfig) RestartCertificationLimit() int {
+	var key = "auth.restartCertificationLimit"
+	if ac.restartCertificationLimit == nil {
+		if _, ok := viper.Get(key).(int); !ok {
+			viper.Set(key, defaultRestartCertificationLimit)
+		}
+		ac.restartCertificationLimit = _int(viper.GetInt(key))
+	}
+	return *ac.restartCertificationLimit
+}
+
+// RestartCertificationWindow implement RestartCertificationWindow of authUsecaseConfig
+func (ac *authConfig) RestartCertificationWindow() time.Duration {
+	var key = "auth.restartCertificationWindow"
+	if ac.restartCertificationWindow != nil {
+		return *ac.restartCertificationWindow
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultRestartCertificationWindow.String())
+		d = defaultRestartCertificationWindow
+	}
+	ac.restartCertificationWindow = &d
+	return *ac.restartCertificationWindow
+}
+
 func _string(s string) *string { return &s }
+func _int(i int) *int          { return &i }
+func _bool(b bool) *bool       { return &b }
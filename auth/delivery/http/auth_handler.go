@@ -3,11 +3,17 @@ package http
 import (
 	"encoding/base64"
 	"github.com/gin-gonic/gin"
+	playValidator "github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
+	"log"
 	"net/http"
+	"path"
+	"reflect"
 	"regexp"
 
 	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/middleware"
+	"github.com/MyFirstBabyTime/Server/negotiate"
 )
 
 // authHandler represent the http handler for article
@@ -15,12 +21,34 @@ type authHandler struct {
 	aUsecase   domain.AuthUsecase
 	validator  validator
 	jwtHandler jwtHandler
+
+	// basePath is path which auth API is mounted under (ex, /api/v1), used in path of resource in response
+	basePath string
 }
 
 // jwtHandler is interface of jwt handler
 type jwtHandler interface {
 	// ParseUUIDFromToken parse token & return token payload and type
 	ParseUUIDFromToken(c *gin.Context)
+
+	// RequireRole return middleware that reject request if role of token account is not equal to role
+	RequireRole(role string) gin.HandlerFunc
+
+	// RequireScope return middleware that reject request if scope of token is not equal to scope
+	RequireScope(scope string) gin.HandlerFunc
+
+	// RequireScopeIfPhoneCertified return middleware like RequireScope, which reject only account having certified phone
+	RequireScopeIfPhoneCertified(scope string) gin.HandlerFunc
+
+	// IntrospectToken is handler that return exp, iat of token & if it is currently valid
+	IntrospectToken(c *gin.Context)
+
+	// NeedsRefresh is handler that return if token is expired or will expire soon
+	NeedsRefresh(c *gin.Context)
+
+	// WriteToken set token in cookie of response if token is delivered in cookie
+	// & return if token should be written in JSON body of response also
+	WriteToken(c *gin.Context, token string) (inBody bool)
 }
 
 // validator is interface used for validating struct value
@@ -28,62 +56,191 @@ type validator interface {
 	ValidateStruct(s interface{}) (err error)
 }
 
-// NewAuthHandler will initialize the auth/ resources endpoint
-func NewAuthHandler(r *gin.Engine, au domain.AuthUsecase, v validator, jh jwtHandler) {
+// NewAuthHandler will initialize the auth/ resources endpoint & return handler having them
+// r can be *gin.Engine or *gin.RouterGroup, so that auth API can be mounted under prefix (ex, /api/v1)
+func NewAuthHandler(r gin.IRouter, au domain.AuthUsecase, v validator, jh jwtHandler) *authHandler {
 	h := &authHandler{
 		aUsecase:   au,
 		validator:  v,
 		jwtHandler: jh,
+		basePath:   r.(interface{ BasePath() string }).BasePath(),
 	}
 
 	r.POST("phones/phone-number/:phone_number/certify-code", h.SendCertifyCodeToPhone)
+	r.POST("phones/certify-code", h.SendCertifyCodeToPhone)
+	r.POST("phones/phone-number/:phone_number/certify-code/reverification", h.jwtHandler.ParseUUIDFromToken, h.SendCertifyCodeToPhone)
 	r.POST("phones/phone-number/:phone_number/certification", h.CertifyPhoneWithCode)
+	r.DELETE("phones/phone-number/:phone_number/certification", h.RestartCertification)
+	r.POST("phones/phone-number/:phone_number/verification", h.jwtHandler.ParseUUIDFromToken, h.VerifyCertifyCodeOnly)
+	r.GET("phones/phone-number/:phone_number/eligibility", h.CheckPhoneEligibility)
 	r.POST("parents", h.SignUpParent)
+	r.POST("parents/validate", h.ValidateSignUpParent)
 	r.POST("login/parent", h.LoginParentAuth)
+	r.POST("login/parent/device-verification", h.CompleteDeviceVerification)
+	r.POST("login/parent/passkey/begin", h.BeginParentPasskeyLogin)
+	r.POST("login/parent/passkey/finish", h.FinishParentPasskeyLogin)
+	r.GET("tokens/introspect", h.jwtHandler.IntrospectToken)
+	r.GET("tokens/needs-refresh", h.jwtHandler.NeedsRefresh)
 	r.GET("parents/id/:parent_id/existence", h.CheckIfParentIDExist)
+	r.POST("parents/id/:parent_id/reservation", h.ReserveParentID)
+	r.GET("parents/uuid/:parent_uuid", h.jwtHandler.ParseUUIDFromToken, h.GetParentInformByUUID)
 	r.PATCH("parents/uuid/:parent_uuid", h.jwtHandler.ParseUUIDFromToken, h.UpdateParentInform)
+	// changing ID is sensitive, so it require elevated token issued with step-up (also withdrawal, if added later)
+	// step-up is done with phone code, so account without certified phone is re-authenticated only with pw in request
+	r.PUT("parents/me/id", h.jwtHandler.ParseUUIDFromToken, h.jwtHandler.RequireScopeIfPhoneCertified(domain.TokenScopeElevated), h.ChangeParentID)
+	r.PUT("parents/me/pw", h.jwtHandler.ParseUUIDFromToken, h.ChangeParentPW)
+	r.POST("parents/me/verify-password", h.jwtHandler.ParseUUIDFromToken, h.VerifyParentPassword)
+	r.GET("parents/me/export", h.jwtHandler.ParseUUIDFromToken, h.ExportParentData)
+	r.POST("parents/me/phone", h.jwtHandler.ParseUUIDFromToken, h.LinkPhoneToParent)
+	r.POST("parents/me/step-up/certify-code", h.jwtHandler.ParseUUIDFromToken, h.SendStepUpCode)
+	r.POST("parents/me/step-up", h.jwtHandler.ParseUUIDFromToken, h.StepUpWithPhoneCode)
+	r.GET("parents/me/phone/certifications", h.jwtHandler.ParseUUIDFromToken, h.GetParentPhoneCertification)
+	r.DELETE("parents/me/phone/certifications", h.jwtHandler.ParseUUIDFromToken, h.CancelParentPhoneCertification)
+	r.GET("parents/me/sessions", h.jwtHandler.ParseUUIDFromToken, h.ListParentSessions)
+	r.GET("parents/me/sessions/count", h.jwtHandler.ParseUUIDFromToken, h.CountActiveSessions)
+	r.GET("parents/me/notification-preferences", h.jwtHandler.ParseUUIDFromToken, h.GetNotificationPreferences)
+	r.PUT("parents/me/notification-preferences", h.jwtHandler.ParseUUIDFromToken, h.UpdateNotificationPreferences)
+	r.POST("parents/me/passkeys/registration/begin", h.jwtHandler.ParseUUIDFromToken, h.BeginParentPasskeyRegistration)
+	r.POST("parents/me/passkeys/registration/finish", h.jwtHandler.ParseUUIDFromToken, h.FinishParentPasskeyRegistration)
+
+	admin := r.Group("admin", h.jwtHandler.ParseUUIDFromToken, h.jwtHandler.RequireRole(domain.ParentRoleAdmin))
+	admin.GET("parents", h.ListParents)
+	admin.POST("parents/uuid/:parent_uuid/suspension", h.SuspendParent)
+	admin.DELETE("parents/uuid/:parent_uuid/suspension", h.UnsuspendParent)
+	admin.POST("parents/uuid/:parent_uuid/unlock", h.UnlockParentAccount)
+	admin.POST("phones/status", h.GetPhoneCertifyStatuses)
+	admin.POST("phones/phone-number/:phone_number/certify", h.ForceCertifyPhone)
+	admin.GET("phones/phone-number/:phone_number/history", h.ListPhoneCertifyHistory)
+	admin.GET("stats", h.GetAuthStats)
+	admin.GET("maintenance", h.GetMaintenanceMode)
+	admin.PUT("maintenance", h.SetMaintenanceMode)
+	return h
 }
 
 // SendCertifyCodeToPhone deliver data to SendCertifyCodeToPhone of domain.AuthUsecase
 func (ah *authHandler) SendCertifyCodeToPhone(c *gin.Context) {
 	req := new(sendCertifyCodeToPhoneRequest)
 	if err := ah.bindRequest(req, c); err != nil {
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+	if !ah.checkPhoneNumber(c, req.PhoneNumber) {
 		return
 	}
 
-	switch err := ah.aUsecase.SendCertifyCodeToPhone(c.Request.Context(), req.PhoneNumber); tErr := err.(type) {
+	dest := domain.CertifyCodeDestination{Channel: domain.CertifyChannel(req.Channel), Email: req.Email}
+	// uuid is set only in re-verification route parsing token, so that owner can get code to phone linked to it
+	// code of re-verification is used for step-up, so purpose in request is used only in other route
+	uuid, purpose := c.GetString("uuid"), domain.CertifyPurpose(req.Purpose)
+	if uuid != "" {
+		purpose = domain.CertifyPurposeStepUp
+	}
+	switch err := ah.aUsecase.SendCertifyCodeToPhone(c.Request.Context(), req.PhoneNumber, uuid, purpose, dest); tErr := err.(type) {
 	case nil:
 		resp := defaultResp(http.StatusOK, 0, "succeed to send certify code to phone")
-		c.JSON(http.StatusOK, resp)
+		negotiate.Render(c, http.StatusOK, resp)
 	case domain.UsecaseError:
-		resp := defaultResp(tErr.Status, tErr.Code, tErr.Error())
-		c.JSON(tErr.Status, resp)
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
 	default:
-		msg := errors.Wrap(err, "SendCertifyCodeToPhone return unexpected error").Error()
-		resp := defaultResp(http.StatusInternalServerError, 0, msg)
-		c.JSON(http.StatusInternalServerError, resp)
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "SendCertifyCodeToPhone return unexpected error")))
 	}
 	return
 }
 
 // CertifyPhoneWithCode deliver data to CertifyPhoneWithCode of domain.AuthUsecase
 func (ah *authHandler) CertifyPhoneWithCode(c *gin.Context) {
+	if !ah.checkPhoneNumberParam(c) {
+		return
+	}
+
 	req := new(certifyPhoneWithCodeRequest)
 	if err := ah.bindRequest(req, c); err != nil {
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
 		return
 	}
 
-	switch err := ah.aUsecase.CertifyPhoneWithCode(c.Request.Context(), req.PhoneNumber, req.CertifyCode); tErr := err.(type) {
+	switch status, err := ah.aUsecase.CertifyPhoneWithCode(c.Request.Context(), req.PhoneNumber, string(req.CertifyCode)); tErr := err.(type) {
 	case nil:
-		resp := defaultResp(http.StatusOK, 0, "succeed to certify phone with certify code")
-		c.JSON(http.StatusOK, resp)
+		resp := certifyPhoneWithCodeResponse{response: defaultResp(http.StatusOK, 0, "succeed to certify phone with certify code")}
+		resp.Certified, resp.InUse = status.Certified, status.InUse
+		negotiate.Render(c, http.StatusOK, resp)
 	case domain.UsecaseError:
-		c.JSON(tErr.Status, defaultResp(tErr.Status, tErr.Code, tErr.Error()))
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
 	default:
-		msg := errors.Wrap(err, "CertifyPhoneWithCode return unexpected error").Error()
-		c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, msg))
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "CertifyPhoneWithCode return unexpected error")))
+	}
+	return
+}
+
+// RestartCertification deliver data to RestartCertification of domain.AuthUsecase
+func (ah *authHandler) RestartCertification(c *gin.Context) {
+	if !ah.checkPhoneNumberParam(c) {
+		return
+	}
+
+	req := new(restartCertificationRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch err := ah.aUsecase.RestartCertification(c.Request.Context(), req.PhoneNumber); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to restart certification"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "RestartCertification return unexpected error")))
+	}
+	return
+}
+
+// VerifyCertifyCodeOnly deliver data to VerifyCertifyCodeOnly of domain.AuthUsecase
+func (ah *authHandler) VerifyCertifyCodeOnly(c *gin.Context) {
+	if !ah.checkPhoneNumberParam(c) {
+		return
+	}
+
+	req := new(verifyCertifyCodeOnlyRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch matched, err := ah.aUsecase.VerifyCertifyCodeOnly(c.Request.Context(), c.GetString("uuid"), req.PhoneNumber, string(req.CertifyCode)); tErr := err.(type) {
+	case nil:
+		resp := verifyCertifyCodeOnlyResponse{response: defaultResp(http.StatusOK, 0, "succeed to verify certify code")}
+		resp.Matched = matched
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "VerifyCertifyCodeOnly return unexpected error")))
+	}
+	return
+}
+
+// CheckPhoneEligibility deliver data to CheckPhoneEligibility of domain.AuthUsecase
+func (ah *authHandler) CheckPhoneEligibility(c *gin.Context) {
+	if !ah.checkPhoneNumberParam(c) {
+		return
+	}
+
+	req := new(checkPhoneEligibilityRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch eligible, reason, err := ah.aUsecase.CheckPhoneEligibility(c.Request.Context(), req.PhoneNumber); tErr := err.(type) {
+	case nil:
+		resp := checkPhoneEligibilityResponse{response: defaultResp(http.StatusOK, 0, "succeed to check phone eligibility")}
+		resp.Eligible, resp.Reason = eligible, reason
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "CheckPhoneEligibility return unexpected error")))
 	}
 	return
 }
@@ -92,7 +249,7 @@ func (ah *authHandler) CertifyPhoneWithCode(c *gin.Context) {
 func (ah *authHandler) SignUpParent(c *gin.Context) {
 	req := new(signUpParentRequest)
 	if err := ah.bindRequest(req, c); err != nil {
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
 		return
 	}
 
@@ -120,22 +277,64 @@ func (ah *authHandler) SignUpParent(c *gin.Context) {
 		req.ProfileBase64 = string(regexp.MustCompile("^data:image/\\w+;base64,").ReplaceAll([]byte(req.ProfileBase64), []byte("")))
 		var err error
 		if profile, err = base64.StdEncoding.DecodeString(req.ProfileBase64); err != nil {
-			err = errors.Wrap(err, "failed to decode base64 string to byte array")
-			c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, err.Error()))
+			log.Printf("failed to decode profile of request, err: %v", errors.Wrap(err, "failed to decode base64 string to byte array"))
+			negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, errors.New("profile_base64 is not valid base64 string")))
 			return
 		}
 	}
 
-	switch uuid, err := ah.aUsecase.SignUpParent(c.Request.Context(), pi, profile); tErr := err.(type) {
+	switch uuid, err := ah.aUsecase.SignUpParent(c.Request.Context(), pi, profile, req.Reservation); tErr := err.(type) {
 	case nil:
-		resp := defaultResp(http.StatusCreated, 0, "succeed to sign up new parent auth")
-		resp["parent_uuid"] = uuid
-		c.JSON(http.StatusCreated, resp)
+		resp := signUpParentResponse{response: defaultResp(http.StatusCreated, 0, "succeed to sign up new parent auth")}
+		resp.ParentUUID = uuid
+		// created parent is fetched with GetParentInformByUUID route under mounted path (ex, /api/v1/parents/uuid/{uuid})
+		c.Header("Location", path.Join(ah.basePath, "parents", "uuid", uuid))
+		negotiate.Render(c, http.StatusCreated, resp)
 	case domain.UsecaseError:
-		c.JSON(tErr.Status, defaultResp(tErr.Status, tErr.Code, tErr.Error()))
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
 	default:
-		msg := errors.Wrap(err, "SignUpParent return unexpected error").Error()
-		c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, msg))
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "SignUpParent return unexpected error")))
+	}
+	return
+}
+
+// ValidateSignUpParent deliver data to ValidateSignUpParent of domain.AuthUsecase
+// request is validated with same rule as SignUpParent, and field failed in it is not checked in usecase
+func (ah *authHandler) ValidateSignUpParent(c *gin.Context) {
+	req := new(signUpParentRequest)
+	if err := req.BindFrom(c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	invalid := map[string]string{}
+	if vErrs, ok := ah.validator.ValidateStruct(req).(playValidator.ValidationErrors); ok {
+		for _, vErr := range vErrs {
+			f, _ := reflect.TypeOf(req).Elem().FieldByName(vErr.StructField())
+			invalid[f.Tag.Get("json")] = vErr.Tag()
+		}
+	}
+
+	id, pn := req.ParentID, req.PhoneNumber
+	if _, ok := invalid["id"]; ok {
+		id = ""
+	}
+	if _, ok := invalid["phone_number"]; ok {
+		pn = ""
+	}
+
+	switch result, err := ah.aUsecase.ValidateSignUpParent(c.Request.Context(), id, pn); tErr := err.(type) {
+	case nil:
+		for field, reason := range result {
+			invalid[field] = reason
+		}
+		resp := validateSignUpParentResponse{response: defaultResp(http.StatusOK, 0, "succeed to validate sign up form")}
+		resp.Valid, resp.Invalid = len(invalid) == 0, invalid
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ValidateSignUpParent return unexpected error")))
 	}
 	return
 }
@@ -144,21 +343,157 @@ func (ah *authHandler) SignUpParent(c *gin.Context) {
 func (ah *authHandler) LoginParentAuth(c *gin.Context) {
 	req := new(loginParentAuthRequest)
 	if err := ah.bindRequest(req, c); err != nil {
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	device := domain.DeviceInfo{DeviceID: req.DeviceID, UserAgent: req.UserAgent, Platform: req.Platform}
+	uuid, token, verificationID, err := ah.aUsecase.LoginParentAuth(c.Request.Context(), req.ID, req.PW, device)
+	switch tErr := err.(type) {
+	case nil:
+		if verificationID != "" {
+			resp := loginParentAuthResponse{response: localizedResp(c, http.StatusAccepted, domain.DeviceVerificationRequired, "device verification is required to login from this device")}
+			resp.UUID, resp.VerificationID = uuid, verificationID
+			negotiate.Render(c, http.StatusAccepted, resp)
+			return
+		}
+		resp := loginParentAuthResponse{response: defaultResp(http.StatusOK, 0, "succeed to login parent auth")}
+		resp.UUID = uuid
+		if ah.jwtHandler.WriteToken(c, token) {
+			resp.Token = token
+		}
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "LoginParentAuth return unexpected error")))
+	}
+	return
+}
+
+// CompleteDeviceVerification deliver data to CompleteDeviceVerification of domain.AuthUsecase
+func (ah *authHandler) CompleteDeviceVerification(c *gin.Context) {
+	req := new(completeDeviceVerificationRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	uuid, token, err := ah.aUsecase.CompleteDeviceVerification(c.Request.Context(), req.VerificationID, string(req.CertifyCode))
+	switch tErr := err.(type) {
+	case nil:
+		resp := loginParentAuthResponse{response: defaultResp(http.StatusOK, 0, "succeed to complete device verification")}
+		resp.UUID = uuid
+		if ah.jwtHandler.WriteToken(c, token) {
+			resp.Token = token
+		}
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "CompleteDeviceVerification return unexpected error")))
+	}
+	return
+}
+
+// BeginParentPasskeyRegistration deliver data to BeginParentPasskeyRegistration of domain.AuthUsecase
+func (ah *authHandler) BeginParentPasskeyRegistration(c *gin.Context) {
+	switch options, ceremonyID, err := ah.aUsecase.BeginParentPasskeyRegistration(c.Request.Context(), c.GetString("uuid")); tErr := err.(type) {
+	case nil:
+		resp := beginPasskeyCeremonyResponse{response: defaultResp(http.StatusOK, 0, "succeed to begin passkey registration")}
+		resp.CeremonyID, resp.Options = ceremonyID, options
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "BeginParentPasskeyRegistration return unexpected error")))
+	}
+	return
+}
+
+// FinishParentPasskeyRegistration deliver data to FinishParentPasskeyRegistration of domain.AuthUsecase
+func (ah *authHandler) FinishParentPasskeyRegistration(c *gin.Context) {
+	req := new(finishPasskeyRegistrationRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch err := ah.aUsecase.FinishParentPasskeyRegistration(c.Request.Context(), c.GetString("uuid"), req.CeremonyID, req.Response); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusCreated, defaultResp(http.StatusCreated, 0, "succeed to register passkey"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "FinishParentPasskeyRegistration return unexpected error")))
+	}
+	return
+}
+
+// BeginParentPasskeyLogin deliver data to BeginParentPasskeyLogin of domain.AuthUsecase
+func (ah *authHandler) BeginParentPasskeyLogin(c *gin.Context) {
+	req := new(beginPasskeyLoginRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
 		return
 	}
 
-	uuid, token, err := ah.aUsecase.LoginParentAuth(c.Request.Context(), req.ID, req.PW)
+	switch options, ceremonyID, err := ah.aUsecase.BeginParentPasskeyLogin(c.Request.Context(), req.ID); tErr := err.(type) {
+	case nil:
+		resp := beginPasskeyCeremonyResponse{response: defaultResp(http.StatusOK, 0, "succeed to begin passkey login")}
+		resp.CeremonyID, resp.Options = ceremonyID, options
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "BeginParentPasskeyLogin return unexpected error")))
+	}
+	return
+}
+
+// FinishParentPasskeyLogin deliver data to FinishParentPasskeyLogin of domain.AuthUsecase
+func (ah *authHandler) FinishParentPasskeyLogin(c *gin.Context) {
+	req := new(finishPasskeyLoginRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	device := domain.DeviceInfo{DeviceID: req.DeviceID, UserAgent: req.UserAgent, Platform: req.Platform}
+	uuid, token, err := ah.aUsecase.FinishParentPasskeyLogin(c.Request.Context(), req.CeremonyID, req.Response, device)
 	switch tErr := err.(type) {
 	case nil:
-		resp := defaultResp(http.StatusOK, 0, "succeed to login parent auth")
-		resp["uuid"], resp["token"] = uuid, token
-		c.JSON(http.StatusOK, resp)
+		resp := loginParentAuthResponse{response: defaultResp(http.StatusOK, 0, "succeed to login parent with passkey")}
+		resp.UUID = uuid
+		if ah.jwtHandler.WriteToken(c, token) {
+			resp.Token = token
+		}
+		negotiate.Render(c, http.StatusOK, resp)
 	case domain.UsecaseError:
-		c.JSON(tErr.Status, defaultResp(tErr.Status, tErr.Code, tErr.Error()))
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
 	default:
-		msg := errors.Wrap(err, "LoginParentAuth return unexpected error").Error()
-		c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, msg))
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "FinishParentPasskeyLogin return unexpected error")))
+	}
+	return
+}
+
+// ReserveParentID deliver data to ReserveParentID of domain.AuthUsecase
+func (ah *authHandler) ReserveParentID(c *gin.Context) {
+	req := new(reserveParentIDRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch reservation, expiresAt, err := ah.aUsecase.ReserveParentID(c.Request.Context(), req.ParentID, middleware.ClientIP(c)); tErr := err.(type) {
+	case nil:
+		resp := reserveParentIDResponse{response: defaultResp(http.StatusCreated, 0, "succeed to reserve parent ID")}
+		resp.Reservation, resp.ExpiresAt = reservation, domain.TimestampOf(&expiresAt)
+		negotiate.Render(c, http.StatusCreated, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ReserveParentID return unexpected error")))
 	}
 	return
 }
@@ -167,19 +502,47 @@ func (ah *authHandler) LoginParentAuth(c *gin.Context) {
 func (ah *authHandler) CheckIfParentIDExist(c *gin.Context) {
 	req := new(getParentInformByIDRequest)
 	if err := ah.bindRequest(req, c); err != nil {
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
 		return
 	}
 
 	switch _, err := ah.aUsecase.GetParentInformByID(c.Request.Context(), req.ParentID); tErr := err.(type) {
 	case nil:
 		resp := defaultResp(http.StatusOK, 0, "parent auth with that ID is exist")
-		c.JSON(http.StatusOK, resp)
+		negotiate.Render(c, http.StatusOK, resp)
 	case domain.UsecaseError:
-		c.JSON(tErr.Status, defaultResp(tErr.Status, tErr.Code, tErr.Error()))
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
 	default:
-		msg := errors.Wrap(err, "GetParentInformByID return unexpected error").Error()
-		c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, msg))
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "GetParentInformByID return unexpected error")))
+	}
+	return
+}
+
+// GetParentInformByUUID deliver data to GetParentInformByUUID of domain.AuthUsecase
+func (ah *authHandler) GetParentInformByUUID(c *gin.Context) {
+	req := new(getParentInformByUUIDRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	if c.GetString("uuid") != req.ParentUUID {
+		negotiate.Render(c, http.StatusForbidden, localizedResp(c, http.StatusForbidden, 0, "you can't access with that uuid token"))
+		return
+	}
+
+	switch pi, err := ah.aUsecase.GetParentInformByUUID(c.Request.Context(), req.ParentUUID); tErr := err.(type) {
+	case nil:
+		resp := getParentInformByUUIDResponse{response: defaultResp(http.StatusOK, 0, "succeed to get parent inform")}
+		resp.ParentUUID = domain.StringValue(pi.ParentAuth.UUID)
+		resp.ParentID = domain.StringValue(pi.ParentAuth.ID)
+		resp.Name = domain.StringValue(pi.Name)
+		resp.ProfileUri = domain.StringValue(pi.ProfileUri)
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "GetParentInformByUUID return unexpected error")))
 	}
 	return
 }
@@ -188,12 +551,12 @@ func (ah *authHandler) CheckIfParentIDExist(c *gin.Context) {
 func (ah *authHandler) UpdateParentInform(c *gin.Context) {
 	req := new(updateParentInformRequest)
 	if err := ah.bindRequest(req, c); err != nil {
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
 		return
 	}
 
 	if c.GetString("uuid") != req.ParentUUID {
-		c.JSON(http.StatusForbidden, defaultResp(http.StatusForbidden, 0, "you can't access with that uuid token"))
+		negotiate.Render(c, http.StatusForbidden, localizedResp(c, http.StatusForbidden, 0, "you can't access with that uuid token"))
 		return
 	}
 
@@ -212,8 +575,8 @@ func (ah *authHandler) UpdateParentInform(c *gin.Context) {
 		req.ProfileBase64 = string(regexp.MustCompile("^data:image/\\w+;base64,").ReplaceAll([]byte(req.ProfileBase64), []byte("")))
 		var err error
 		if profile, err = base64.StdEncoding.DecodeString(req.ProfileBase64); err != nil {
-			err = errors.Wrap(err, "failed to decode base64 string to byte array")
-			c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, err.Error()))
+			log.Printf("failed to decode profile of request, err: %v", errors.Wrap(err, "failed to decode base64 string to byte array"))
+			negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, errors.New("profile_base64 is not valid base64 string")))
 			return
 		}
 	}
@@ -221,16 +584,494 @@ func (ah *authHandler) UpdateParentInform(c *gin.Context) {
 	switch err := ah.aUsecase.UpdateParentInform(c.Request.Context(), req.ParentUUID, pa, profile); tErr := err.(type) {
 	case nil:
 		resp := defaultResp(http.StatusOK, 0, "succeed to update parent inform")
-		c.JSON(http.StatusOK, resp)
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "UpdateParentInform return unexpected error")))
+	}
+	return
+}
+
+// ChangeParentID deliver data to ChangeParentID of domain.AuthUsecase
+func (ah *authHandler) ChangeParentID(c *gin.Context) {
+	req := new(changeParentIDRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch err := ah.aUsecase.ChangeParentID(c.Request.Context(), c.GetString("uuid"), req.ParentID, req.ParentPW); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to change parent ID"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ChangeParentID return unexpected error")))
+	}
+	return
+}
+
+// VerifyParentPassword deliver data to VerifyParentPassword of domain.AuthUsecase
+func (ah *authHandler) VerifyParentPassword(c *gin.Context) {
+	req := new(verifyParentPasswordRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch matched, err := ah.aUsecase.VerifyParentPassword(c.Request.Context(), c.GetString("uuid"), req.ParentPW); tErr := err.(type) {
+	case nil:
+		resp := verifyParentPasswordResponse{response: defaultResp(http.StatusOK, 0, "succeed to verify parent password")}
+		resp.Matched = matched
+		negotiate.Render(c, http.StatusOK, resp)
 	case domain.UsecaseError:
-		c.JSON(tErr.Status, defaultResp(tErr.Status, tErr.Code, tErr.Error()))
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
 	default:
-		msg := errors.Wrap(err, "UpdateParentInform return unexpected error").Error()
-		c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, msg))
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "VerifyParentPassword return unexpected error")))
 	}
 	return
 }
 
+// ChangeParentPW deliver data to ChangeParentPW of domain.AuthUsecase
+func (ah *authHandler) ChangeParentPW(c *gin.Context) {
+	req := new(changeParentPWRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch err := ah.aUsecase.ChangeParentPW(c.Request.Context(), c.GetString("uuid"), req.ParentPW, req.NewParentPW); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to change parent password"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ChangeParentPW return unexpected error")))
+	}
+	return
+}
+
+// LinkPhoneToParent deliver data to LinkPhoneToParent of domain.AuthUsecase
+func (ah *authHandler) LinkPhoneToParent(c *gin.Context) {
+	req := new(linkPhoneToParentRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+	if !ah.checkPhoneNumber(c, req.PhoneNumber) {
+		return
+	}
+
+	switch err := ah.aUsecase.LinkPhoneToParent(c.Request.Context(), c.GetString("uuid"), req.PhoneNumber, string(req.CertifyCode)); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to link phone to parent"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "LinkPhoneToParent return unexpected error")))
+	}
+	return
+}
+
+// SendStepUpCode deliver data to SendStepUpCode of domain.AuthUsecase
+func (ah *authHandler) SendStepUpCode(c *gin.Context) {
+	switch err := ah.aUsecase.SendStepUpCode(c.Request.Context(), c.GetString("uuid")); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to send certify code for step-up"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "SendStepUpCode return unexpected error")))
+	}
+	return
+}
+
+// StepUpWithPhoneCode deliver data to StepUpWithPhoneCode of domain.AuthUsecase
+func (ah *authHandler) StepUpWithPhoneCode(c *gin.Context) {
+	req := new(stepUpWithPhoneCodeRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch token, err := ah.aUsecase.StepUpWithPhoneCode(c.Request.Context(), c.GetString("uuid"), string(req.CertifyCode)); tErr := err.(type) {
+	case nil:
+		resp := stepUpWithPhoneCodeResponse{response: defaultResp(http.StatusOK, 0, "succeed to step up with phone code")}
+		resp.Token = token
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "StepUpWithPhoneCode return unexpected error")))
+	}
+	return
+}
+
+// GetParentPhoneCertification deliver data to GetParentPhoneCertification of domain.AuthUsecase
+func (ah *authHandler) GetParentPhoneCertification(c *gin.Context) {
+	switch status, err := ah.aUsecase.GetParentPhoneCertification(c.Request.Context(), c.GetString("uuid")); tErr := err.(type) {
+	case nil:
+		resp := getParentPhoneCertificationResponse{response: defaultResp(http.StatusOK, 0, "succeed to get parent phone certification")}
+		resp.PhoneCertifyStatus = status
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "GetParentPhoneCertification return unexpected error")))
+	}
+	return
+}
+
+// CancelParentPhoneCertification deliver data to CancelParentPhoneCertification of domain.AuthUsecase
+func (ah *authHandler) CancelParentPhoneCertification(c *gin.Context) {
+	switch err := ah.aUsecase.CancelParentPhoneCertification(c.Request.Context(), c.GetString("uuid")); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to cancel parent phone certification"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "CancelParentPhoneCertification return unexpected error")))
+	}
+	return
+}
+
+// CountActiveSessions deliver data to CountActiveSessions of domain.AuthUsecase
+func (ah *authHandler) CountActiveSessions(c *gin.Context) {
+	switch count, err := ah.aUsecase.CountActiveSessions(c.Request.Context(), c.GetString("uuid")); tErr := err.(type) {
+	case nil:
+		resp := countActiveSessionsResponse{response: defaultResp(http.StatusOK, 0, "succeed to count active sessions")}
+		resp.Count = count
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "CountActiveSessions return unexpected error")))
+	}
+	return
+}
+
+// ListPhoneCertifyHistory deliver data to ListPhoneCertifyHistory of domain.AuthUsecase
+func (ah *authHandler) ListPhoneCertifyHistory(c *gin.Context) {
+	if !ah.checkPhoneNumberParam(c) {
+		return
+	}
+
+	req := new(listPhoneCertifyHistoryRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch events, next, prev, err := ah.aUsecase.ListPhoneCertifyHistory(c.Request.Context(), req.PhoneNumber, req.Cursor, req.Limit); tErr := err.(type) {
+	case nil:
+		resp := listPhoneCertifyHistoryResponse{listResponse: listResponse{response: defaultResp(http.StatusOK, 0, "succeed to list phone certify history")}}
+		resp.Next, resp.Prev = next, prev
+		resp.Events = make([]phoneCertifyEvent, len(events))
+		for i, pce := range events {
+			resp.Events[i] = phoneCertifyEvent{
+				Type:       domain.StringValue(pce.Type),
+				Channel:    domain.StringValue(pce.Channel),
+				OccurredAt: domain.TimestampOf(pce.OccurredAt),
+			}
+		}
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ListPhoneCertifyHistory return unexpected error")))
+	}
+	return
+}
+
+// GetMaintenanceMode deliver data to GetMaintenanceMode of domain.AuthUsecase
+func (ah *authHandler) GetMaintenanceMode(c *gin.Context) {
+	switch enabled, err := ah.aUsecase.GetMaintenanceMode(c.Request.Context()); tErr := err.(type) {
+	case nil:
+		resp := maintenanceModeResponse{response: defaultResp(http.StatusOK, 0, "succeed to get maintenance mode")}
+		resp.Enabled = enabled
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "GetMaintenanceMode return unexpected error")))
+	}
+	return
+}
+
+// SetMaintenanceMode deliver data to SetMaintenanceMode of domain.AuthUsecase
+func (ah *authHandler) SetMaintenanceMode(c *gin.Context) {
+	req := new(setMaintenanceModeRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch err := ah.aUsecase.SetMaintenanceMode(c.Request.Context(), c.GetString("uuid"), *req.Enabled); tErr := err.(type) {
+	case nil:
+		resp := maintenanceModeResponse{response: defaultResp(http.StatusOK, 0, "succeed to set maintenance mode")}
+		resp.Enabled = *req.Enabled
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "SetMaintenanceMode return unexpected error")))
+	}
+	return
+}
+
+// GetAuthStats deliver data to GetAuthStats of domain.AuthUsecase
+func (ah *authHandler) GetAuthStats(c *gin.Context) {
+	req := new(getAuthStatsRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch stats, err := ah.aUsecase.GetAuthStats(c.Request.Context(), req.from, req.to); tErr := err.(type) {
+	case nil:
+		resp := getAuthStatsResponse{response: defaultResp(http.StatusOK, 0, "succeed to get auth stats")}
+		resp.AuthStats = stats
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "GetAuthStats return unexpected error")))
+	}
+	return
+}
+
+// GetNotificationPreferences deliver data to GetNotificationPreferences of domain.AuthUsecase
+func (ah *authHandler) GetNotificationPreferences(c *gin.Context) {
+	switch prefs, err := ah.aUsecase.GetNotificationPreferences(c.Request.Context(), c.GetString("uuid")); tErr := err.(type) {
+	case nil:
+		resp := notificationPreferencesResponse{response: defaultResp(http.StatusOK, 0, "succeed to get notification preferences")}
+		resp.NotificationPreferences = prefs
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "GetNotificationPreferences return unexpected error")))
+	}
+	return
+}
+
+// UpdateNotificationPreferences deliver data to UpdateNotificationPreferences of domain.AuthUsecase
+func (ah *authHandler) UpdateNotificationPreferences(c *gin.Context) {
+	req := new(updateNotificationPreferencesRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch prefs, err := ah.aUsecase.UpdateNotificationPreferences(c.Request.Context(), c.GetString("uuid"), *req.Marketing); tErr := err.(type) {
+	case nil:
+		resp := notificationPreferencesResponse{response: defaultResp(http.StatusOK, 0, "succeed to update notification preferences")}
+		resp.NotificationPreferences = prefs
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "UpdateNotificationPreferences return unexpected error")))
+	}
+	return
+}
+
+// ExportParentData deliver data to ExportParentData of domain.AuthUsecase
+func (ah *authHandler) ExportParentData(c *gin.Context) {
+	switch export, err := ah.aUsecase.ExportParentData(c.Request.Context(), c.GetString("uuid")); tErr := err.(type) {
+	case nil:
+		resp := exportParentDataResponse{response: defaultResp(http.StatusOK, 0, "succeed to export parent data")}
+		resp.Data = export
+		c.Header("Content-Disposition", "attachment; filename=parent-data-export.json")
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ExportParentData return unexpected error")))
+	}
+	return
+}
+
+// SuspendParent deliver data to SuspendParent of domain.AuthUsecase
+func (ah *authHandler) SuspendParent(c *gin.Context) {
+	req := new(suspendParentRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch err := ah.aUsecase.SuspendParent(c.Request.Context(), req.ParentUUID, req.Reason, req.Until); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to suspend parent"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "SuspendParent return unexpected error")))
+	}
+	return
+}
+
+// UnsuspendParent deliver data to UnsuspendParent of domain.AuthUsecase
+func (ah *authHandler) UnsuspendParent(c *gin.Context) {
+	req := new(unsuspendParentRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch err := ah.aUsecase.UnsuspendParent(c.Request.Context(), req.ParentUUID); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to unsuspend parent"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "UnsuspendParent return unexpected error")))
+	}
+	return
+}
+
+// UnlockParentAccount deliver data to UnlockParentAccount of domain.AuthUsecase
+func (ah *authHandler) UnlockParentAccount(c *gin.Context) {
+	req := new(unlockParentAccountRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch err := ah.aUsecase.UnlockParentAccount(c.Request.Context(), c.GetString("uuid"), req.ParentUUID); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to unlock parent account"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "UnlockParentAccount return unexpected error")))
+	}
+	return
+}
+
+// GetPhoneCertifyStatuses deliver data to GetPhoneCertifyStatuses of domain.AuthUsecase
+func (ah *authHandler) GetPhoneCertifyStatuses(c *gin.Context) {
+	req := new(getPhoneCertifyStatusesRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch statuses, err := ah.aUsecase.GetPhoneCertifyStatuses(c.Request.Context(), req.PhoneNumbers); tErr := err.(type) {
+	case nil:
+		resp := getPhoneCertifyStatusesResponse{response: defaultResp(http.StatusOK, 0, "succeed to get phone certify statuses")}
+		resp.Statuses = statuses
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "GetPhoneCertifyStatuses return unexpected error")))
+	}
+	return
+}
+
+// ListParents deliver data to ListParents of domain.AuthUsecase
+func (ah *authHandler) ListParents(c *gin.Context) {
+	req := new(listRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch parents, next, prev, err := ah.aUsecase.ListParents(c.Request.Context(), req.Cursor, req.Limit); tErr := err.(type) {
+	case nil:
+		resp := listParentsResponse{listResponse: listResponse{response: defaultResp(http.StatusOK, 0, "succeed to list parents")}}
+		resp.Next, resp.Prev = next, prev
+		resp.Parents = make([]parentSummary, len(parents))
+		for i, pa := range parents {
+			resp.Parents[i] = parentSummary{
+				UUID:      domain.StringValue(pa.UUID),
+				ID:        domain.StringValue(pa.ID),
+				Name:      domain.StringValue(pa.Name),
+				Role:      domain.StringValue(pa.Role),
+				Suspended: domain.BoolValue(pa.Suspended),
+			}
+		}
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ListParents return unexpected error")))
+	}
+	return
+}
+
+// ListParentSessions deliver data to ListParentSessions of domain.AuthUsecase
+func (ah *authHandler) ListParentSessions(c *gin.Context) {
+	req := new(listRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch sessions, next, prev, err := ah.aUsecase.ListParentSessions(c.Request.Context(), c.GetString("uuid"), req.Cursor, req.Limit); tErr := err.(type) {
+	case nil:
+		resp := listParentSessionsResponse{listResponse: listResponse{response: defaultResp(http.StatusOK, 0, "succeed to list parent sessions")}}
+		resp.Next, resp.Prev = next, prev
+		resp.Sessions = make([]parentSession, len(sessions))
+		for i, ps := range sessions {
+			resp.Sessions[i] = parentSession{
+				UUID:       domain.StringValue(ps.UUID),
+				DeviceID:   domain.StringValue(ps.DeviceID),
+				UserAgent:  domain.StringValue(ps.UserAgent),
+				Platform:   domain.StringValue(ps.Platform),
+				CreatedAt:  domain.TimestampOf(ps.CreatedAt),
+				LastUsedAt: domain.TimestampOf(ps.LastUsedAt),
+			}
+		}
+		negotiate.Render(c, http.StatusOK, resp)
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ListParentSessions return unexpected error")))
+	}
+	return
+}
+
+// ForceCertifyPhone deliver data to ForceCertifyPhone of domain.AuthUsecase
+func (ah *authHandler) ForceCertifyPhone(c *gin.Context) {
+	if !ah.checkPhoneNumberParam(c) {
+		return
+	}
+
+	req := new(forceCertifyPhoneRequest)
+	if err := ah.bindRequest(req, c); err != nil {
+		negotiate.Render(c, http.StatusBadRequest, badRequestResp(c, err))
+		return
+	}
+
+	switch err := ah.aUsecase.ForceCertifyPhone(c.Request.Context(), c.GetString("uuid"), req.PhoneNumber); tErr := err.(type) {
+	case nil:
+		negotiate.Render(c, http.StatusOK, defaultResp(http.StatusOK, 0, "succeed to force certify phone"))
+	case domain.UsecaseError:
+		negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+	default:
+		negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ForceCertifyPhone return unexpected error")))
+	}
+	return
+}
+
+// checkPhoneNumberParam method check phone_number path param before binding & response 400 if it is invalid
+func (ah *authHandler) checkPhoneNumberParam(c *gin.Context) bool {
+	return ah.checkPhoneNumber(c, domain.NormalizePhoneNumber(c.Param("phone_number")))
+}
+
+// checkPhoneNumber method check if normalized phone number is valid & response 400 if it is invalid
+func (ah *authHandler) checkPhoneNumber(c *gin.Context, pn string) bool {
+	if !domain.IsValidPhoneNumber(pn) {
+		msg := "phone_number must be 11 digits (hyphen & space is allowed)"
+		negotiate.Render(c, http.StatusBadRequest, localizedResp(c, http.StatusBadRequest, domain.InvalidPhoneNumber, msg))
+		return false
+	}
+	return true
+}
+
 // bindRequest method bind *gin.Context to request having BindFrom method
 func (ah *authHandler) bindRequest(req interface {
 	BindFrom(ctx *gin.Context) error
@@ -243,12 +1084,3 @@ func (ah *authHandler) bindRequest(req interface {
 	}
 	return nil
 }
-
-// defaultResp return response have status, code, message inform
-func defaultResp(status, code int, msg string) (resp gin.H) {
-	resp = gin.H{}
-	resp["status"] = status
-	resp["code"] = code
-	resp["message"] = msg
-	return
-}
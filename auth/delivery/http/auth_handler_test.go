@@ -0,0 +1,781 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/validate"
+)
+
+// update rewrite golden file of handler test with current response, run with `go test -run TestAuthHandler -update`
+var update = flag.Bool("update", false, "update golden file of handler test")
+
+const (
+	testParentUUID = "parent-111111111111"
+	testPhone      = "01012345678"
+	testCeremonyID = "0123456789abcdef0123456789abcdef"
+)
+
+// testTime is fixed time returned from fakeAuthUsecase, so that timestamp in golden file doesn't change
+var testTime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// fakeAuthUsecase is domain.AuthUsecase returning fixed value with err, so that handler is tested without usecase
+type fakeAuthUsecase struct {
+	domain.AuthUsecase
+
+	// err is returned from every method
+	err error
+
+	// verificationID is returned from LoginParentAuth, to test login requiring device verification
+	verificationID string
+}
+
+func (f *fakeAuthUsecase) SendCertifyCodeToPhone(_ context.Context, _, _ string, _ domain.CertifyPurpose, _ domain.CertifyCodeDestination) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) CertifyPhoneWithCode(_ context.Context, pn, _ string) (domain.PhoneCertifyStatus, error) {
+	return domain.PhoneCertifyStatus{PhoneNumber: pn, Exist: true, Certified: true}, f.err
+}
+
+func (f *fakeAuthUsecase) RestartCertification(_ context.Context, _ string) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) VerifyCertifyCodeOnly(_ context.Context, _, _, _ string) (bool, error) {
+	return true, f.err
+}
+
+func (f *fakeAuthUsecase) CheckPhoneEligibility(_ context.Context, _ string) (bool, string, error) {
+	return true, "", f.err
+}
+
+func (f *fakeAuthUsecase) ValidateSignUpParent(_ context.Context, _, _ string) (map[string]string, error) {
+	return map[string]string{"id": "in_use"}, f.err
+}
+
+func (f *fakeAuthUsecase) ReserveParentID(_ context.Context, _, _ string) (string, time.Time, error) {
+	return "reservation", testTime, f.err
+}
+
+func (f *fakeAuthUsecase) SignUpParent(_ context.Context, _ struct {
+	*domain.ParentAuth
+	*domain.ParentPhoneCertify
+}, _ []byte, _ string) (string, error) {
+	return testParentUUID, f.err
+}
+
+func (f *fakeAuthUsecase) LoginParentAuth(_ context.Context, _, _ string, _ domain.DeviceInfo) (string, string, string, error) {
+	if f.verificationID != "" {
+		return testParentUUID, "", f.verificationID, f.err
+	}
+	return testParentUUID, "token", "", f.err
+}
+
+func (f *fakeAuthUsecase) CompleteDeviceVerification(_ context.Context, _, _ string) (string, string, error) {
+	return testParentUUID, "token", f.err
+}
+
+func (f *fakeAuthUsecase) GetParentInformByID(_ context.Context, _ string) (struct {
+	domain.ParentAuth
+	domain.ParentPhoneCertify
+}, error) {
+	return struct {
+		domain.ParentAuth
+		domain.ParentPhoneCertify
+	}{}, f.err
+}
+
+func (f *fakeAuthUsecase) GetParentInformByUUID(_ context.Context, uuid string) (struct {
+	domain.ParentAuth
+	domain.ParentPhoneCertify
+}, error) {
+	pi := struct {
+		domain.ParentAuth
+		domain.ParentPhoneCertify
+	}{}
+	pi.ParentAuth = domain.ParentAuth{UUID: domain.String(uuid), ID: domain.String("parent1"), Name: domain.String("name")}
+	return pi, f.err
+}
+
+func (f *fakeAuthUsecase) UpdateParentInform(_ context.Context, _ string, _ *domain.ParentAuth, _ []byte) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) ChangeParentID(_ context.Context, _, _, _ string) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) ChangeParentPW(_ context.Context, _, _, _ string) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) VerifyParentPassword(_ context.Context, _, _ string) (bool, error) {
+	return true, f.err
+}
+
+func (f *fakeAuthUsecase) GetPhoneCertifyStatuses(_ context.Context, pns []string) ([]domain.PhoneCertifyStatus, error) {
+	statuses := make([]domain.PhoneCertifyStatus, len(pns))
+	for i, pn := range pns {
+		statuses[i] = domain.PhoneCertifyStatus{PhoneNumber: pn, Exist: true}
+	}
+	return statuses, f.err
+}
+
+func (f *fakeAuthUsecase) SuspendParent(_ context.Context, _, _ string, _ time.Time) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) UnsuspendParent(_ context.Context, _ string) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) LinkPhoneToParent(_ context.Context, _, _, _ string) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) SendStepUpCode(_ context.Context, _ string) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) StepUpWithPhoneCode(_ context.Context, _, _ string) (string, error) {
+	return "elevated-token", f.err
+}
+
+func (f *fakeAuthUsecase) GetParentPhoneCertification(_ context.Context, _ string) (domain.PhoneCertifyStatus, error) {
+	return domain.PhoneCertifyStatus{PhoneNumber: testPhone, Exist: true, Certified: true, InUse: true}, f.err
+}
+
+func (f *fakeAuthUsecase) CancelParentPhoneCertification(_ context.Context, _ string) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) BeginParentPasskeyRegistration(_ context.Context, _ string) ([]byte, string, error) {
+	return []byte(`{"publicKey":{}}`), testCeremonyID, f.err
+}
+
+func (f *fakeAuthUsecase) FinishParentPasskeyRegistration(_ context.Context, _, _ string, _ []byte) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) BeginParentPasskeyLogin(_ context.Context, _ string) ([]byte, string, error) {
+	return []byte(`{"publicKey":{}}`), testCeremonyID, f.err
+}
+
+func (f *fakeAuthUsecase) FinishParentPasskeyLogin(_ context.Context, _ string, _ []byte, _ domain.DeviceInfo) (string, string, error) {
+	return testParentUUID, "token", f.err
+}
+
+func (f *fakeAuthUsecase) ListParents(_ context.Context, _ string, _ int) ([]domain.ParentAuth, string, string, error) {
+	parents := []domain.ParentAuth{{
+		UUID: domain.String(testParentUUID),
+		ID:   domain.String("parent1"),
+		Name: domain.String("name"),
+		Role: domain.String(domain.ParentRoleParent),
+	}}
+	return parents, "next", "", f.err
+}
+
+func (f *fakeAuthUsecase) ListParentSessions(_ context.Context, _, _ string, _ int) ([]domain.ParentSession, string, string, error) {
+	sessions := []domain.ParentSession{{
+		UUID:       domain.String("session-111111111111"),
+		DeviceID:   domain.String("device"),
+		Platform:   domain.String("ios"),
+		CreatedAt:  &testTime,
+		LastUsedAt: &testTime,
+	}}
+	return sessions, "", "prev", f.err
+}
+
+func (f *fakeAuthUsecase) CountActiveSessions(_ context.Context, _ string) (int, error) {
+	return 2, f.err
+}
+
+func (f *fakeAuthUsecase) ExportParentData(_ context.Context, uuid string) (domain.ParentDataExport, error) {
+	return domain.ParentDataExport{
+		ExportedAt: domain.Timestamp(testTime),
+		Parent:     domain.ParentAuthExport{UUID: uuid, ID: "parent1", Name: "name", Role: domain.ParentRoleParent},
+	}, f.err
+}
+
+func (f *fakeAuthUsecase) ForceCertifyPhone(_ context.Context, _, _ string) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) UnlockParentAccount(_ context.Context, _, _ string) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) GetMaintenanceMode(_ context.Context) (bool, error) {
+	return true, f.err
+}
+
+func (f *fakeAuthUsecase) SetMaintenanceMode(_ context.Context, _ string, _ bool) error {
+	return f.err
+}
+
+func (f *fakeAuthUsecase) ListPhoneCertifyHistory(_ context.Context, _, _ string, _ int) ([]domain.PhoneCertifyEvent, string, string, error) {
+	events := []domain.PhoneCertifyEvent{{
+		Type:       domain.String("code_sent"),
+		Channel:    domain.String("sms"),
+		OccurredAt: &testTime,
+	}}
+	return events, "", "", f.err
+}
+
+func (f *fakeAuthUsecase) GetAuthStats(_ context.Context, from, to time.Time) (domain.AuthStats, error) {
+	return domain.AuthStats{From: domain.Timestamp(from), To: domain.Timestamp(to), SignUps: 1, CodesSent: 2, Logins: 3}, f.err
+}
+
+func (f *fakeAuthUsecase) GetNotificationPreferences(_ context.Context, _ string) (domain.NotificationPreferences, error) {
+	return domain.DefaultNotificationPreferences(), f.err
+}
+
+func (f *fakeAuthUsecase) UpdateNotificationPreferences(_ context.Context, _ string, marketing bool) (domain.NotificationPreferences, error) {
+	return domain.NotificationPreferences{Marketing: marketing, Security: true}, f.err
+}
+
+// fakeJwtHandler is jwtHandler authenticating every request as testParentUUID, who is admin having every scope
+type fakeJwtHandler struct{}
+
+func (fakeJwtHandler) ParseUUIDFromToken(c *gin.Context) {
+	c.Set("uuid", testParentUUID)
+	c.Next()
+}
+
+// passThrough is middleware calling next handler, returned from fakeJwtHandler for every requirement
+func passThrough(c *gin.Context) { c.Next() }
+
+func (fakeJwtHandler) RequireRole(_ string) gin.HandlerFunc                  { return passThrough }
+func (fakeJwtHandler) RequireScope(_ string) gin.HandlerFunc                 { return passThrough }
+func (fakeJwtHandler) RequireScopeIfPhoneCertified(_ string) gin.HandlerFunc { return passThrough }
+func (fakeJwtHandler) IntrospectToken(c *gin.Context)                        { c.Status(http.StatusOK) }
+func (fakeJwtHandler) NeedsRefresh(c *gin.Context)                           { c.Status(http.StatusOK) }
+func (fakeJwtHandler) WriteToken(_ *gin.Context, _ string) bool              { return true }
+
+// handlerRequest is request sent to handler in test case
+type handlerRequest struct {
+	method, path, body string
+}
+
+// handlerResult is response of handler recorded in golden file, having ref of internal error replaced with refPlaceholder
+type handlerResult struct {
+	Status int                    `json:"status"`
+	Header map[string]string      `json:"header,omitempty"`
+	Body   map[string]interface{} `json:"body"`
+}
+
+// refPlaceholder replace ref of internal error in golden file, since ref is random
+const refPlaceholder = "<ref>"
+
+// recordedHeaders is header recorded in golden file if it is set in response
+var recordedHeaders = []string{"Location", "Content-Disposition"}
+
+// usecaseErr return domain.UsecaseError having status & code, which message is used if catalog doesn't have it
+func usecaseErr(status, code int) domain.UsecaseError {
+	return domain.UsecaseError{UsecaseErr: errors.New("usecase error"), Status: status, Code: code}
+}
+
+// handlerTestCase is endpoint tested in TestAuthHandler, responses of which are recorded in testdata/{name}.golden.json
+type handlerTestCase struct {
+	name string
+	req  handlerRequest
+
+	// errs is error returned from usecase, each of which is tested in case named with status & code
+	errs []domain.UsecaseError
+
+	// fakes is case with fakeAuthUsecase set before sending req (ex, other success response)
+	fakes map[string]func(f *fakeAuthUsecase)
+
+	// reqs is case with other request sent instead of req (ex, bad request)
+	reqs map[string]handlerRequest
+}
+
+func TestAuthHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	phonePath := "/api/v1/phones/phone-number/" + testPhone
+	adminPhonePath := "/api/v1/admin/phones/phone-number/" + testPhone
+	certifyCodeBody := `{"certify_code":"123456"}`
+
+	tests := []handlerTestCase{{
+		name: "send_certify_code_to_phone",
+		req:  handlerRequest{http.MethodPost, phonePath + "/certify-code", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusBadRequest, domain.InvalidPhoneNumber),
+			usecaseErr(http.StatusBadRequest, domain.UnsupportedCertifyChannel),
+			usecaseErr(http.StatusConflict, domain.PhoneAlreadyInUse),
+			usecaseErr(http.StatusServiceUnavailable, domain.CertifyCodeSentButNotPersisted),
+			usecaseErr(http.StatusServiceUnavailable, domain.UnderMaintenance),
+			usecaseErr(http.StatusServiceUnavailable, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"invalid_phone_number": {http.MethodPost, "/api/v1/phones/phone-number/0101234/certify-code", ""},
+			"bad_request":          {http.MethodPost, phonePath + "/certify-code", `{"channel":"fax"}`},
+		},
+	}, {
+		name: "certify_phone_with_code",
+		req:  handlerRequest{http.MethodPost, phonePath + "/certification", certifyCodeBody},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.IncorrectCertifyCode),
+			usecaseErr(http.StatusConflict, domain.PhoneAlreadyCertified),
+			usecaseErr(http.StatusConflict, domain.PhoneOwnedByAnotherParent),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPost, phonePath + "/certification", `{}`},
+		},
+	}, {
+		name: "restart_certification",
+		req:  handlerRequest{http.MethodDelete, phonePath + "/certification", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.PhoneAlreadyCertified),
+			usecaseErr(http.StatusConflict, domain.PhoneAlreadyInUse),
+			usecaseErr(http.StatusTooManyRequests, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "verify_certify_code_only",
+		req:  handlerRequest{http.MethodPost, phonePath + "/verification", certifyCodeBody},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusTooManyRequests, domain.TooManyCodeAttempts),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "check_phone_eligibility",
+		req:  handlerRequest{http.MethodGet, phonePath + "/eligibility", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "sign_up_parent",
+		req:  handlerRequest{http.MethodPost, "/api/v1/parents", `{"id":"parent1","pw":"password","name":"name","phone_number":"01012345678"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusConflict, domain.ParentIDAlreadyInUse),
+			usecaseErr(http.StatusConflict, domain.ParentIDReservedByAnother),
+			usecaseErr(http.StatusConflict, domain.PhoneAlreadyInUse),
+			usecaseErr(http.StatusConflict, domain.UncertifiedPhone),
+			usecaseErr(http.StatusConflict, domain.TooManyAccountsForPhone),
+			usecaseErr(http.StatusConflict, domain.PasswordBreached),
+			usecaseErr(http.StatusServiceUnavailable, domain.UnderMaintenance),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPost, "/api/v1/parents", `{"id":"parent1","name":"name"}`},
+			"bad_profile_base64": {http.MethodPost, "/api/v1/parents",
+				`{"id":"parent1","pw":"password","name":"name","phone_number":"01012345678","profile_base64":"not base64!"}`},
+		},
+	}, {
+		name: "validate_sign_up_parent",
+		req:  handlerRequest{http.MethodPost, "/api/v1/parents/validate", `{"id":"parent1","pw":"pw","name":"name"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "login_parent_auth",
+		req:  handlerRequest{http.MethodPost, "/api/v1/login/parent", `{"id":"parent1","pw":"password","device_id":"device"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusConflict, domain.NotExistParentID),
+			usecaseErr(http.StatusConflict, domain.IncorrectParentPW),
+			usecaseErr(http.StatusConflict, domain.AccountLocked),
+			usecaseErr(http.StatusConflict, domain.AccountSuspended),
+			usecaseErr(http.StatusConflict, domain.TooManySessions),
+			usecaseErr(http.StatusInternalServerError, domain.TokenIssueFailed),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		fakes: map[string]func(f *fakeAuthUsecase){
+			"device_verification_required": func(f *fakeAuthUsecase) { f.verificationID = testCeremonyID },
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPost, "/api/v1/login/parent", `{"id":"parent1"}`},
+		},
+	}, {
+		name: "complete_device_verification",
+		req:  handlerRequest{http.MethodPost, "/api/v1/login/parent/device-verification", `{"verification_id":"` + testCeremonyID + `","certify_code":"123456"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.IncorrectCertifyCode),
+			usecaseErr(http.StatusConflict, domain.DeviceVerificationExpired),
+			usecaseErr(http.StatusConflict, domain.AccountLocked),
+			usecaseErr(http.StatusConflict, domain.AccountSuspended),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPost, "/api/v1/login/parent/device-verification", certifyCodeBody},
+		},
+	}, {
+		name: "begin_parent_passkey_login",
+		req:  handlerRequest{http.MethodPost, "/api/v1/login/parent/passkey/begin", `{"id":"parent1"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusConflict, domain.NotExistParentID),
+			usecaseErr(http.StatusConflict, domain.NoPasskeyRegistered),
+			usecaseErr(http.StatusNotImplemented, domain.PasskeyUnavailable),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "finish_parent_passkey_login",
+		req:  handlerRequest{http.MethodPost, "/api/v1/login/parent/passkey/finish", `{"ceremony_id":"` + testCeremonyID + `","response":{}}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.PasskeyCeremonyExpired),
+			usecaseErr(http.StatusConflict, domain.PasskeyVerificationFailed),
+			usecaseErr(http.StatusNotImplemented, domain.PasskeyUnavailable),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "check_if_parent_id_exist",
+		req:  handlerRequest{http.MethodGet, "/api/v1/parents/id/parent1/existence", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "reserve_parent_id",
+		req:  handlerRequest{http.MethodPost, "/api/v1/parents/id/parent1/reservation", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusConflict, domain.ParentIDAlreadyInUse),
+			usecaseErr(http.StatusConflict, domain.ParentIDReservedByAnother),
+			usecaseErr(http.StatusTooManyRequests, 0),
+			usecaseErr(http.StatusServiceUnavailable, domain.UnderMaintenance),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPost, "/api/v1/parents/id/abc/reservation", ""},
+		},
+	}, {
+		name: "get_parent_inform_by_uuid",
+		req:  handlerRequest{http.MethodGet, "/api/v1/parents/uuid/" + testParentUUID, ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"other_parent": {http.MethodGet, "/api/v1/parents/uuid/parent-222222222222", ""},
+		},
+	}, {
+		name: "update_parent_inform",
+		req:  handlerRequest{http.MethodPatch, "/api/v1/parents/uuid/" + testParentUUID, `{"name":"new name"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"other_parent":       {http.MethodPatch, "/api/v1/parents/uuid/parent-222222222222", `{"name":"new name"}`},
+			"bad_profile_base64": {http.MethodPatch, "/api/v1/parents/uuid/" + testParentUUID, `{"profile_base64":"not base64!"}`},
+		},
+	}, {
+		name: "change_parent_id",
+		req:  handlerRequest{http.MethodPut, "/api/v1/parents/me/id", `{"id":"parent2","pw":"password"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.IncorrectParentPW),
+			usecaseErr(http.StatusConflict, domain.ParentIDAlreadyInUse),
+			usecaseErr(http.StatusConflict, domain.ParentIDChangeTooSoon),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "change_parent_pw",
+		req:  handlerRequest{http.MethodPut, "/api/v1/parents/me/pw", `{"pw":"password","new_pw":"password2"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.IncorrectParentPW),
+			usecaseErr(http.StatusConflict, domain.PasswordBreached),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPut, "/api/v1/parents/me/pw", `{"pw":"password","new_pw":"pw"}`},
+		},
+	}, {
+		name: "verify_parent_password",
+		req:  handlerRequest{http.MethodPost, "/api/v1/parents/me/verify-password", `{"pw":"password"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusTooManyRequests, domain.TooManyPasswordAttempts),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "export_parent_data",
+		req:  handlerRequest{http.MethodGet, "/api/v1/parents/me/export", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusTooManyRequests, domain.DataExportTooSoon),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "link_phone_to_parent",
+		req:  handlerRequest{http.MethodPost, "/api/v1/parents/me/phone", `{"phone_number":"010-1234-5678","certify_code":"123456"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.IncorrectCertifyCode),
+			usecaseErr(http.StatusConflict, domain.ParentPhoneAlreadyLinked),
+			usecaseErr(http.StatusConflict, domain.PhoneAlreadyCertified),
+			usecaseErr(http.StatusConflict, domain.PhoneAlreadyInUse),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"invalid_phone_number": {http.MethodPost, "/api/v1/parents/me/phone", `{"phone_number":"0101234","certify_code":"123456"}`},
+		},
+	}, {
+		name: "send_step_up_code",
+		req:  handlerRequest{http.MethodPost, "/api/v1/parents/me/step-up/certify-code", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.UncertifiedPhone),
+			usecaseErr(http.StatusBadRequest, domain.InvalidPhoneNumber),
+			usecaseErr(http.StatusServiceUnavailable, domain.UnderMaintenance),
+			usecaseErr(http.StatusServiceUnavailable, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "step_up_with_phone_code",
+		req:  handlerRequest{http.MethodPost, "/api/v1/parents/me/step-up", certifyCodeBody},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.IncorrectCertifyCode),
+			usecaseErr(http.StatusConflict, domain.UncertifiedPhone),
+			usecaseErr(http.StatusTooManyRequests, domain.TooManyCodeAttempts),
+			usecaseErr(http.StatusInternalServerError, domain.TokenIssueFailed),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "get_parent_phone_certification",
+		req:  handlerRequest{http.MethodGet, "/api/v1/parents/me/phone/certifications", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "cancel_parent_phone_certification",
+		req:  handlerRequest{http.MethodDelete, "/api/v1/parents/me/phone/certifications", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "list_parent_sessions",
+		req:  handlerRequest{http.MethodGet, "/api/v1/parents/me/sessions?limit=10", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusBadRequest, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodGet, "/api/v1/parents/me/sessions?limit=1000", ""},
+		},
+	}, {
+		name: "count_active_sessions",
+		req:  handlerRequest{http.MethodGet, "/api/v1/parents/me/sessions/count", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "get_notification_preferences",
+		req:  handlerRequest{http.MethodGet, "/api/v1/parents/me/notification-preferences", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "update_notification_preferences",
+		req:  handlerRequest{http.MethodPut, "/api/v1/parents/me/notification-preferences", `{"marketing":false}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPut, "/api/v1/parents/me/notification-preferences", `{}`},
+		},
+	}, {
+		name: "begin_parent_passkey_registration",
+		req:  handlerRequest{http.MethodPost, "/api/v1/parents/me/passkeys/registration/begin", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotImplemented, domain.PasskeyUnavailable),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "finish_parent_passkey_registration",
+		req:  handlerRequest{http.MethodPost, "/api/v1/parents/me/passkeys/registration/finish", `{"ceremony_id":"` + testCeremonyID + `","response":{}}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.PasskeyCeremonyExpired),
+			usecaseErr(http.StatusConflict, domain.PasskeyVerificationFailed),
+			usecaseErr(http.StatusNotImplemented, domain.PasskeyUnavailable),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "list_parents",
+		req:  handlerRequest{http.MethodGet, "/api/v1/admin/parents", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusBadRequest, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "suspend_parent",
+		req:  handlerRequest{http.MethodPost, "/api/v1/admin/parents/uuid/" + testParentUUID + "/suspension", `{"reason":"spam"}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPost, "/api/v1/admin/parents/uuid/" + testParentUUID + "/suspension", `{}`},
+		},
+	}, {
+		name: "unsuspend_parent",
+		req:  handlerRequest{http.MethodDelete, "/api/v1/admin/parents/uuid/" + testParentUUID + "/suspension", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "unlock_parent_account",
+		req:  handlerRequest{http.MethodPost, "/api/v1/admin/parents/uuid/" + testParentUUID + "/unlock", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "get_phone_certify_statuses",
+		req:  handlerRequest{http.MethodPost, "/api/v1/admin/phones/status", `{"phone_numbers":["01012345678"]}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusBadRequest, domain.TooManyPhoneNumbers),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPost, "/api/v1/admin/phones/status", `{"phone_numbers":[]}`},
+		},
+	}, {
+		name: "force_certify_phone",
+		req:  handlerRequest{http.MethodPost, adminPhonePath + "/certify", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusNotFound, 0),
+			usecaseErr(http.StatusConflict, domain.PhoneAlreadyCertified),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "list_phone_certify_history",
+		req:  handlerRequest{http.MethodGet, adminPhonePath + "/history", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusBadRequest, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "get_auth_stats",
+		req:  handlerRequest{http.MethodGet, "/api/v1/admin/stats?from=2026-01-01&to=2026-01-31", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusBadRequest, 0),
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodGet, "/api/v1/admin/stats?from=2026/01/01&to=2026-01-31", ""},
+		},
+	}, {
+		name: "get_maintenance_mode",
+		req:  handlerRequest{http.MethodGet, "/api/v1/admin/maintenance", ""},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+	}, {
+		name: "set_maintenance_mode",
+		req:  handlerRequest{http.MethodPut, "/api/v1/admin/maintenance", `{"enabled":true}`},
+		errs: []domain.UsecaseError{
+			usecaseErr(http.StatusInternalServerError, 0),
+		},
+		reqs: map[string]handlerRequest{
+			"bad_request": {http.MethodPut, "/api/v1/admin/maintenance", `{}`},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := map[string]handlerResult{}
+			results["success"] = serveAuthHandler(t, &fakeAuthUsecase{}, tt.req)
+			results["unexpected_error"] = serveAuthHandler(t, &fakeAuthUsecase{err: errors.New("unexpected error")}, tt.req)
+			for _, ucErr := range tt.errs {
+				name := "usecase_error_" + domain.ErrorKey(ucErr.Status, ucErr.Code)
+				results[name] = serveAuthHandler(t, &fakeAuthUsecase{err: ucErr}, tt.req)
+			}
+			for name, setFake := range tt.fakes {
+				f := &fakeAuthUsecase{}
+				setFake(f)
+				results[name] = serveAuthHandler(t, f, tt.req)
+			}
+			for name, req := range tt.reqs {
+				results[name] = serveAuthHandler(t, &fakeAuthUsecase{}, req)
+			}
+			assertGolden(t, filepath.Join("testdata", tt.name+".golden.json"), results)
+		})
+	}
+}
+
+// serveAuthHandler serve req with engine routing authHandler using au & return result of response
+func serveAuthHandler(t *testing.T, au domain.AuthUsecase, req handlerRequest) handlerResult {
+	t.Helper()
+	r := gin.New()
+	NewAuthHandler(r.Group("api/v1"), au, validate.New(), fakeJwtHandler{})
+
+	var httpReq *http.Request
+	if req.body != "" {
+		httpReq = httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+		httpReq.Header.Set("Content-Type", "application/json")
+	} else {
+		httpReq = httptest.NewRequest(req.method, req.path, nil)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httpReq)
+
+	result := handlerResult{Status: w.Code}
+	if !assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result.Body), "body is not JSON: %s", w.Body.String()) {
+		return result
+	}
+	if ref, ok := result.Body["ref"].(string); ok {
+		result.Body["ref"] = refPlaceholder
+		result.Body["message"] = strings.ReplaceAll(result.Body["message"].(string), ref, refPlaceholder)
+	}
+	for _, key := range recordedHeaders {
+		if v := w.Header().Get(key); v != "" {
+			if result.Header == nil {
+				result.Header = map[string]string{}
+			}
+			result.Header[key] = v
+		}
+	}
+	return result
+}
+
+// assertGolden assert results is same as in golden file at path, or write results in it if update flag is set
+func assertGolden(t *testing.T, path string, results map[string]handlerResult) {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if !assert.NoError(t, enc.Encode(results)) {
+		return
+	}
+	got := buf.Bytes()
+
+	if *update {
+		assert.NoError(t, ioutil.WriteFile(path, got, 0644))
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if !assert.NoError(t, err, "golden file is not exist, run test with -update flag") {
+		return
+	}
+	assert.Equal(t, string(want), string(got))
+}
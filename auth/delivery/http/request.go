@@ -1,30 +1,107 @@
 package http
 
 import (
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 	"mime/multipart"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
 )
 
 // sendCertifyCodeToPhoneRequest is request for authHandler.SendCertifyCodeToPhone
+// phone number is get from path or body, and path take precedence if both are same
 type sendCertifyCodeToPhoneRequest struct {
-	PhoneNumber string `uri:"phone_number" validate:"required,len=11"`
+	PhoneNumber     string `uri:"phone_number" json:"-" validate:"required"`
+	BodyPhoneNumber string `json:"phone_number"`
+	Channel         string `json:"channel" validate:"omitempty,oneof=sms voice email"`
+	Email           string `json:"email" validate:"required_if=Channel email,omitempty,email"`
+	Purpose         string `json:"purpose" validate:"omitempty,oneof=sign_up phone_change"`
 }
 
 func (r *sendCertifyCodeToPhoneRequest) BindFrom(c *gin.Context) error {
-	return errors.Wrap(c.BindUri(r), "failed to BindUri")
+	if err := c.BindUri(r); err != nil {
+		return errors.Wrap(err, "failed to BindUri")
+	}
+
+	// body is optional, SMS channel is used if body is not set
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(r); err != nil {
+			return errors.Wrap(err, "failed to BindJSON")
+		}
+	}
+
+	path, body := domain.NormalizePhoneNumber(r.PhoneNumber), domain.NormalizePhoneNumber(r.BodyPhoneNumber)
+	if path != "" && body != "" && path != body {
+		return errors.New("phone_number in path and body are different")
+	}
+	if r.PhoneNumber = path; r.PhoneNumber == "" {
+		r.PhoneNumber = body
+	}
+	return nil
+}
+
+// certifyCode is certify code in request, accepting JSON number for client sending numeric code before as well
+type certifyCode string
+
+func (cc *certifyCode) UnmarshalJSON(b []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err == nil {
+		*cc = certifyCode(n)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.Wrap(err, "certify code must be string or number")
+	}
+	*cc = certifyCode(s)
+	return nil
 }
 
 // certifyPhoneWithCodeRequest is request for authHandler.CertifyPhoneWithCode
 type certifyPhoneWithCodeRequest struct {
-	PhoneNumber string `uri:"phone_number" validate:"required"`
-	CertifyCode int64  `json:"certify_code" validate:"required"`
+	PhoneNumber string      `uri:"phone_number" json:"-" validate:"required,len=11"`
+	CertifyCode certifyCode `json:"certify_code" validate:"required"`
 }
 
 func (r *certifyPhoneWithCodeRequest) BindFrom(c *gin.Context) error {
 	if err := c.BindUri(r); err != nil {
 		return errors.Wrap(err, "failed to BindUri")
 	}
+	r.PhoneNumber = domain.NormalizePhoneNumber(r.PhoneNumber)
+
+	if err := c.BindJSON(r); err != nil {
+		return errors.Wrap(err, "failed to BindJSON")
+	}
+	return nil
+}
+
+// restartCertificationRequest is request for authHandler.RestartCertification
+type restartCertificationRequest struct {
+	PhoneNumber string `uri:"phone_number" json:"-" validate:"required,len=11"`
+}
+
+func (r *restartCertificationRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindUri(r); err != nil {
+		return errors.Wrap(err, "failed to BindUri")
+	}
+	r.PhoneNumber = domain.NormalizePhoneNumber(r.PhoneNumber)
+	return nil
+}
+
+// verifyCertifyCodeOnlyRequest is request for authHandler.VerifyCertifyCodeOnly
+type verifyCertifyCodeOnlyRequest struct {
+	PhoneNumber string      `uri:"phone_number" json:"-" validate:"required,len=11"`
+	CertifyCode certifyCode `json:"certify_code" validate:"required"`
+}
+
+func (r *verifyCertifyCodeOnlyRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindUri(r); err != nil {
+		return errors.Wrap(err, "failed to BindUri")
+	}
+	r.PhoneNumber = domain.NormalizePhoneNumber(r.PhoneNumber)
 
 	if err := c.BindJSON(r); err != nil {
 		return errors.Wrap(err, "failed to BindJSON")
@@ -32,14 +109,28 @@ func (r *certifyPhoneWithCodeRequest) BindFrom(c *gin.Context) error {
 	return nil
 }
 
+// checkPhoneEligibilityRequest is request for authHandler.CheckPhoneEligibility
+type checkPhoneEligibilityRequest struct {
+	PhoneNumber string `uri:"phone_number" json:"-" validate:"required,len=11"`
+}
+
+func (r *checkPhoneEligibilityRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindUri(r); err != nil {
+		return errors.Wrap(err, "failed to BindUri")
+	}
+	r.PhoneNumber = domain.NormalizePhoneNumber(r.PhoneNumber)
+	return nil
+}
+
 // signUpParentRequest is request for authHandler.SignUpParent
 type signUpParentRequest struct {
 	ParentID      string                `form:"id" json:"id" validate:"required,min=4,max=20"`
 	ParentPW      string                `form:"pw" json:"pw" validate:"required,min=6,max=20"`
-	Name          string                `form:"name" json:"name" validate:"required,max=20"`
-	PhoneNumber   string                `form:"phone_number" json:"phone_number" validate:"required,len=11"`
-	Profile       *multipart.FileHeader `form:"profile"`
+	Name          string                `form:"name" json:"name" validate:"required,max=20,safe_text"`
+	PhoneNumber   string                `form:"phone_number" json:"phone_number" validate:"omitempty,len=11"` // required by usecase if certification is required
+	Profile       *multipart.FileHeader `form:"profile" json:"-"`
 	ProfileBase64 string                `json:"profile_base64"`
+	Reservation   string                `form:"id_reservation" json:"id_reservation" validate:"max=64"` // returned from ReserveParentID
 }
 
 func (r *signUpParentRequest) BindFrom(c *gin.Context) error {
@@ -52,26 +143,127 @@ func (r *signUpParentRequest) BindFrom(c *gin.Context) error {
 }
 
 type loginParentAuthRequest struct {
-	ID string `json:"id" validate:"required"`
-	PW string `json:"pw" validate:"required"`
+	ID        string `json:"id" validate:"required"`
+	PW        string `json:"pw" validate:"required"`
+	DeviceID  string `json:"device_id" validate:"max=100"`
+	Platform  string `json:"platform" validate:"max=20"`
+	UserAgent string `json:"-"`
 }
 
+// maxUserAgentLength is max length of user agent recorded with session (longer one is truncated)
+const maxUserAgentLength = 255
+
 func (r *loginParentAuthRequest) BindFrom(c *gin.Context) error {
+	if r.UserAgent = c.GetHeader("User-Agent"); len(r.UserAgent) > maxUserAgentLength {
+		r.UserAgent = r.UserAgent[:maxUserAgentLength]
+	}
+	return errors.Wrap(c.BindJSON(r), "failed to BindJSON")
+}
+
+// linkPhoneToParentRequest is request for authHandler.LinkPhoneToParent
+type linkPhoneToParentRequest struct {
+	PhoneNumber string      `json:"phone_number" validate:"required"`
+	CertifyCode certifyCode `json:"certify_code" validate:"required"`
+}
+
+func (r *linkPhoneToParentRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindJSON(r); err != nil {
+		return errors.Wrap(err, "failed to BindJSON")
+	}
+	r.PhoneNumber = domain.NormalizePhoneNumber(r.PhoneNumber)
+	return nil
+}
+
+// stepUpWithPhoneCodeRequest is request for authHandler.StepUpWithPhoneCode
+type stepUpWithPhoneCodeRequest struct {
+	CertifyCode certifyCode `json:"certify_code" validate:"required"`
+}
+
+func (r *stepUpWithPhoneCodeRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindJSON(r); err != nil {
+		return errors.Wrap(err, "failed to BindJSON")
+	}
+	return nil
+}
+
+// completeDeviceVerificationRequest is request for authHandler.CompleteDeviceVerification
+type completeDeviceVerificationRequest struct {
+	VerificationID string      `json:"verification_id" validate:"required,len=32"`
+	CertifyCode    certifyCode `json:"certify_code" validate:"required"`
+}
+
+func (r *completeDeviceVerificationRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindJSON(r); err != nil {
+		return errors.Wrap(err, "failed to BindJSON")
+	}
+	return nil
+}
+
+// finishPasskeyRegistrationRequest is request for authHandler.FinishParentPasskeyRegistration
+type finishPasskeyRegistrationRequest struct {
+	CeremonyID string          `json:"ceremony_id" validate:"required,len=32"`
+	Response   json.RawMessage `json:"response" validate:"required"`
+}
+
+func (r *finishPasskeyRegistrationRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindJSON(r), "failed to BindJSON")
+}
+
+// beginPasskeyLoginRequest is request for authHandler.BeginParentPasskeyLogin
+type beginPasskeyLoginRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+func (r *beginPasskeyLoginRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindJSON(r), "failed to BindJSON")
+}
+
+// finishPasskeyLoginRequest is request for authHandler.FinishParentPasskeyLogin
+type finishPasskeyLoginRequest struct {
+	CeremonyID string          `json:"ceremony_id" validate:"required,len=32"`
+	Response   json.RawMessage `json:"response" validate:"required"`
+	DeviceID   string          `json:"device_id" validate:"max=100"`
+	Platform   string          `json:"platform" validate:"max=20"`
+	UserAgent  string          `json:"-"`
+}
+
+func (r *finishPasskeyLoginRequest) BindFrom(c *gin.Context) error {
+	if r.UserAgent = c.GetHeader("User-Agent"); len(r.UserAgent) > maxUserAgentLength {
+		r.UserAgent = r.UserAgent[:maxUserAgentLength]
+	}
 	return errors.Wrap(c.BindJSON(r), "failed to BindJSON")
 }
 
 type getParentInformByIDRequest struct {
-	ParentID string `uri:"parent_id" validate:"required"`
+	ParentID string `uri:"parent_id" json:"-" validate:"required"`
 }
 
 func (r *getParentInformByIDRequest) BindFrom(c *gin.Context) error {
 	return errors.Wrap(c.BindUri(r), "failed to BindUri")
 }
 
+// getParentInformByUUIDRequest is request for authHandler.GetParentInformByUUID
+type getParentInformByUUIDRequest struct {
+	ParentUUID string `uri:"parent_uuid" json:"-" validate:"required"`
+}
+
+func (r *getParentInformByUUIDRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindUri(r), "failed to BindUri")
+}
+
+// reserveParentIDRequest is request for authHandler.ReserveParentID
+type reserveParentIDRequest struct {
+	ParentID string `uri:"parent_id" json:"-" validate:"required,min=4,max=20"`
+}
+
+func (r *reserveParentIDRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindUri(r), "failed to BindUri")
+}
+
 type updateParentInformRequest struct {
-	ParentUUID    string                `uri:"parent_uuid" validate:"required"`
-	Name          *string               `form:"name" json:"name" validate:"max=20"`
-	Profile       *multipart.FileHeader `form:"profile"`
+	ParentUUID    string                `uri:"parent_uuid" json:"-" validate:"required"`
+	Name          *string               `form:"name" json:"name" validate:"max=20,safe_text"`
+	Profile       *multipart.FileHeader `form:"profile" json:"-"`
 	ProfileBase64 string                `json:"profile_base64"`
 }
 
@@ -95,3 +287,174 @@ func (r *updateParentInformRequest) BindFrom(c *gin.Context) (err error) {
 	}
 	return
 }
+
+type suspendParentRequest struct {
+	ParentUUID string    `uri:"parent_uuid" json:"-" validate:"required"`
+	Reason     string    `json:"reason" validate:"required,max=100"`
+	Until      time.Time `json:"until"`
+}
+
+func (r *suspendParentRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindUri(r); err != nil {
+		return errors.Wrap(err, "failed to BindUri")
+	}
+	return errors.Wrap(c.BindJSON(r), "failed to BindJSON")
+}
+
+type unsuspendParentRequest struct {
+	ParentUUID string `uri:"parent_uuid" json:"-" validate:"required"`
+}
+
+func (r *unsuspendParentRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindUri(r), "failed to BindUri")
+}
+
+type unlockParentAccountRequest struct {
+	ParentUUID string `uri:"parent_uuid" json:"-" validate:"required"`
+}
+
+func (r *unlockParentAccountRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindUri(r), "failed to BindUri")
+}
+
+type changeParentIDRequest struct {
+	ParentID string `json:"id" validate:"required,min=4,max=20"`
+	ParentPW string `json:"pw" validate:"required"`
+}
+
+func (r *changeParentIDRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindJSON(r), "failed to BindJSON")
+}
+
+type changeParentPWRequest struct {
+	ParentPW    string `json:"pw" validate:"required"`
+	NewParentPW string `json:"new_pw" validate:"required,min=6,max=20"`
+}
+
+func (r *changeParentPWRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindJSON(r), "failed to BindJSON")
+}
+
+// verifyParentPasswordRequest is request for authHandler.VerifyParentPassword
+type verifyParentPasswordRequest struct {
+	ParentPW string `json:"pw" validate:"required"`
+}
+
+func (r *verifyParentPasswordRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindJSON(r), "failed to BindJSON")
+}
+
+type getPhoneCertifyStatusesRequest struct {
+	PhoneNumbers []string `json:"phone_numbers" validate:"required,min=1,dive,len=11"`
+}
+
+func (r *getPhoneCertifyStatusesRequest) BindFrom(c *gin.Context) error {
+	return errors.Wrap(c.BindJSON(r), "failed to BindJSON")
+}
+
+// setMaintenanceModeRequest is request for authHandler.SetMaintenanceMode
+type setMaintenanceModeRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
+
+func (r *setMaintenanceModeRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindJSON(r); err != nil {
+		return errors.Wrap(err, "failed to BindJSON")
+	}
+
+	// required tag can't reject nil pointer, since ValidateStruct initialize nil pointer before validating
+	if r.Enabled == nil {
+		return errors.New("enabled field is required")
+	}
+	return nil
+}
+
+// listRequest is request for list handler paginated with cursor (ex, authHandler.ListParents)
+type listRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// defaultListLimit is limit of list used if limit is not set in listRequest
+const defaultListLimit = 20
+
+func (r *listRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindQuery(r); err != nil {
+		return errors.Wrap(err, "failed to BindQuery")
+	}
+	if r.Limit == 0 {
+		r.Limit = defaultListLimit
+	}
+	return nil
+}
+
+// forceCertifyPhoneRequest is request for authHandler.ForceCertifyPhone
+type forceCertifyPhoneRequest struct {
+	PhoneNumber string `uri:"phone_number" json:"-" validate:"required,len=11"`
+}
+
+func (r *forceCertifyPhoneRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindUri(r); err != nil {
+		return errors.Wrap(err, "failed to BindUri")
+	}
+	r.PhoneNumber = domain.NormalizePhoneNumber(r.PhoneNumber)
+	return nil
+}
+
+// listPhoneCertifyHistoryRequest is request for authHandler.ListPhoneCertifyHistory
+type listPhoneCertifyHistoryRequest struct {
+	PhoneNumber string `uri:"phone_number" form:"-" json:"-" validate:"required,len=11"`
+	listRequest
+}
+
+func (r *listPhoneCertifyHistoryRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindUri(r); err != nil {
+		return errors.Wrap(err, "failed to BindUri")
+	}
+	r.PhoneNumber = domain.NormalizePhoneNumber(r.PhoneNumber)
+	return r.listRequest.BindFrom(c)
+}
+
+// getAuthStatsRequest is request for authHandler.GetAuthStats
+// from & to are date in UTC (2006-01-02), and both of them are included in range
+type getAuthStatsRequest struct {
+	From string `form:"from" validate:"required"`
+	To   string `form:"to" validate:"required"`
+
+	from, to time.Time
+}
+
+// statsDateLayout is layout of date in getAuthStatsRequest
+const statsDateLayout = "2006-01-02"
+
+func (r *getAuthStatsRequest) BindFrom(c *gin.Context) (err error) {
+	if err = c.BindQuery(r); err != nil {
+		return errors.Wrap(err, "failed to BindQuery")
+	}
+	if r.from, err = time.Parse(statsDateLayout, r.From); err != nil {
+		return errors.Wrap(err, "from must be date formatted as 2006-01-02")
+	}
+	if r.to, err = time.Parse(statsDateLayout, r.To); err != nil {
+		return errors.Wrap(err, "to must be date formatted as 2006-01-02")
+	}
+	r.to = r.to.AddDate(0, 0, 1)
+	return nil
+}
+
+// updateNotificationPreferencesRequest is request for authHandler.UpdateNotificationPreferences
+// security is not in request, since security message can't be opted out
+type updateNotificationPreferencesRequest struct {
+	Marketing *bool `json:"marketing" validate:"required"`
+}
+
+func (r *updateNotificationPreferencesRequest) BindFrom(c *gin.Context) error {
+	if err := c.BindJSON(r); err != nil {
+		return errors.Wrap(err, "failed to BindJSON")
+	}
+
+	// required tag can't reject nil pointer, since ValidateStruct initialize nil pointer before validating
+	if r.Marketing == nil {
+		return errors.New("marketing field is required")
+	}
+	return nil
+}
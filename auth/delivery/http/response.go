@@ -0,0 +1,267 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/locale"
+)
+
+// response is response envelope having status, code, message inform, embedded in every response of handler
+// response having more field embed it, to keep status, code, message field in top level of body
+type response struct {
+	Status  int    `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+
+	// Key is stable key of error, which message is localized with (set only in error response)
+	Key string `json:"key,omitempty"`
+
+	// Ref is reference id of internal error, set only in response of internal error
+	Ref string `json:"ref,omitempty"`
+
+	// APIVersion is version of API responding, which is same as version in prefix of route (ex, /api/v1)
+	APIVersion string `json:"api_version"`
+
+	// Detail is structured detail of error (ex, next step of client), set only in response of usecase error having it
+	Detail interface{} `json:"detail,omitempty"`
+}
+
+// APIVersion is version of auth API, routes of which should be mounted under /api/{APIVersion}
+// it is changed only with breaking change of API, so that old client keep using routes of old version
+const APIVersion = "v1"
+
+// defaultResp return response have status, code, message inform
+func defaultResp(status, code int, msg string) response {
+	return response{Status: status, Code: code, Message: msg, APIVersion: APIVersion}
+}
+
+// localizedResp return response with message localized in locale negotiated from Accept-Language of c
+// msg is used as it is if catalog doesn't have message about key of status & code
+func localizedResp(c *gin.Context, status, code int, msg string) response {
+	key := domain.ErrorKey(status, code)
+	if localized, ok := locale.Message(locale.Negotiate(c.GetHeader("Accept-Language")), key); ok {
+		msg = localized
+	}
+	resp := defaultResp(status, code, msg)
+	resp.Key = key
+	return resp
+}
+
+// badRequestResp return 400 response about err returned from binding or validating request, with localized message
+func badRequestResp(c *gin.Context, err error) response {
+	if msg, ok := locale.ValidationMessage(locale.Negotiate(c.GetHeader("Accept-Language")), err); ok {
+		resp := defaultResp(http.StatusBadRequest, 0, msg)
+		resp.Key = domain.ErrorKey(http.StatusBadRequest, 0)
+		return resp
+	}
+	return localizedResp(c, http.StatusBadRequest, 0, err.Error())
+}
+
+// internalErrorResp log err with generated reference id & return 500 response having the id instead of err,
+// so that internal error (ex, DB error text) is not exposed to client, and operator find log with the id
+func internalErrorResp(c *gin.Context, err error) response {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	ref := hex.EncodeToString(b)
+
+	log.Printf("internal error, ref: %s, err: %v", ref, err)
+	resp := localizedResp(c, http.StatusInternalServerError, 0, "internal error,")
+	resp.Message += " ref: " + ref
+	resp.Ref = ref
+	return resp
+}
+
+// usecaseErrorResp return localized response about domain.UsecaseError, hiding error with internalErrorResp if status is 5xx
+// 503 with code is not hidden, since it represent expected state of service (ex, maintenance mode) rather than error
+func usecaseErrorResp(c *gin.Context, err domain.UsecaseError) response {
+	if err.Status == http.StatusServiceUnavailable && err.Code != 0 {
+		return localizedResp(c, err.Status, err.Code, err.Error())
+	}
+	if err.Status >= http.StatusInternalServerError {
+		resp := internalErrorResp(c, err)
+		resp.Status, resp.Code, resp.Key = err.Status, err.Code, domain.ErrorKey(err.Status, err.Code)
+		return resp
+	}
+	resp := localizedResp(c, err.Status, err.Code, err.Error())
+	resp.Detail = err.Detail
+	return resp
+}
+
+// certifyPhoneWithCodeResponse is response for authHandler.CertifyPhoneWithCode
+// InUse represent if phone is linked to parent, so that client go to login if true, or sign up if false
+type certifyPhoneWithCodeResponse struct {
+	response
+	Certified bool `json:"certified"`
+	InUse     bool `json:"in_use"`
+}
+
+// getParentInformByUUIDResponse is response for authHandler.GetParentInformByUUID (password hash is excluded)
+type getParentInformByUUIDResponse struct {
+	response
+	ParentUUID string `json:"parent_uuid"`
+	ParentID   string `json:"parent_id"`
+	Name       string `json:"name"`
+	ProfileUri string `json:"profile_uri,omitempty"`
+}
+
+// listResponse is response envelope of list paginated with cursor, having cursor of next & prev page
+type listResponse struct {
+	response
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// listParentsResponse is response for authHandler.ListParents
+type listParentsResponse struct {
+	listResponse
+	Parents []parentSummary `json:"parents"`
+}
+
+// parentSummary is parent in parent list (password hash is excluded)
+type parentSummary struct {
+	UUID      string `json:"uuid"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	Suspended bool   `json:"suspended"`
+}
+
+// listParentSessionsResponse is response for authHandler.ListParentSessions
+type listParentSessionsResponse struct {
+	listResponse
+	Sessions []parentSession `json:"sessions"`
+}
+
+// countActiveSessionsResponse is response for authHandler.CountActiveSessions
+type countActiveSessionsResponse struct {
+	response
+	Count int `json:"count"`
+}
+
+// parentSession is session in parent session list
+type parentSession struct {
+	UUID       string            `json:"uuid"`
+	DeviceID   string            `json:"device_id,omitempty"`
+	UserAgent  string            `json:"user_agent,omitempty"`
+	Platform   string            `json:"platform,omitempty"`
+	CreatedAt  *domain.Timestamp `json:"created_at"`
+	LastUsedAt *domain.Timestamp `json:"last_used_at"`
+}
+
+// listPhoneCertifyHistoryResponse is response for authHandler.ListPhoneCertifyHistory
+type listPhoneCertifyHistoryResponse struct {
+	listResponse
+	Events []phoneCertifyEvent `json:"events"`
+}
+
+// phoneCertifyEvent is event in certify history of phone
+type phoneCertifyEvent struct {
+	Type       string            `json:"type"`
+	Channel    string            `json:"channel,omitempty"`
+	OccurredAt *domain.Timestamp `json:"occurred_at"`
+}
+
+// exportParentDataResponse is response for authHandler.ExportParentData
+type exportParentDataResponse struct {
+	response
+	Data domain.ParentDataExport `json:"data"`
+}
+
+// verifyCertifyCodeOnlyResponse is response for authHandler.VerifyCertifyCodeOnly
+type verifyCertifyCodeOnlyResponse struct {
+	response
+	Matched bool `json:"matched"`
+}
+
+// verifyParentPasswordResponse is response for authHandler.VerifyParentPassword
+type verifyParentPasswordResponse struct {
+	response
+	Matched bool `json:"matched"`
+}
+
+// maintenanceModeResponse is response for authHandler.GetMaintenanceMode, authHandler.SetMaintenanceMode
+type maintenanceModeResponse struct {
+	response
+	Enabled bool `json:"enabled"`
+}
+
+// checkPhoneEligibilityResponse is response for authHandler.CheckPhoneEligibility
+type checkPhoneEligibilityResponse struct {
+	response
+	Eligible bool   `json:"eligible"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// reserveParentIDResponse is response for authHandler.ReserveParentID
+type reserveParentIDResponse struct {
+	response
+	Reservation string            `json:"id_reservation"`
+	ExpiresAt   *domain.Timestamp `json:"expires_at"`
+}
+
+// signUpParentResponse is response for authHandler.SignUpParent
+type signUpParentResponse struct {
+	response
+	ParentUUID string `json:"parent_uuid,omitempty"`
+}
+
+// validateSignUpParentResponse is response for authHandler.ValidateSignUpParent
+type validateSignUpParentResponse struct {
+	response
+	Valid   bool              `json:"valid"`
+	Invalid map[string]string `json:"invalid"`
+}
+
+// beginPasskeyCeremonyResponse is response for authHandler.BeginParentPasskeyRegistration & BeginParentPasskeyLogin
+// Options is passed to authenticator of client (navigator.credentials) as it is
+type beginPasskeyCeremonyResponse struct {
+	response
+	CeremonyID string          `json:"ceremony_id"`
+	Options    json.RawMessage `json:"options"`
+}
+
+// loginParentAuthResponse is response for authHandler.LoginParentAuth
+type loginParentAuthResponse struct {
+	response
+	UUID  string `json:"uuid,omitempty"`
+	Token string `json:"token,omitempty"`
+
+	// VerificationID is set instead of Token if login from unrecognized device require verification
+	VerificationID string `json:"verification_id,omitempty"`
+}
+
+// stepUpWithPhoneCodeResponse is response for authHandler.StepUpWithPhoneCode
+type stepUpWithPhoneCodeResponse struct {
+	response
+	Token string `json:"token,omitempty"`
+}
+
+// getParentPhoneCertificationResponse is response for authHandler.GetParentPhoneCertification
+type getParentPhoneCertificationResponse struct {
+	response
+	domain.PhoneCertifyStatus
+}
+
+// getPhoneCertifyStatusesResponse is response for authHandler.GetPhoneCertifyStatuses
+type getPhoneCertifyStatusesResponse struct {
+	response
+	Statuses []domain.PhoneCertifyStatus `json:"statuses"`
+}
+
+// getAuthStatsResponse is response for authHandler.GetAuthStats
+type getAuthStatsResponse struct {
+	response
+	domain.AuthStats
+}
+
+// notificationPreferencesResponse is response for authHandler.GetNotificationPreferences & UpdateNotificationPreferences
+type notificationPreferencesResponse struct {
+	response
+	domain.NotificationPreferences
+}
@@ -0,0 +1,23 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// selectPage return b limited to page of cursor ordered by key column (limit + 1 row is selected to know more page)
+// row selected in prev direction is in descending order, so it must be reversed with reverse function of caller
+func selectPage(b squirrel.SelectBuilder, key string, cursor domain.Cursor, limit int) squirrel.SelectBuilder {
+	if cursor.IsPrev() {
+		if cursor.Key != "" {
+			b = b.Where(squirrel.Lt{key: cursor.Key})
+		}
+		return b.OrderBy(key + " DESC").Limit(uint64(limit + 1))
+	}
+
+	if cursor.Key != "" {
+		b = b.Where(squirrel.Gt{key: cursor.Key})
+	}
+	return b.OrderBy(key).Limit(uint64(limit + 1))
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"strings"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// uniqueKeys is domain.DuplicateKey of each unique key (except primary key) by table, keyed by name of key in DB
+// unique key added in migration must be added here, or it is reported as domain.DuplicateKeyUnknown
+var uniqueKeys = map[string]map[string]domain.DuplicateKey{
+	"parent_auth":          {"id": domain.DuplicateKeyParentID},
+	"parent_phone_certify": {"parent_uuid": domain.DuplicateKeyParentUUID},
+}
+
+// duplicateKey return domain.DuplicateKey of key in table, which is name of key parsed from entry duplicate error
+// name is prefixed with table since MySQL 8.0.19 (ex, parent_auth.id), so the prefix is trimmed before mapping
+func duplicateKey(table, key string) domain.DuplicateKey {
+	key = strings.TrimPrefix(key, table+".")
+	if key == "PRIMARY" {
+		return domain.DuplicateKeyPrimary
+	}
+	if dk, ok := uniqueKeys[table][key]; ok {
+		return dk
+	}
+	return domain.DuplicateKeyUnknown
+}
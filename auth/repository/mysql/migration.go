@@ -0,0 +1,16 @@
+package mysql
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// Migrations return versioned migration of auth tables, which can be applied with migrate.Up
+// table not existing is created in constructor of repository also, so migration create table only if not exist
+func Migrations() fs.FS {
+	sub, _ := fs.Sub(migrationFS, "migrations")
+	return sub
+}
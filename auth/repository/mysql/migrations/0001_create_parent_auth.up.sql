@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS parent_auth (
+	uuid        CHAR(11)  NOT NULL,
+	id          VARCHAR(20)  NOT NULL UNIQUE,
+	pw          VARCHAR(100) NOT NULL,
+	name        VARCHAR(10)  NOT NULL,
+	profile_uri VARCHAR(100),
+	role            VARCHAR(10)  NOT NULL DEFAULT 'parent',
+	suspended       TINYINT      NOT NULL DEFAULT 0,
+	suspend_reason  VARCHAR(100),
+	suspended_until DATETIME,
+	id_changed_at   DATETIME,
+	pw_changed_at   DATETIME,
+	failed_login_count  INT NOT NULL DEFAULT 0,
+	locked_until        DATETIME,
+	lockout_notified_at DATETIME,
+	PRIMARY KEY (uuid)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS parent_phone_certify (
+	parent_uuid  CHAR(11) UNIQUE,
+	phone_number CHAR(11) NOT NULL,
+	certify_code VARCHAR(10) NOT NULL,
+	certified    TINYINT  NOT NULL DEFAULT 0,
+	PRIMARY KEY (phone_number),
+	FOREIGN KEY (parent_uuid)
+		REFERENCES parent_auth(uuid)
+		ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS parent_session (
+	uuid         CHAR(11)     NOT NULL,
+	parent_uuid  CHAR(11)     NOT NULL,
+	device_id    VARCHAR(100),
+	user_agent   VARCHAR(255),
+	platform     VARCHAR(20),
+	created_at   DATETIME     NOT NULL,
+	last_used_at DATETIME     NOT NULL,
+	PRIMARY KEY (uuid),
+	INDEX (parent_uuid, device_id),
+	FOREIGN KEY (parent_uuid)
+		REFERENCES parent_auth(uuid)
+		ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+	id         BIGINT       NOT NULL AUTO_INCREMENT,
+	actor_uuid CHAR(11)     NOT NULL,
+	action     VARCHAR(50)  NOT NULL,
+	target     VARCHAR(100),
+	created_at DATETIME     NOT NULL,
+	PRIMARY KEY (id),
+	INDEX (actor_uuid)
+);
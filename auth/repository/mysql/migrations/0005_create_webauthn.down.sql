@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS webauthn_ceremony;
+
+DROP TABLE IF EXISTS parent_webauthn_credential;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS parent_webauthn_credential (
+	id           VARCHAR(255) NOT NULL,
+	parent_uuid  CHAR(11)     NOT NULL,
+	public_key   BLOB         NOT NULL,
+	sign_count   BIGINT       NOT NULL DEFAULT 0,
+	created_at   DATETIME     NOT NULL,
+	last_used_at DATETIME,
+	PRIMARY KEY (id),
+	INDEX (parent_uuid),
+	FOREIGN KEY (parent_uuid)
+		REFERENCES parent_auth(uuid)
+		ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS webauthn_ceremony (
+	id          CHAR(32)    NOT NULL,
+	parent_uuid CHAR(11)    NOT NULL,
+	type        VARCHAR(20) NOT NULL,
+	session     BLOB        NOT NULL,
+	expires_at  DATETIME    NOT NULL,
+	PRIMARY KEY (id),
+	FOREIGN KEY (parent_uuid)
+		REFERENCES parent_auth(uuid)
+		ON DELETE CASCADE
+);
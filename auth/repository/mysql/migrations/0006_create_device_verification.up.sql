@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS device_verification (
+	id          CHAR(32)     NOT NULL,
+	parent_uuid CHAR(11)     NOT NULL,
+	device_id   VARCHAR(100),
+	user_agent  VARCHAR(255),
+	platform    VARCHAR(20),
+	expires_at  DATETIME     NOT NULL,
+	PRIMARY KEY (id),
+	FOREIGN KEY (parent_uuid)
+		REFERENCES parent_auth(uuid)
+		ON DELETE CASCADE
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS auth_event (
+	id          BIGINT      NOT NULL AUTO_INCREMENT,
+	type        VARCHAR(20) NOT NULL,
+	occurred_at DATETIME    NOT NULL,
+	PRIMARY KEY (id),
+	INDEX (occurred_at, type)
+);
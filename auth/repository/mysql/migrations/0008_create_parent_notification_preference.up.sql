@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS parent_notification_preference (
+	parent_uuid CHAR(11) NOT NULL,
+	marketing   TINYINT  NOT NULL DEFAULT 1,
+	PRIMARY KEY (parent_uuid),
+	FOREIGN KEY (parent_uuid)
+		REFERENCES parent_auth(uuid)
+		ON DELETE CASCADE
+);
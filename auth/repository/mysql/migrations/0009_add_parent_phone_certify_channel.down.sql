@@ -0,0 +1,3 @@
+ALTER TABLE parent_phone_certify
+	DROP COLUMN sent_channel,
+	DROP COLUMN preferred_channel;
@@ -0,0 +1,3 @@
+ALTER TABLE parent_phone_certify
+	ADD COLUMN sent_channel      VARCHAR(10),
+	ADD COLUMN preferred_channel VARCHAR(10);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS phone_certify_event (
+	id           BIGINT      NOT NULL AUTO_INCREMENT,
+	phone_number CHAR(11)    NOT NULL,
+	type         VARCHAR(20) NOT NULL,
+	channel      VARCHAR(10),
+	occurred_at  DATETIME    NOT NULL,
+	PRIMARY KEY (id),
+	INDEX (phone_number, id)
+);
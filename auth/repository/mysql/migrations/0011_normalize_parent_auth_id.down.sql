@@ -0,0 +1,2 @@
+-- original letter case of ID isn't kept, so nothing is reverted
+SELECT 1;
@@ -0,0 +1,7 @@
+-- convert mixed-case ID stored before case-insensitive ID to lower case
+-- colliding ID is not converted (must be resolved manually), since one of them can't be stored with unique constraint
+-- colliding ID is found with: SELECT LOWER(id), GROUP_CONCAT(id) FROM parent_auth GROUP BY LOWER(id) HAVING COUNT(*) > 1
+UPDATE parent_auth SET id = LOWER(id) WHERE BINARY id <> BINARY LOWER(id)
+	AND LOWER(id) NOT IN (SELECT normalized_id FROM (
+		SELECT LOWER(id) AS normalized_id FROM parent_auth GROUP BY LOWER(id) HAVING COUNT(*) > 1
+	) AS collision);
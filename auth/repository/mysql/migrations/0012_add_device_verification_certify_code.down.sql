@@ -0,0 +1,2 @@
+ALTER TABLE device_verification
+	DROP COLUMN certify_code;
@@ -0,0 +1,2 @@
+ALTER TABLE device_verification
+	ADD COLUMN certify_code VARCHAR(10) NOT NULL DEFAULT '' AFTER platform;
@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"log"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// auditLogRepository is implementation of domain.AuditLogRepository using mysql
+type auditLogRepository struct {
+	myCfg auditLogRepositoryConfig
+
+	db        *sqlx.DB
+	migrator  migrator
+	validator validator
+
+	// phoneNumberCipher is used for rewriting plaintext phone number in target into hash of it
+	phoneNumberCipher phoneNumberCipher
+}
+
+// AuditLogRepository return implementation of domain.AuditLogRepository using mysql
+func AuditLogRepository(
+	cfg auditLogRepositoryConfig,
+	db *sqlx.DB,
+	v validator,
+	pc phoneNumberCipher,
+) domain.AuditLogRepository {
+	repo := &auditLogRepository{
+		myCfg:             cfg,
+		db:                db,
+		validator:         v,
+		phoneNumberCipher: pc,
+	}
+
+	if err := repo.migrator.MigrateModel(repo.db, domain.AuditLog{}); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate audit log").Error())
+	}
+	if err := repo.migratePhoneNumberTarget(); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate plaintext phone number target of audit log").Error())
+	}
+	return repo
+}
+
+// migratePhoneNumberTarget method rewrite plaintext phone number in target of audit log into hash of it
+// target stored before is found with action about phone number & absence of domain.AuditTargetPhoneNumberPrefix,
+// and it is rewritten only if still same as selected, so that it isn't hashed twice by other instance migrating
+func (ar *auditLogRepository) migratePhoneNumberTarget() (err error) {
+	_tx, err := ar.db.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer func() { _ = _tx.Rollback() }()
+
+	var rows []struct {
+		ID     int64  `db:"id"`
+		Target string `db:"target"`
+	}
+	if err = _tx.Select(&rows, `SELECT id, target FROM audit_log WHERE action = ? AND target NOT LIKE ? FOR UPDATE`,
+		domain.AuditActionForceCertifyPhone, domain.AuditTargetPhoneNumberPrefix+"%"); err != nil {
+		return errors.Wrap(err, "failed to select plaintext phone number target")
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for _, row := range rows {
+		target := domain.AuditTargetPhoneNumberPrefix + ar.phoneNumberCipher.Hash(domain.NormalizePhoneNumber(row.Target))
+		if _, err = _tx.Exec(`UPDATE audit_log SET target = ? WHERE id = ? AND target = ?`, target, row.ID, row.Target); err != nil {
+			return errors.Wrap(err, "failed to update target into hash of phone number")
+		}
+	}
+	if err = _tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit hash of phone number target")
+	}
+	log.Printf("%d plaintext phone number target of audit log is hashed", len(rows))
+	return nil
+}
+
+// auditLogRepositoryConfig is interface get config value for audit log repository
+type auditLogRepositoryConfig interface{}
+
+// GetByActorUUIDOrTarget is implement domain.AuditLogRepository interface
+// return audit log performed by actorUUID or performed to one of targets, in order of created time
+func (ar *auditLogRepository) GetByActorUUIDOrTarget(ctx tx.Context, actorUUID string, targets []string) (als []domain.AuditLog, err error) {
+	cond := squirrel.Or{squirrel.Eq{"actor_uuid": actorUUID}}
+	if len(targets) != 0 {
+		cond = append(cond, squirrel.Eq{"target": targets})
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("*").From("audit_log").Where(cond).OrderBy("created_at").ToSql()
+
+	if err = _tx.Select(&als, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select audit log list return unexpected error")
+	}
+	return
+}
+
+// Store is implement domain.AuditLogRepository interface
+func (ar *auditLogRepository) Store(ctx tx.Context, al *domain.AuditLog) (err error) {
+	if err = ar.validator.ValidateStruct(al); err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.Wrap(err, "failed to validate domain.AuditLog")}
+		return
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Insert("audit_log").
+		Columns("actor_uuid", "action", "target", "created_at").
+		Values(al.ActorUUID, al.Action, al.Target, al.CreatedAt).ToSql()
+
+	result, err := _tx.Exec(_sql, args...)
+	if err != nil {
+		err = errors.Wrap(err, "insert audit log return unexpected error")
+		return
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		al.ID = domain.Int64(id)
+	}
+	return
+}
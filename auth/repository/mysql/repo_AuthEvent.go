@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// authEventRepository is implementation of domain.AuthEventRepository using mysql
+type authEventRepository struct {
+	myCfg authEventRepositoryConfig
+
+	db        *sqlx.DB
+	migrator  migrator
+	validator validator
+}
+
+// AuthEventRepository return implementation of domain.AuthEventRepository using mysql
+func AuthEventRepository(
+	cfg authEventRepositoryConfig,
+	db *sqlx.DB,
+	v validator,
+) domain.AuthEventRepository {
+	repo := &authEventRepository{
+		myCfg:     cfg,
+		db:        db,
+		validator: v,
+	}
+
+	if err := repo.migrator.MigrateModel(repo.db, domain.AuthEvent{}); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate auth event").Error())
+	}
+	return repo
+}
+
+// authEventRepositoryConfig is interface get config value for auth event repository
+type authEventRepositoryConfig interface{}
+
+// CountByType is implement domain.AuthEventRepository interface
+// return count of auth event occurred in [from, to) by type, which is aggregated in db with index of occurred_at
+func (ar *authEventRepository) CountByType(ctx tx.Context, from, to time.Time) (counts map[string]int, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("type", "COUNT(*) AS count").From("auth_event").
+		Where("occurred_at >= ? AND occurred_at < ?", from, to).GroupBy("type").ToSql()
+
+	var rows []struct {
+		Type  string `db:"type"`
+		Count int    `db:"count"`
+	}
+	if err = _tx.Select(&rows, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select count of auth event return unexpected error")
+		return
+	}
+
+	counts = make(map[string]int, len(rows))
+	for _, r := range rows {
+		counts[r.Type] = r.Count
+	}
+	return
+}
+
+// Store is implement domain.AuthEventRepository interface
+func (ar *authEventRepository) Store(ctx tx.Context, ae *domain.AuthEvent) (err error) {
+	if err = ar.validator.ValidateStruct(ae); err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.Wrap(err, "failed to validate domain.AuthEvent")}
+		return
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Insert("auth_event").
+		Columns("type", "occurred_at").
+		Values(ae.Type, ae.OccurredAt).ToSql()
+
+	result, err := _tx.Exec(_sql, args...)
+	if err != nil {
+		err = errors.Wrap(err, "insert auth event return unexpected error")
+		return
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		ae.ID = domain.Int64(id)
+	}
+	return
+}
@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"log"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// deviceVerificationRepository is implementation of domain.DeviceVerificationRepository using mysql
+type deviceVerificationRepository struct {
+	myCfg deviceVerificationRepositoryConfig
+
+	db           *sqlx.DB
+	migrator     migrator
+	sqlMsgParser sqlMsgParser
+	validator    validator
+}
+
+// DeviceVerificationRepository return implementation of domain.DeviceVerificationRepository using mysql
+func DeviceVerificationRepository(
+	cfg deviceVerificationRepositoryConfig,
+	db *sqlx.DB,
+	sp sqlMsgParser,
+	v validator,
+) domain.DeviceVerificationRepository {
+	repo := &deviceVerificationRepository{
+		myCfg:        cfg,
+		db:           db,
+		sqlMsgParser: sp,
+		validator:    v,
+	}
+
+	if err := repo.migrator.MigrateModel(repo.db, domain.DeviceVerification{}); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate device verification").Error())
+	}
+	if err := repo.migrateCertifyCodeColumn(); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate certify code column of device verification").Error())
+	}
+	return repo
+}
+
+// migrateCertifyCodeColumn method add certify_code column to device_verification table created before it
+// verification stored before has empty code, which is regarded as expired in usecase
+func (dr *deviceVerificationRepository) migrateCertifyCodeColumn() (err error) {
+	var exist bool
+	if err = dr.db.Get(&exist, `SELECT COUNT(*) > 0 FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'device_verification' AND COLUMN_NAME = 'certify_code'`); err != nil {
+		return errors.Wrap(err, "failed to select certify_code column")
+	}
+	if exist {
+		return nil
+	}
+
+	_, err = dr.db.Exec(`ALTER TABLE device_verification ADD COLUMN certify_code VARCHAR(10) NOT NULL DEFAULT '' AFTER platform`)
+	return errors.Wrap(err, "failed to add certify_code column")
+}
+
+// deviceVerificationRepositoryConfig is interface get config value for device verification repository
+type deviceVerificationRepositoryConfig interface{}
+
+// GetByID is implement domain.DeviceVerificationRepository interface
+func (dr *deviceVerificationRepository) GetByID(ctx tx.Context, id string) (dv domain.DeviceVerification, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("*").From("device_verification").Where("id = ?", id).ToSql()
+
+	switch err = _tx.Get(&dv, _sql, args...); err {
+	case nil:
+		break
+	case sql.ErrNoRows:
+		err = domain.ErrRowNotExist{RepoErr: errors.Wrap(err, "failed to select device verification")}
+	default:
+		err = errors.Wrap(err, "select device verification return unexpected error")
+	}
+	return
+}
+
+// Store is implement domain.DeviceVerificationRepository interface
+func (dr *deviceVerificationRepository) Store(ctx tx.Context, dv *domain.DeviceVerification) (err error) {
+	if domain.StringValue(dv.ID) == "" {
+		dv.ID = domain.String(dv.GenerateRandomID())
+	}
+
+	if err = dr.validator.ValidateStruct(dv); err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.Wrap(err, "failed to validate domain.DeviceVerification")}
+		return
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Insert("device_verification").
+		Columns("id", "parent_uuid", "device_id", "user_agent", "platform", "certify_code", "expires_at").
+		Values(dv.ID, dv.ParentUUID, dv.DeviceID, dv.UserAgent, dv.Platform, dv.CertifyCode, dv.ExpiresAt).ToSql()
+
+	switch _, err = _tx.Exec(_sql, args...); tErr := err.(type) {
+	case nil:
+		break
+	case *mysql.MySQLError:
+		switch tErr.Number {
+		case mysqlerr.ER_NO_REFERENCED_ROW_2:
+			err = errors.Wrap(err, "failed to insert device verification")
+			fk := dr.sqlMsgParser.NoReferencedRow(tErr.Message)
+			err = domain.ErrNoReferencedRow{RepoErr: err, ForeignKey: fk}
+		default:
+			err = errors.Wrap(err, "insert device verification return unexpected code return")
+		}
+	default:
+		err = errors.Wrap(err, "insert device verification return unexpected error type")
+	}
+	return
+}
+
+// Delete is implement domain.DeviceVerificationRepository interface
+func (dr *deviceVerificationRepository) Delete(ctx tx.Context, id string) (err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Delete("device_verification").Where("id = ?", id).ToSql()
+
+	if _, err = _tx.Exec(_sql, args...); err != nil {
+		err = errors.Wrap(err, "delete device verification return unexpected error")
+	}
+	return
+}
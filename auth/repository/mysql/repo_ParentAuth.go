@@ -21,6 +21,9 @@ type parentAuthRepository struct {
 	migrator     migrator
 	sqlMsgParser sqlMsgParser
 	validator    validator
+
+	// phoneNumberCipher is used for decrypting phone number linked to parent auth
+	phoneNumberCipher phoneNumberCipher
 }
 
 // parentAuthRepositoryConfig is interface get config value for parent auth repository
@@ -37,40 +40,99 @@ type validator interface {
 	ValidateStruct(s interface{}) (err error)
 }
 
+// phoneNumberCipher is interface used for storing phone number as hash for lookup & encrypted value, not as plaintext
+type phoneNumberCipher interface {
+	// Hash return deterministic hash of phone number, so that row is looked up with it
+	Hash(pn string) string
+
+	// Encrypt return phone number encrypted, which is decrypted with Decrypt
+	Encrypt(pn string) ([]byte, error)
+
+	// Decrypt return phone number decrypted from value returned by Encrypt
+	Decrypt(encrypted []byte) (string, error)
+}
+
 // ParentAuthRepository return implementation of domain.ParentAuthRepository using mysql
 func ParentAuthRepository(
 	cfg parentAuthRepositoryConfig,
 	db *sqlx.DB,
 	sp sqlMsgParser,
 	v validator,
+	pc phoneNumberCipher,
 ) domain.ParentAuthRepository {
 	repo := &parentAuthRepository{
-		myCfg:        cfg,
-		db:           db,
-		sqlMsgParser: sp,
-		validator:    v,
+		myCfg:             cfg,
+		db:                db,
+		sqlMsgParser:      sp,
+		validator:         v,
+		phoneNumberCipher: pc,
 	}
 
 	if err := repo.migrator.MigrateModel(repo.db, domain.ParentAuth{}); err != nil {
 		log.Fatal(errors.Wrap(err, "failed to migrate parent auth model").Error())
 	}
+	if err := repo.migrateLockoutColumns(); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate lockout column of parent auth").Error())
+	}
 	return repo
 }
 
+// migrateLockoutColumns method add columns about login lockout to parent_auth table created before lockout
+func (ar *parentAuthRepository) migrateLockoutColumns() (err error) {
+	var exist bool
+	if err = ar.db.Get(&exist, `SELECT COUNT(*) > 0 FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'parent_auth' AND COLUMN_NAME = 'failed_login_count'`); err != nil {
+		return errors.Wrap(err, "failed to select failed_login_count column")
+	}
+	if exist {
+		return nil
+	}
+
+	_, err = ar.db.Exec(`ALTER TABLE parent_auth
+		ADD COLUMN failed_login_count  INT NOT NULL DEFAULT 0,
+		ADD COLUMN locked_until        DATETIME,
+		ADD COLUMN lockout_notified_at DATETIME`)
+	return errors.Wrap(err, "failed to add lockout columns")
+}
+
+// parentAuthRow is row of parent_auth joined with encrypted phone number linked to it (nil if not linked)
+type parentAuthRow struct {
+	domain.ParentAuth
+	PhoneNumberCipher []byte `db:"phone_number_cipher"`
+}
+
+// linkedPhoneNumber method return phone number decrypted from cipher of row, which is empty string if not linked
+func (ar *parentAuthRepository) linkedPhoneNumber(row parentAuthRow) (*string, error) {
+	if row.PhoneNumberCipher == nil {
+		return domain.String(""), nil
+	}
+	pn, err := ar.phoneNumberCipher.Decrypt(row.PhoneNumberCipher)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt phone number linked to parent auth")
+	}
+	return domain.String(pn), nil
+}
+
 // GetByUUID is implement domain.ParentAuthRepository interface
 func (ar *parentAuthRepository) GetByUUID(ctx tx.Context, uuid string) (auth struct {
 	domain.ParentAuth
 	domain.ParentPhoneCertify
 }, err error) {
 	_tx, _ := ctx.Tx().(*sqlx.Tx)
-	_sql, args, _ := squirrel.Select("parent_auth.*, IF(phone_number IS NULL, '', phone_number) AS phone_number").
+	_sql, args, _ := squirrel.Select("parent_auth.*, parent_phone_certify.phone_number_cipher").
 		From("parent_auth").
 		LeftJoin("parent_phone_certify ON parent_auth.uuid = parent_phone_certify.parent_uuid").
 		Where("parent_auth.uuid = ?", uuid).ToSql()
 
-	switch err = _tx.Get(&auth, _sql, args...); err {
+	stmt, err := preparedStmts.txStmt(ar.db, _tx, _sql)
+	if err != nil {
+		return
+	}
+	var row parentAuthRow
+	switch err = stmt.Get(&row, args...); err {
 	case nil:
-		break
+		auth.ParentAuth = row.ParentAuth
+		auth.PhoneNumber, err = ar.linkedPhoneNumber(row)
 	case sql.ErrNoRows:
 		err = domain.ErrRowNotExist{RepoErr: errors.Wrap(err, "failed to select parent auth")}
 	default:
@@ -85,14 +147,20 @@ func (ar *parentAuthRepository) GetByID(ctx tx.Context, id string) (auth struct
 	domain.ParentPhoneCertify
 }, err error) {
 	_tx, _ := ctx.Tx().(*sqlx.Tx)
-	_sql, args, _ := squirrel.Select("parent_auth.*, IF(phone_number IS NULL, '', phone_number) AS phone_number").
+	_sql, args, _ := squirrel.Select("parent_auth.*, parent_phone_certify.phone_number_cipher").
 		From("parent_auth").
 		LeftJoin("parent_phone_certify ON parent_auth.uuid = parent_phone_certify.parent_uuid").
 		Where("parent_auth.id = ?", id).ToSql()
 
-	switch err = _tx.Get(&auth, _sql, args...); err {
+	stmt, err := preparedStmts.txStmt(ar.db, _tx, _sql)
+	if err != nil {
+		return
+	}
+	var row parentAuthRow
+	switch err = stmt.Get(&row, args...); err {
 	case nil:
-		break
+		auth.ParentAuth = row.ParentAuth
+		auth.PhoneNumber, err = ar.linkedPhoneNumber(row)
 	case sql.ErrNoRows:
 		err = domain.ErrRowNotExist{RepoErr: errors.Wrap(err, "failed to select parent auth")}
 	default:
@@ -101,6 +169,35 @@ func (ar *parentAuthRepository) GetByID(ctx tx.Context, id string) (auth struct
 	return
 }
 
+// ListAfter is implement domain.ParentAuthRepository interface
+// return at most limit + 1 parent auth at cursor in order of uuid, so that caller know if there is more page
+func (ar *parentAuthRepository) ListAfter(ctx tx.Context, cursor domain.Cursor, limit int) (pas []domain.ParentAuth, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := selectPage(squirrel.Select("*").From("parent_auth"), "uuid", cursor, limit).ToSql()
+
+	if err = _tx.Select(&pas, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select parent auth list return unexpected error")
+		return
+	}
+	if cursor.IsPrev() {
+		for i, j := 0, len(pas)-1; i < j; i, j = i+1, j-1 {
+			pas[i], pas[j] = pas[j], pas[i]
+		}
+	}
+	return
+}
+
+// ExistsByID is implement domain.ParentAuthRepository interface
+func (ar *parentAuthRepository) ExistsByID(ctx tx.Context, id string) (exist bool, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("COUNT(*) > 0").From("parent_auth").Where("id = ?", id).ToSql()
+
+	if err = _tx.Get(&exist, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select parent auth existence return unexpected error")
+	}
+	return
+}
+
 // Store is implement domain.ParentAuthRepository interface
 func (ar *parentAuthRepository) Store(ctx tx.Context, pa *domain.ParentAuth) (err error) {
 	if domain.StringValue(pa.UUID) == "" {
@@ -122,7 +219,11 @@ func (ar *parentAuthRepository) Store(ctx tx.Context, pa *domain.ParentAuth) (er
 		Columns("uuid", "id", "pw", "name", "profile_uri").
 		Values(pa.UUID, pa.ID, pa.PW, pa.Name, pa.ProfileUri).ToSql()
 
-	switch _, err = _tx.Exec(_sql, args...); tErr := err.(type) {
+	stmt, err := preparedStmts.txStmt(ar.db, _tx, _sql)
+	if err != nil {
+		return
+	}
+	switch _, err = stmt.Exec(args...); tErr := err.(type) {
 	case nil:
 		break
 	case *mysql.MySQLError:
@@ -130,7 +231,7 @@ func (ar *parentAuthRepository) Store(ctx tx.Context, pa *domain.ParentAuth) (er
 		case mysqlerr.ER_DUP_ENTRY:
 			err = errors.Wrap(err, "failed to insert parent auth")
 			_, key := ar.sqlMsgParser.EntryDuplicate(tErr.Message)
-			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: key}
+			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: duplicateKey("parent_auth", key)}
 		default:
 			err = errors.Wrap(err, "insert parent auth return unexpected code return")
 		}
@@ -195,6 +296,39 @@ func (ar *parentAuthRepository) Update(ctx tx.Context, pa *domain.ParentAuth) (e
 		}
 		b = b.Set("profile_uri", pa.ProfileUri)
 	}
+	if pa.Role != nil {
+		b = b.Set("role", pa.Role)
+	}
+	if pa.Suspended != nil {
+		b = b.Set("suspended", pa.Suspended)
+	}
+	if pa.SuspendReason != nil {
+		if *pa.SuspendReason == "" {
+			pa.SuspendReason = nil
+		}
+		b = b.Set("suspend_reason", pa.SuspendReason)
+	}
+	if pa.SuspendedUntil != nil {
+		if pa.SuspendedUntil.IsZero() {
+			pa.SuspendedUntil = nil
+		}
+		b = b.Set("suspended_until", pa.SuspendedUntil)
+	}
+	if pa.IDChangedAt != nil {
+		b = b.Set("id_changed_at", pa.IDChangedAt)
+	}
+	if pa.PWChangedAt != nil {
+		b = b.Set("pw_changed_at", pa.PWChangedAt)
+	}
+	if pa.FailedLoginCount != nil {
+		b = b.Set("failed_login_count", pa.FailedLoginCount)
+	}
+	if pa.LockedUntil != nil {
+		b = b.Set("locked_until", pa.LockedUntil)
+	}
+	if pa.LockoutNotifiedAt != nil {
+		b = b.Set("lockout_notified_at", pa.LockoutNotifiedAt)
+	}
 
 	_tx, _ := ctx.Tx().(*sqlx.Tx)
 	_sql, args, err := b.ToSql()
@@ -203,8 +337,20 @@ func (ar *parentAuthRepository) Update(ctx tx.Context, pa *domain.ParentAuth) (e
 		return
 	}
 
-	if _, err = _tx.Exec(_sql, args...); err != nil {
-		err = errors.Wrap(err, "failed to update parent auth")
+	switch _, err = _tx.Exec(_sql, args...); tErr := err.(type) {
+	case nil:
+		break
+	case *mysql.MySQLError:
+		switch tErr.Number {
+		case mysqlerr.ER_DUP_ENTRY:
+			err = errors.Wrap(err, "failed to update parent auth")
+			_, key := ar.sqlMsgParser.EntryDuplicate(tErr.Message)
+			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: duplicateKey("parent_auth", key)}
+		default:
+			err = errors.Wrap(err, "update parent auth return unexpected code return")
+		}
+	default:
+		err = errors.Wrap(err, "update parent auth return unexpected error type")
 	}
 	return
 }
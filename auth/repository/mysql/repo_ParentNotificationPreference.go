@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"log"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// parentNotificationPreferenceRepository is implementation of domain.ParentNotificationPreferenceRepository using mysql
+type parentNotificationPreferenceRepository struct {
+	myCfg parentNotificationPreferenceRepositoryConfig
+
+	db           *sqlx.DB
+	migrator     migrator
+	sqlMsgParser sqlMsgParser
+	validator    validator
+}
+
+// ParentNotificationPreferenceRepository return implementation of domain.ParentNotificationPreferenceRepository using mysql
+func ParentNotificationPreferenceRepository(
+	cfg parentNotificationPreferenceRepositoryConfig,
+	db *sqlx.DB,
+	sp sqlMsgParser,
+	v validator,
+) domain.ParentNotificationPreferenceRepository {
+	repo := &parentNotificationPreferenceRepository{
+		myCfg:        cfg,
+		db:           db,
+		sqlMsgParser: sp,
+		validator:    v,
+	}
+
+	if err := repo.migrator.MigrateModel(repo.db, domain.ParentNotificationPreference{}); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate parent notification preference").Error())
+	}
+	return repo
+}
+
+// parentNotificationPreferenceRepositoryConfig is interface get config value for parent notification preference repository
+type parentNotificationPreferenceRepositoryConfig interface{}
+
+// GetByParentUUID is implement domain.ParentNotificationPreferenceRepository interface
+func (nr *parentNotificationPreferenceRepository) GetByParentUUID(ctx tx.Context, parentUUID string) (np domain.ParentNotificationPreference, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("*").From("parent_notification_preference").Where("parent_uuid = ?", parentUUID).ToSql()
+
+	switch err = _tx.Get(&np, _sql, args...); err {
+	case nil:
+		break
+	case sql.ErrNoRows:
+		err = domain.ErrRowNotExist{RepoErr: errors.Wrap(err, "failed to select parent notification preference")}
+	default:
+		err = errors.Wrap(err, "select parent notification preference return unexpected error")
+	}
+	return
+}
+
+// Store is implement domain.ParentNotificationPreferenceRepository interface
+func (nr *parentNotificationPreferenceRepository) Store(ctx tx.Context, np *domain.ParentNotificationPreference) (err error) {
+	if err = nr.validator.ValidateStruct(np); err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.Wrap(err, "failed to validate domain.ParentNotificationPreference")}
+		return
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Insert("parent_notification_preference").
+		Columns("parent_uuid", "marketing").
+		Values(np.ParentUUID, domain.BoolValue(np.Marketing)).ToSql()
+
+	switch _, err = _tx.Exec(_sql, args...); tErr := err.(type) {
+	case nil:
+		break
+	case *mysql.MySQLError:
+		switch tErr.Number {
+		case mysqlerr.ER_DUP_ENTRY:
+			err = errors.Wrap(err, "failed to insert parent notification preference")
+			_, key := nr.sqlMsgParser.EntryDuplicate(tErr.Message)
+			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: duplicateKey("parent_notification_preference", key)}
+		case mysqlerr.ER_NO_REFERENCED_ROW_2:
+			err = errors.Wrap(err, "failed to insert parent notification preference")
+			fk := nr.sqlMsgParser.NoReferencedRow(tErr.Message)
+			err = domain.ErrNoReferencedRow{RepoErr: err, ForeignKey: fk}
+		default:
+			err = errors.Wrap(err, "insert parent notification preference return unexpected code return")
+		}
+	default:
+		err = errors.Wrap(err, "insert parent notification preference return unexpected error type")
+	}
+	return
+}
+
+// Update is implement domain.ParentNotificationPreferenceRepository interface
+// where -> PK, set -> field with value set (so, cannot set to NULL in this method)
+func (nr *parentNotificationPreferenceRepository) Update(ctx tx.Context, np *domain.ParentNotificationPreference) (err error) {
+	if domain.StringValue(np.ParentUUID) == "" {
+		err = errors.New("ParentUUID(PK) value in model must be set")
+		return
+	}
+
+	b := squirrel.Update("parent_notification_preference").Where("parent_uuid = ?", np.ParentUUID)
+	if np.Marketing != nil {
+		b = b.Set("marketing", np.Marketing)
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, err := b.ToSql()
+	if err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.New("update statements must have at least one")}
+		return
+	}
+
+	if _, err = _tx.Exec(_sql, args...); err != nil {
+		err = errors.Wrap(err, "update parent notification preference return unexpected error")
+	}
+	return
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"github.com/Masterminds/squirrel"
 	"github.com/VividCortex/mysqlerr"
@@ -21,6 +22,9 @@ type parentPhoneCertifyRepository struct {
 	migrator     migrator
 	sqlMsgParser sqlMsgParser
 	validator    validator
+
+	// phoneNumberCipher is used for storing phone number as hash & encrypted, instead of plaintext
+	phoneNumberCipher phoneNumberCipher
 }
 
 // ParentPhoneCertifyRepository return implementation of domain.ParentPhoneCertifyRepository using mysql
@@ -29,31 +33,197 @@ func ParentPhoneCertifyRepository(
 	db *sqlx.DB,
 	sp sqlMsgParser,
 	v validator,
+	pc phoneNumberCipher,
 ) domain.ParentPhoneCertifyRepository {
 	repo := &parentPhoneCertifyRepository{
-		myCfg:        cfg,
-		db:           db,
-		sqlMsgParser: sp,
-		validator:    v,
+		myCfg:             cfg,
+		db:                db,
+		sqlMsgParser:      sp,
+		validator:         v,
+		phoneNumberCipher: pc,
 	}
 
 	if err := repo.migrator.MigrateModel(repo.db, domain.ParentPhoneCertify{}); err != nil {
 		log.Fatal(errors.Wrap(err, "failed to migrate parent phone certify").Error())
 	}
+	if err := repo.migrateCertifyCodeType(); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate type of certify code").Error())
+	}
+	if err := repo.migrateChannelColumns(); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate channel column of parent phone certify").Error())
+	}
+	if err := repo.migratePhoneNumberEncryption(); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate plaintext phone number of parent phone certify").Error())
+	}
 	return repo
 }
 
+// migrateCertifyCodeType method change type of certify_code column created as INT before to VARCHAR
+// certify code is stored as string, since it can be alphanumeric
+func (pp *parentPhoneCertifyRepository) migrateCertifyCodeType() (err error) {
+	var dataType string
+	if err = pp.db.Get(&dataType, `SELECT DATA_TYPE FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'parent_phone_certify' AND COLUMN_NAME = 'certify_code'`); err != nil {
+		return errors.Wrap(err, "failed to select type of certify_code column")
+	}
+	if dataType == "varchar" {
+		return nil
+	}
+
+	_, err = pp.db.Exec(`ALTER TABLE parent_phone_certify MODIFY certify_code VARCHAR(10) NOT NULL`)
+	return errors.Wrap(err, "failed to alter type of certify_code column")
+}
+
+// migrateChannelColumns method add columns about certify channel to parent_phone_certify table created before them
+func (pp *parentPhoneCertifyRepository) migrateChannelColumns() (err error) {
+	var exist bool
+	if err = pp.db.Get(&exist, `SELECT COUNT(*) > 0 FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'parent_phone_certify' AND COLUMN_NAME = 'sent_channel'`); err != nil {
+		return errors.Wrap(err, "failed to select sent_channel column")
+	}
+	if exist {
+		return nil
+	}
+
+	_, err = pp.db.Exec(`ALTER TABLE parent_phone_certify
+		ADD COLUMN sent_channel      VARCHAR(10),
+		ADD COLUMN preferred_channel VARCHAR(10)`)
+	return errors.Wrap(err, "failed to add channel columns")
+}
+
+// phoneNumberEncryptionLock is name of MySQL named lock held while migrating plaintext phone number
+const phoneNumberEncryptionLock = "parent_phone_certify.phone_number_encryption"
+
+// migratePhoneNumberEncryption method replace plaintext phone_number column with hash & encrypted column of it
+// hash can't be generated in SQL without key, so plaintext is converted row by row here instead of versioned migration
+// every instance run it at start, so it is done under named lock by one instance (others see it done after lock)
+func (pp *parentPhoneCertifyRepository) migratePhoneNumberEncryption() (err error) {
+	if plaintext, err := pp.columnExists("phone_number"); err != nil || !plaintext {
+		return err
+	}
+
+	// named lock is held by connection, so lock, migration & release must be done in the same connection
+	ctx := context.Background()
+	conn, err := pp.db.Connx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection")
+	}
+	defer func() { _ = conn.Close() }()
+
+	var locked sql.NullInt64
+	if err = conn.GetContext(ctx, &locked, `SELECT GET_LOCK(?, 60)`, phoneNumberEncryptionLock); err != nil {
+		return errors.Wrap(err, "failed to get lock for migrating phone number")
+	}
+	if locked.Int64 != 1 {
+		return errors.New("timeout to get lock for migrating phone number, which is held by other instance")
+	}
+	defer func() {
+		if _, rErr := conn.ExecContext(ctx, `SELECT RELEASE_LOCK(?)`, phoneNumberEncryptionLock); rErr != nil {
+			log.Printf("failed to release lock for migrating phone number, err: %v", rErr)
+		}
+	}()
+
+	// migration may be done by other instance while waiting lock
+	if plaintext, err := pp.columnExists("phone_number"); err != nil || !plaintext {
+		return err
+	}
+
+	// DDL is committed implicitly in MySQL, so only backfill of rows is done in transaction
+	hashed, err := pp.columnExists("phone_number_hash")
+	if err != nil {
+		return err
+	}
+	if !hashed {
+		if _, err = conn.ExecContext(ctx, `ALTER TABLE parent_phone_certify
+			ADD COLUMN phone_number_hash   CHAR(64),
+			ADD COLUMN phone_number_cipher VARBINARY(64)`); err != nil {
+			return errors.Wrap(err, "failed to add phone number hash & cipher columns")
+		}
+	}
+
+	_tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer func() { _ = _tx.Rollback() }()
+
+	var pns []string
+	if err = _tx.Select(&pns, `SELECT phone_number FROM parent_phone_certify WHERE phone_number_hash IS NULL FOR UPDATE`); err != nil {
+		return errors.Wrap(err, "failed to select plaintext phone number")
+	}
+	for _, pn := range pns {
+		encrypted, err := pp.phoneNumberCipher.Encrypt(domain.NormalizePhoneNumber(pn))
+		if err != nil {
+			return errors.Wrap(err, "failed to encrypt phone number")
+		}
+		if _, err = _tx.Exec(`UPDATE parent_phone_certify SET phone_number_hash = ?, phone_number_cipher = ?
+			WHERE phone_number = ?`, pp.hashPhoneNumber(pn), encrypted, pn); err != nil {
+			return errors.Wrap(err, "failed to update hash & cipher of phone number")
+		}
+	}
+	if err = _tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit hash & cipher of phone number")
+	}
+	log.Printf("%d plaintext phone number of parent phone certify is hashed & encrypted", len(pns))
+
+	_, err = conn.ExecContext(ctx, `ALTER TABLE parent_phone_certify
+		DROP PRIMARY KEY,
+		DROP COLUMN phone_number,
+		MODIFY phone_number_hash   CHAR(64)      NOT NULL,
+		MODIFY phone_number_cipher VARBINARY(64) NOT NULL,
+		ADD PRIMARY KEY (phone_number_hash)`)
+	return errors.Wrap(err, "failed to drop plaintext phone_number column")
+}
+
+// columnExists method return if column of parent_phone_certify table exists
+func (pp *parentPhoneCertifyRepository) columnExists(column string) (exist bool, err error) {
+	err = pp.db.Get(&exist, `SELECT COUNT(*) > 0 FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'parent_phone_certify' AND COLUMN_NAME = ?`, column)
+	return exist, errors.Wrapf(err, "failed to select %s column", column)
+}
+
+// parentPhoneCertifyColumns is columns of parent_phone_certify selected into parentPhoneCertifyRow
+const parentPhoneCertifyColumns = "parent_uuid, certify_code, certified, sent_channel, preferred_channel, phone_number_cipher"
+
+// parentPhoneCertifyRow is row of parent_phone_certify, having encrypted phone number instead of plaintext of model
+type parentPhoneCertifyRow struct {
+	domain.ParentPhoneCertify
+	PhoneNumberCipher []byte `db:"phone_number_cipher"`
+}
+
+// hashPhoneNumber method return hash of normalized pn, which is primary key of parent_phone_certify
+func (pp *parentPhoneCertifyRepository) hashPhoneNumber(pn string) string {
+	return pp.phoneNumberCipher.Hash(domain.NormalizePhoneNumber(pn))
+}
+
+// model method return domain.ParentPhoneCertify of row, having phone number decrypted from cipher of row
+func (pp *parentPhoneCertifyRepository) model(row parentPhoneCertifyRow) (ppc domain.ParentPhoneCertify, err error) {
+	pn, err := pp.phoneNumberCipher.Decrypt(row.PhoneNumberCipher)
+	if err != nil {
+		return ppc, errors.Wrap(err, "failed to decrypt phone number of parent phone certify")
+	}
+	ppc = row.ParentPhoneCertify
+	ppc.PhoneNumber = domain.String(pn)
+	return
+}
+
 // parentPhoneCertifyRepositoryConfig is interface get config value for parent phone certify repository
 type parentPhoneCertifyRepositoryConfig interface{}
 
 // GetByPhoneNumber is implement domain.ParentPhoneCertifyRepository interface
 func (pp *parentPhoneCertifyRepository) GetByPhoneNumber(ctx tx.Context, pn string) (ppc domain.ParentPhoneCertify, err error) {
 	_tx, _ := ctx.Tx().(*sqlx.Tx)
-	_sql, args, _ := squirrel.Select("*").From("parent_phone_certify").Where("phone_number = ?", pn).ToSql()
+	_sql, args, _ := squirrel.Select(parentPhoneCertifyColumns).From("parent_phone_certify").
+		Where("phone_number_hash = ?", pp.hashPhoneNumber(pn)).ToSql()
 
-	switch err = _tx.Get(&ppc, _sql, args...); err {
+	stmt, err := preparedStmts.txStmt(pp.db, _tx, _sql)
+	if err != nil {
+		return
+	}
+	var row parentPhoneCertifyRow
+	switch err = stmt.Get(&row, args...); err {
 	case nil:
-		break
+		ppc, err = pp.model(row)
 	case sql.ErrNoRows:
 		err = domain.ErrRowNotExist{RepoErr: errors.Wrap(err, "failed to select parent phone certify")}
 	default:
@@ -62,10 +232,39 @@ func (pp *parentPhoneCertifyRepository) GetByPhoneNumber(ctx tx.Context, pn stri
 	return
 }
 
+// GetByPhoneNumbers is implement domain.ParentPhoneCertifyRepository interface
+func (pp *parentPhoneCertifyRepository) GetByPhoneNumbers(ctx tx.Context, pns []string) (ppcs []domain.ParentPhoneCertify, err error) {
+	if len(pns) == 0 {
+		return []domain.ParentPhoneCertify{}, nil
+	}
+
+	hashes := make([]string, len(pns))
+	for i, pn := range pns {
+		hashes[i] = pp.hashPhoneNumber(pn)
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select(parentPhoneCertifyColumns).From("parent_phone_certify").
+		Where(squirrel.Eq{"phone_number_hash": hashes}).ToSql()
+
+	var rows []parentPhoneCertifyRow
+	if err = _tx.Select(&rows, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select parent phone certify list return unexpected error")
+		return
+	}
+	ppcs = make([]domain.ParentPhoneCertify, len(rows))
+	for i, row := range rows {
+		if ppcs[i], err = pp.model(row); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
 // Store is implement domain.ParentPhoneCertifyRepository interface
 func (pp *parentPhoneCertifyRepository) Store(ctx tx.Context, ppc *domain.ParentPhoneCertify) (err error) {
-	if domain.Int64Value(ppc.CertifyCode) == 0 {
-		ppc.CertifyCode = domain.Int64(ppc.GenerateCertifyCode())
+	if domain.StringValue(ppc.CertifyCode) == "" {
+		ppc.CertifyCode = domain.String(ppc.GenerateCertifyCode(domain.CertifyCodeNumericCharset, 6))
 	}
 
 	if err = pp.validator.ValidateStruct(ppc); err != nil {
@@ -73,12 +272,22 @@ func (pp *parentPhoneCertifyRepository) Store(ctx tx.Context, ppc *domain.Parent
 		return
 	}
 
+	encrypted, err := pp.phoneNumberCipher.Encrypt(domain.NormalizePhoneNumber(domain.StringValue(ppc.PhoneNumber)))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encrypt phone number")
+		return
+	}
+
 	_tx, _ := ctx.Tx().(*sqlx.Tx)
 	_sql, args, _ := squirrel.Insert("parent_phone_certify").
-		Columns("parent_uuid", "phone_number", "certify_code").
-		Values(ppc.ParentUUID, ppc.PhoneNumber, ppc.CertifyCode).ToSql()
+		Columns("parent_uuid", "phone_number_hash", "phone_number_cipher", "certify_code").
+		Values(ppc.ParentUUID, pp.hashPhoneNumber(domain.StringValue(ppc.PhoneNumber)), encrypted, ppc.CertifyCode).ToSql()
 
-	switch _, err = _tx.Exec(_sql, args...); tErr := err.(type) {
+	stmt, err := preparedStmts.txStmt(pp.db, _tx, _sql)
+	if err != nil {
+		return
+	}
+	switch _, err = stmt.Exec(args...); tErr := err.(type) {
 	case nil:
 		break
 	case *mysql.MySQLError:
@@ -86,7 +295,7 @@ func (pp *parentPhoneCertifyRepository) Store(ctx tx.Context, ppc *domain.Parent
 		case mysqlerr.ER_DUP_ENTRY:
 			err = errors.Wrap(err, "failed to insert parent phone certify")
 			_, key := pp.sqlMsgParser.EntryDuplicate(tErr.Message)
-			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: key}
+			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: duplicateKey("parent_phone_certify", key)}
 		case mysqlerr.ER_NO_REFERENCED_ROW_2:
 			err = errors.Wrap(err, "failed to insert parent phone certify")
 			fk := pp.sqlMsgParser.NoReferencedRow(tErr.Message)
@@ -113,7 +322,7 @@ func (pp *parentPhoneCertifyRepository) Update(ctx tx.Context, ppc *domain.Paren
 		return
 	}
 
-	b := squirrel.Update("parent_phone_certify").Where("phone_number = ?", ppc.PhoneNumber)
+	b := squirrel.Update("parent_phone_certify").Where("phone_number_hash = ?", pp.hashPhoneNumber(domain.StringValue(ppc.PhoneNumber)))
 	if ppc.ParentUUID != nil {
 		b = b.Set("parent_uuid", ppc.ParentUUID)
 	}
@@ -123,6 +332,12 @@ func (pp *parentPhoneCertifyRepository) Update(ctx tx.Context, ppc *domain.Paren
 	if ppc.Certified != nil {
 		b = b.Set("certified", ppc.Certified)
 	}
+	if ppc.SentChannel != nil {
+		b = b.Set("sent_channel", ppc.SentChannel)
+	}
+	if ppc.PreferredChannel != nil {
+		b = b.Set("preferred_channel", ppc.PreferredChannel)
+	}
 
 	_tx, _ := ctx.Tx().(*sqlx.Tx)
 	_sql, args, err := b.ToSql()
@@ -148,3 +363,19 @@ func (pp *parentPhoneCertifyRepository) Update(ctx tx.Context, ppc *domain.Paren
 	}
 	return
 }
+
+// Delete is implement domain.ParentPhoneCertifyRepository interface
+func (pp *parentPhoneCertifyRepository) Delete(ctx tx.Context, pn string) (err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Delete("parent_phone_certify").Where("phone_number_hash = ?", pp.hashPhoneNumber(pn)).ToSql()
+
+	result, err := _tx.Exec(_sql, args...)
+	if err != nil {
+		err = errors.Wrap(err, "delete parent phone certify return unexpected error")
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		err = domain.ErrRowNotExist{RepoErr: errors.New("parent phone certify to delete is not exist")}
+	}
+	return
+}
@@ -0,0 +1,231 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// parentSessionRepository is implementation of domain.ParentSessionRepository using mysql
+type parentSessionRepository struct {
+	myCfg parentSessionRepositoryConfig
+
+	db           *sqlx.DB
+	migrator     migrator
+	sqlMsgParser sqlMsgParser
+	validator    validator
+}
+
+// ParentSessionRepository return implementation of domain.ParentSessionRepository using mysql
+func ParentSessionRepository(
+	cfg parentSessionRepositoryConfig,
+	db *sqlx.DB,
+	sp sqlMsgParser,
+	v validator,
+) domain.ParentSessionRepository {
+	repo := &parentSessionRepository{
+		myCfg:        cfg,
+		db:           db,
+		sqlMsgParser: sp,
+		validator:    v,
+	}
+
+	if err := repo.migrator.MigrateModel(repo.db, domain.ParentSession{}); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate parent session").Error())
+	}
+	return repo
+}
+
+// parentSessionRepositoryConfig is interface get config value for parent session repository
+type parentSessionRepositoryConfig interface{}
+
+// GetByParentUUID is implement domain.ParentSessionRepository interface
+func (ps *parentSessionRepository) GetByParentUUID(ctx tx.Context, parentUUID string) (ss []domain.ParentSession, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("*").From("parent_session").
+		Where("parent_uuid = ?", parentUUID).OrderBy("created_at").ToSql()
+
+	if err = _tx.Select(&ss, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select parent session list return unexpected error")
+	}
+	return
+}
+
+// ListAfter is implement domain.ParentSessionRepository interface
+// return at most limit + 1 session of parent at cursor in order of uuid, so that caller know if there is more page
+func (ps *parentSessionRepository) ListAfter(ctx tx.Context, parentUUID string, cursor domain.Cursor, limit int) (ss []domain.ParentSession, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	b := squirrel.Select("*").From("parent_session").Where("parent_uuid = ?", parentUUID)
+	_sql, args, _ := selectPage(b, "uuid", cursor, limit).ToSql()
+
+	if err = _tx.Select(&ss, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select parent session list return unexpected error")
+		return
+	}
+	if cursor.IsPrev() {
+		for i, j := 0, len(ss)-1; i < j; i, j = i+1, j-1 {
+			ss[i], ss[j] = ss[j], ss[i]
+		}
+	}
+	return
+}
+
+// CountUsedSince is implement domain.ParentSessionRepository interface
+// return count of session of parent used at or after since, counted in DB without loading rows
+func (ps *parentSessionRepository) CountUsedSince(ctx tx.Context, parentUUID string, since time.Time) (count int, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("COUNT(*)").From("parent_session").
+		Where("parent_uuid = ?", parentUUID).Where("last_used_at >= ?", since).ToSql()
+
+	if err = _tx.Get(&count, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select parent session count return unexpected error")
+	}
+	return
+}
+
+// GetLeastRecentlyUsedSince is implement domain.ParentSessionRepository interface
+// return at most limit session of parent used at or after since, in order of last used time (least recently used first)
+func (ps *parentSessionRepository) GetLeastRecentlyUsedSince(ctx tx.Context, parentUUID string, since time.Time, limit int) (ss []domain.ParentSession, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("*").From("parent_session").
+		Where("parent_uuid = ?", parentUUID).Where("last_used_at >= ?", since).
+		OrderBy("last_used_at").Limit(uint64(limit)).ToSql()
+
+	if err = _tx.Select(&ss, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select parent session list return unexpected error")
+	}
+	return
+}
+
+// GetByParentUUIDAndDeviceID is implement domain.ParentSessionRepository interface
+// return last used session if parent has several session in same device
+func (ps *parentSessionRepository) GetByParentUUIDAndDeviceID(ctx tx.Context, parentUUID, deviceID string) (s domain.ParentSession, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("*").From("parent_session").
+		Where("parent_uuid = ? AND device_id = ?", parentUUID, deviceID).
+		OrderBy("last_used_at DESC").Limit(1).ToSql()
+
+	switch err = _tx.Get(&s, _sql, args...); err {
+	case nil:
+		break
+	case sql.ErrNoRows:
+		err = domain.ErrRowNotExist{RepoErr: errors.Wrap(err, "failed to select parent session")}
+	default:
+		err = errors.Wrap(err, "select parent session return unexpected error")
+	}
+	return
+}
+
+// GetDevicelessByParentUUID is implement domain.ParentSessionRepository interface
+// return last used session without device id, stored with same user agent & platform
+func (ps *parentSessionRepository) GetDevicelessByParentUUID(ctx tx.Context, parentUUID, userAgent, platform string) (s domain.ParentSession, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("*").From("parent_session").
+		Where("parent_uuid = ? AND (device_id = '' OR device_id IS NULL)", parentUUID).
+		Where("user_agent = ? AND platform = ?", userAgent, platform).
+		OrderBy("last_used_at DESC").Limit(1).ToSql()
+
+	switch err = _tx.Get(&s, _sql, args...); err {
+	case nil:
+		break
+	case sql.ErrNoRows:
+		err = domain.ErrRowNotExist{RepoErr: errors.Wrap(err, "failed to select parent session")}
+	default:
+		err = errors.Wrap(err, "select parent session return unexpected error")
+	}
+	return
+}
+
+// Store is implement domain.ParentSessionRepository interface
+func (ps *parentSessionRepository) Store(ctx tx.Context, s *domain.ParentSession) (err error) {
+	if domain.StringValue(s.UUID) == "" {
+		s.UUID = domain.String(s.GenerateRandomUUID())
+	}
+
+	if err = ps.validator.ValidateStruct(s); err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.Wrap(err, "failed to validate domain.ParentSession")}
+		return
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Insert("parent_session").
+		Columns("uuid", "parent_uuid", "device_id", "user_agent", "platform", "created_at", "last_used_at").
+		Values(s.UUID, s.ParentUUID, s.DeviceID, s.UserAgent, s.Platform, s.CreatedAt, s.LastUsedAt).ToSql()
+
+	switch _, err = _tx.Exec(_sql, args...); tErr := err.(type) {
+	case nil:
+		break
+	case *mysql.MySQLError:
+		switch tErr.Number {
+		case mysqlerr.ER_DUP_ENTRY:
+			err = errors.Wrap(err, "failed to insert parent session")
+			_, key := ps.sqlMsgParser.EntryDuplicate(tErr.Message)
+			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: duplicateKey("parent_session", key)}
+		case mysqlerr.ER_NO_REFERENCED_ROW_2:
+			err = errors.Wrap(err, "failed to insert parent session")
+			fk := ps.sqlMsgParser.NoReferencedRow(tErr.Message)
+			err = domain.ErrNoReferencedRow{RepoErr: err, ForeignKey: fk}
+		default:
+			err = errors.Wrap(err, "insert parent session return unexpected code return")
+		}
+	default:
+		err = errors.Wrap(err, "insert parent session return unexpected error type")
+	}
+	return
+}
+
+// Update is implement domain.ParentSessionRepository interface
+// where -> PK, set -> field with value set (so, cannot set to NULL in this method)
+func (ps *parentSessionRepository) Update(ctx tx.Context, s *domain.ParentSession) (err error) {
+	if domain.StringValue(s.UUID) == "" {
+		err = errors.New("UUID(PK) value in model must be set")
+		return
+	}
+
+	b := squirrel.Update("parent_session").Where("uuid = ?", s.UUID)
+	if s.UserAgent != nil {
+		b = b.Set("user_agent", s.UserAgent)
+	}
+	if s.Platform != nil {
+		b = b.Set("platform", s.Platform)
+	}
+	if s.LastUsedAt != nil {
+		b = b.Set("last_used_at", s.LastUsedAt)
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, err := b.ToSql()
+	if err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.New("update statements must have at least one")}
+		return
+	}
+
+	if _, err = _tx.Exec(_sql, args...); err != nil {
+		err = errors.Wrap(err, "update parent session return unexpected error")
+	}
+	return
+}
+
+// Delete is implement domain.ParentSessionRepository interface
+func (ps *parentSessionRepository) Delete(ctx tx.Context, uuid string) (err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Delete("parent_session").Where("uuid = ?", uuid).ToSql()
+
+	result, err := _tx.Exec(_sql, args...)
+	if err != nil {
+		err = errors.Wrap(err, "delete parent session return unexpected error")
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		err = domain.ErrRowNotExist{RepoErr: errors.New("parent session to delete is not exist")}
+	}
+	return
+}
@@ -0,0 +1,121 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"log"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// parentWebAuthnCredentialRepository is implementation of domain.ParentWebAuthnCredentialRepository using mysql
+type parentWebAuthnCredentialRepository struct {
+	myCfg parentWebAuthnCredentialRepositoryConfig
+
+	db           *sqlx.DB
+	migrator     migrator
+	sqlMsgParser sqlMsgParser
+	validator    validator
+}
+
+// ParentWebAuthnCredentialRepository return implementation of domain.ParentWebAuthnCredentialRepository using mysql
+func ParentWebAuthnCredentialRepository(
+	cfg parentWebAuthnCredentialRepositoryConfig,
+	db *sqlx.DB,
+	sp sqlMsgParser,
+	v validator,
+) domain.ParentWebAuthnCredentialRepository {
+	repo := &parentWebAuthnCredentialRepository{
+		myCfg:        cfg,
+		db:           db,
+		sqlMsgParser: sp,
+		validator:    v,
+	}
+
+	if err := repo.migrator.MigrateModel(repo.db, domain.ParentWebAuthnCredential{}); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate parent webauthn credential").Error())
+	}
+	return repo
+}
+
+// parentWebAuthnCredentialRepositoryConfig is interface get config value for parent webauthn credential repository
+type parentWebAuthnCredentialRepositoryConfig interface{}
+
+// GetByParentUUID is implement domain.ParentWebAuthnCredentialRepository interface
+func (pr *parentWebAuthnCredentialRepository) GetByParentUUID(ctx tx.Context, parentUUID string) (creds []domain.ParentWebAuthnCredential, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("*").From("parent_webauthn_credential").
+		Where("parent_uuid = ?", parentUUID).OrderBy("created_at").ToSql()
+
+	if err = _tx.Select(&creds, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select parent webauthn credential list return unexpected error")
+	}
+	return
+}
+
+// Store is implement domain.ParentWebAuthnCredentialRepository interface
+func (pr *parentWebAuthnCredentialRepository) Store(ctx tx.Context, cred *domain.ParentWebAuthnCredential) (err error) {
+	if err = pr.validator.ValidateStruct(cred); err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.Wrap(err, "failed to validate domain.ParentWebAuthnCredential")}
+		return
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Insert("parent_webauthn_credential").
+		Columns("id", "parent_uuid", "public_key", "sign_count", "created_at").
+		Values(cred.ID, cred.ParentUUID, cred.PublicKey, domain.Int64Value(cred.SignCount), cred.CreatedAt).ToSql()
+
+	switch _, err = _tx.Exec(_sql, args...); tErr := err.(type) {
+	case nil:
+		break
+	case *mysql.MySQLError:
+		switch tErr.Number {
+		case mysqlerr.ER_DUP_ENTRY:
+			err = errors.Wrap(err, "failed to insert parent webauthn credential")
+			_, key := pr.sqlMsgParser.EntryDuplicate(tErr.Message)
+			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: duplicateKey("parent_webauthn_credential", key)}
+		case mysqlerr.ER_NO_REFERENCED_ROW_2:
+			err = errors.Wrap(err, "failed to insert parent webauthn credential")
+			fk := pr.sqlMsgParser.NoReferencedRow(tErr.Message)
+			err = domain.ErrNoReferencedRow{RepoErr: err, ForeignKey: fk}
+		default:
+			err = errors.Wrap(err, "insert parent webauthn credential return unexpected code return")
+		}
+	default:
+		err = errors.Wrap(err, "insert parent webauthn credential return unexpected error type")
+	}
+	return
+}
+
+// Update is implement domain.ParentWebAuthnCredentialRepository interface
+// where -> PK, set -> field with value set (so, cannot set to NULL in this method)
+func (pr *parentWebAuthnCredentialRepository) Update(ctx tx.Context, cred *domain.ParentWebAuthnCredential) (err error) {
+	if domain.StringValue(cred.ID) == "" {
+		err = errors.New("ID(PK) value in model must be set")
+		return
+	}
+
+	b := squirrel.Update("parent_webauthn_credential").Where("id = ?", cred.ID)
+	if cred.SignCount != nil {
+		b = b.Set("sign_count", cred.SignCount)
+	}
+	if cred.LastUsedAt != nil {
+		b = b.Set("last_used_at", cred.LastUsedAt)
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, err := b.ToSql()
+	if err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.New("update statements must have at least one")}
+		return
+	}
+
+	if _, err = _tx.Exec(_sql, args...); err != nil {
+		err = errors.Wrap(err, "update parent webauthn credential return unexpected error")
+	}
+	return
+}
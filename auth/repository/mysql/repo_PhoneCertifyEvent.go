@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"log"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// phoneCertifyEventRepository is implementation of domain.PhoneCertifyEventRepository using mysql
+type phoneCertifyEventRepository struct {
+	myCfg phoneCertifyEventRepositoryConfig
+
+	db        *sqlx.DB
+	migrator  migrator
+	validator validator
+}
+
+// PhoneCertifyEventRepository return implementation of domain.PhoneCertifyEventRepository using mysql
+func PhoneCertifyEventRepository(
+	cfg phoneCertifyEventRepositoryConfig,
+	db *sqlx.DB,
+	v validator,
+) domain.PhoneCertifyEventRepository {
+	repo := &phoneCertifyEventRepository{
+		myCfg:     cfg,
+		db:        db,
+		validator: v,
+	}
+
+	if err := repo.migrator.MigrateModel(repo.db, domain.PhoneCertifyEvent{}); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate phone certify event").Error())
+	}
+	return repo
+}
+
+// phoneCertifyEventRepositoryConfig is interface get config value for phone certify event repository
+type phoneCertifyEventRepositoryConfig interface{}
+
+// ListAfter is implement domain.PhoneCertifyEventRepository interface
+// return at most limit + 1 event of phone at cursor in order of id (occurrence), so that caller know if there is more page
+func (pr *phoneCertifyEventRepository) ListAfter(ctx tx.Context, pn string, cursor domain.Cursor, limit int) (es []domain.PhoneCertifyEvent, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	b := squirrel.Select("*").From("phone_certify_event").Where("phone_number = ?", pn)
+	_sql, args, _ := selectPage(b, "id", cursor, limit).ToSql()
+
+	if err = _tx.Select(&es, _sql, args...); err != nil {
+		err = errors.Wrap(err, "select phone certify event list return unexpected error")
+		return
+	}
+	if cursor.IsPrev() {
+		for i, j := 0, len(es)-1; i < j; i, j = i+1, j-1 {
+			es[i], es[j] = es[j], es[i]
+		}
+	}
+	return
+}
+
+// Store is implement domain.PhoneCertifyEventRepository interface
+func (pr *phoneCertifyEventRepository) Store(ctx tx.Context, pce *domain.PhoneCertifyEvent) (err error) {
+	if err = pr.validator.ValidateStruct(pce); err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.Wrap(err, "failed to validate domain.PhoneCertifyEvent")}
+		return
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Insert("phone_certify_event").
+		Columns("phone_number", "type", "channel", "occurred_at").
+		Values(pce.PhoneNumber, pce.Type, pce.Channel, pce.OccurredAt).ToSql()
+
+	result, err := _tx.Exec(_sql, args...)
+	if err != nil {
+		err = errors.Wrap(err, "insert phone certify event return unexpected error")
+		return
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		pce.ID = domain.Int64(id)
+	}
+	return
+}
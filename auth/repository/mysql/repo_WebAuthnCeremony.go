@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"log"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// webAuthnCeremonyRepository is implementation of domain.WebAuthnCeremonyRepository using mysql
+type webAuthnCeremonyRepository struct {
+	myCfg webAuthnCeremonyRepositoryConfig
+
+	db           *sqlx.DB
+	migrator     migrator
+	sqlMsgParser sqlMsgParser
+	validator    validator
+}
+
+// WebAuthnCeremonyRepository return implementation of domain.WebAuthnCeremonyRepository using mysql
+func WebAuthnCeremonyRepository(
+	cfg webAuthnCeremonyRepositoryConfig,
+	db *sqlx.DB,
+	sp sqlMsgParser,
+	v validator,
+) domain.WebAuthnCeremonyRepository {
+	repo := &webAuthnCeremonyRepository{
+		myCfg:        cfg,
+		db:           db,
+		sqlMsgParser: sp,
+		validator:    v,
+	}
+
+	if err := repo.migrator.MigrateModel(repo.db, domain.WebAuthnCeremony{}); err != nil {
+		log.Fatal(errors.Wrap(err, "failed to migrate webauthn ceremony").Error())
+	}
+	return repo
+}
+
+// webAuthnCeremonyRepositoryConfig is interface get config value for webauthn ceremony repository
+type webAuthnCeremonyRepositoryConfig interface{}
+
+// GetByID is implement domain.WebAuthnCeremonyRepository interface
+func (wr *webAuthnCeremonyRepository) GetByID(ctx tx.Context, id string) (wc domain.WebAuthnCeremony, err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Select("*").From("webauthn_ceremony").Where("id = ?", id).ToSql()
+
+	switch err = _tx.Get(&wc, _sql, args...); err {
+	case nil:
+		break
+	case sql.ErrNoRows:
+		err = domain.ErrRowNotExist{RepoErr: errors.Wrap(err, "failed to select webauthn ceremony")}
+	default:
+		err = errors.Wrap(err, "select webauthn ceremony return unexpected error")
+	}
+	return
+}
+
+// Store is implement domain.WebAuthnCeremonyRepository interface
+func (wr *webAuthnCeremonyRepository) Store(ctx tx.Context, wc *domain.WebAuthnCeremony) (err error) {
+	if domain.StringValue(wc.ID) == "" {
+		wc.ID = domain.String(wc.GenerateRandomID())
+	}
+
+	if err = wr.validator.ValidateStruct(wc); err != nil {
+		err = domain.ErrInvalidModel{RepoErr: errors.Wrap(err, "failed to validate domain.WebAuthnCeremony")}
+		return
+	}
+
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Insert("webauthn_ceremony").
+		Columns("id", "parent_uuid", "type", "session", "expires_at").
+		Values(wc.ID, wc.ParentUUID, wc.Type, wc.Session, wc.ExpiresAt).ToSql()
+
+	switch _, err = _tx.Exec(_sql, args...); tErr := err.(type) {
+	case nil:
+		break
+	case *mysql.MySQLError:
+		switch tErr.Number {
+		case mysqlerr.ER_NO_REFERENCED_ROW_2:
+			err = errors.Wrap(err, "failed to insert webauthn ceremony")
+			fk := wr.sqlMsgParser.NoReferencedRow(tErr.Message)
+			err = domain.ErrNoReferencedRow{RepoErr: err, ForeignKey: fk}
+		default:
+			err = errors.Wrap(err, "insert webauthn ceremony return unexpected code return")
+		}
+	default:
+		err = errors.Wrap(err, "insert webauthn ceremony return unexpected error type")
+	}
+	return
+}
+
+// Delete is implement domain.WebAuthnCeremonyRepository interface
+func (wr *webAuthnCeremonyRepository) Delete(ctx tx.Context, id string) (err error) {
+	_tx, _ := ctx.Tx().(*sqlx.Tx)
+	_sql, args, _ := squirrel.Delete("webauthn_ceremony").Where("id = ?", id).ToSql()
+
+	if _, err = _tx.Exec(_sql, args...); err != nil {
+		err = errors.Wrap(err, "delete webauthn ceremony return unexpected error")
+	}
+	return
+}
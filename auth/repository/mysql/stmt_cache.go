@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"log"
+	"sync"
+)
+
+// preparedStmts is cache of statement prepared for query on hot path (ex, login, certify), shared by repositories
+var preparedStmts = &stmtCache{stmts: map[string]*sqlx.Stmt{}}
+
+// stmtCache is struct caching statement prepared in db by query, so that same query isn't parsed by db every time
+type stmtCache struct {
+	mutex sync.Mutex
+	stmts map[string]*sqlx.Stmt
+
+	// preparing is set of query being prepared in background, so that query is prepared only once at the same time
+	preparing map[string]bool
+
+	// closed represent if cache is closed in shutdown, after which statement prepared in background is closed at once
+	closed bool
+}
+
+// txStmt method return statement of query prepared in db, which is bound to transaction
+// statement is prepared only at first call of query, and statement bound to transaction is closed with transaction
+// if query isn't prepared yet, it is prepared in transaction for this call & in db in background, since preparing
+// in db wait for free connection while transaction hold one (ex, deadlock if every connection of pool is in transaction)
+func (sc *stmtCache) txStmt(db *sqlx.DB, _tx *sqlx.Tx, query string) (*sqlx.Stmt, error) {
+	sc.mutex.Lock()
+	stmt, ok := sc.stmts[query]
+	if !ok && !sc.preparing[query] && !sc.closed {
+		if sc.preparing == nil {
+			sc.preparing = map[string]bool{}
+		}
+		sc.preparing[query] = true
+		go sc.prepare(db, query)
+	}
+	sc.mutex.Unlock()
+
+	if ok {
+		return _tx.Stmtx(stmt), nil
+	}
+	stmt, err := _tx.Preparex(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to prepare statement in transaction")
+	}
+	return stmt, nil
+}
+
+// prepare method prepare statement of query in db & cache it, called in background by txStmt
+// error is only logged, and query is prepared again in next call of txStmt
+func (sc *stmtCache) prepare(db *sqlx.DB, query string) {
+	stmt, err := db.Preparex(query)
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	delete(sc.preparing, query)
+	switch {
+	case err != nil:
+		log.Printf("failed to prepare statement of query %s, err: %v", query, err)
+	case sc.closed:
+		_ = stmt.Close()
+	default:
+		sc.stmts[query] = stmt
+	}
+}
+
+// close method close every statement prepared & clear cache, after which statement isn't cached any more
+func (sc *stmtCache) close() (err error) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.closed = true
+	for query, stmt := range sc.stmts {
+		if cErr := stmt.Close(); cErr != nil && err == nil {
+			err = errors.Wrapf(cErr, "failed to close statement of query %s", query)
+		}
+		delete(sc.stmts, query)
+	}
+	return
+}
+
+// CloseStatements close statement prepared by repositories, which must be called before db is closed in shutdown
+func CloseStatements() error {
+	return preparedStmts.close()
+}
@@ -0,0 +1,180 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDriver is database/sql driver having no real DB behind it, counting statement prepared in it
+// prepareLatency is slept in every prepare, to model round trip of preparing statement in real DB
+type fakeDriver struct {
+	prepares       int64
+	prepareLatency time.Duration
+}
+
+func (d *fakeDriver) Open(_ string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+// fakeConnector is driver.Connector of fakeDriver, used to open db without registering driver
+type fakeConnector struct{ d *fakeDriver }
+
+func (c fakeConnector) Connect(_ context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c fakeConnector) Driver() driver.Driver                          { return c.d }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(_ string) (driver.Stmt, error) {
+	atomic.AddInt64(&c.d.prepares, 1)
+	time.Sleep(c.d.prepareLatency)
+	return fakeStmt{}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                                 { return nil }
+func (fakeStmt) NumInput() int                                { return -1 }
+func (fakeStmt) Exec(_ []driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (fakeStmt) Query(_ []driver.Value) (driver.Rows, error)  { return fakeRows{}, nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string           { return []string{"uuid"} }
+func (fakeRows) Close() error                { return nil }
+func (fakeRows) Next(_ []driver.Value) error { return io.EOF }
+
+// testQuery is query of GetByID, which is on hot path of login
+const testQuery = "SELECT parent_auth.*, parent_phone_certify.phone_number_cipher FROM parent_auth " +
+	"LEFT JOIN parent_phone_certify ON parent_auth.uuid = parent_phone_certify.parent_uuid WHERE parent_auth.id = ?"
+
+// openFakeDB return db opened with d, having maxConns connection at most
+func openFakeDB(tb testing.TB, d *fakeDriver, maxConns int) *sqlx.DB {
+	tb.Helper()
+	db := sqlx.NewDb(sql.OpenDB(fakeConnector{d}), "mysql")
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns)
+	tb.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// queryInTx run query in new transaction with statement returned from stmtOf, like repository method called in usecase
+func queryInTx(tb testing.TB, db *sqlx.DB, stmtOf func(_tx *sqlx.Tx) (*sqlx.Stmt, error)) {
+	_tx, err := db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	stmt, err := stmtOf(_tx)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if _, err = stmt.Exec("parent1"); err != nil {
+		tb.Fatal(err)
+	}
+	_ = stmt.Close()
+	if err = _tx.Commit(); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+// waitCached wait until query is cached in sc, which is prepared in background at first call of txStmt
+func waitCached(tb testing.TB, sc *stmtCache, query string) {
+	tb.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		sc.mutex.Lock()
+		_, ok := sc.stmts[query]
+		sc.mutex.Unlock()
+		if ok {
+			return
+		}
+	}
+	tb.Fatalf("query is not cached in time: %s", query)
+}
+
+func TestStmtCache_TxStmt(t *testing.T) {
+	d := &fakeDriver{}
+	// only connection of pool is held by transaction, so preparing in db must not be waited in txStmt
+	db := openFakeDB(t, d, 1)
+	sc := &stmtCache{stmts: map[string]*sqlx.Stmt{}}
+	stmtOf := func(_tx *sqlx.Tx) (*sqlx.Stmt, error) { return sc.txStmt(db, _tx, testQuery) }
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		queryInTx(t, db, stmtOf)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("txStmt is blocked by preparing in db while transaction hold every connection")
+	}
+
+	waitCached(t, sc, testQuery)
+	prepares := atomic.LoadInt64(&d.prepares)
+	for i := 0; i < 10; i++ {
+		queryInTx(t, db, stmtOf)
+	}
+	assert.Equal(t, prepares, atomic.LoadInt64(&d.prepares), "cached statement must not be prepared again")
+
+	assert.NoError(t, sc.close())
+	assert.Empty(t, sc.stmts)
+	queryInTx(t, db, stmtOf)
+	assert.Empty(t, sc.stmts, "statement must not be cached after close")
+}
+
+func BenchmarkStmtCache_TxStmt(b *testing.B) {
+	benchmarks := []struct {
+		name           string
+		maxConns       int
+		prepareLatency time.Duration
+	}{
+		{"no latency 1 conn", 1, 0},
+		{"no latency 8 conns", 8, 0},
+		{"100us prepare latency 8 conns", 8, 100 * time.Microsecond},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name+" prepare per call", func(b *testing.B) {
+			d := &fakeDriver{prepareLatency: bm.prepareLatency}
+			db := openFakeDB(b, d, bm.maxConns)
+			benchmarkQueryInTx(b, d, db, func(_tx *sqlx.Tx) (*sqlx.Stmt, error) { return _tx.Preparex(testQuery) })
+		})
+
+		b.Run(bm.name+" cached", func(b *testing.B) {
+			d := &fakeDriver{prepareLatency: bm.prepareLatency}
+			db := openFakeDB(b, d, bm.maxConns)
+			sc := &stmtCache{stmts: map[string]*sqlx.Stmt{}}
+			b.Cleanup(func() { _ = sc.close() })
+			stmtOf := func(_tx *sqlx.Tx) (*sqlx.Stmt, error) { return sc.txStmt(db, _tx, testQuery) }
+
+			queryInTx(b, db, stmtOf)
+			waitCached(b, sc, testQuery)
+			benchmarkQueryInTx(b, d, db, stmtOf)
+		})
+	}
+}
+
+// benchmarkQueryInTx run queryInTx in parallel b.N times, reporting count of prepare per query
+func benchmarkQueryInTx(b *testing.B, d *fakeDriver, db *sqlx.DB, stmtOf func(_tx *sqlx.Tx) (*sqlx.Stmt, error)) {
+	prepares := atomic.LoadInt64(&d.prepares)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			queryInTx(b, db, stmtOf)
+		}
+	})
+	b.ReportMetric(float64(atomic.LoadInt64(&d.prepares)-prepares)/float64(b.N), "prepares/op")
+}
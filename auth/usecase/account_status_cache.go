@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// accountStatusKeyPrefix is prefix of kv store key caching status of parent account, followed by uuid
+const accountStatusKeyPrefix = "auth:account_status:"
+
+// accountStatus is part of parent account checked in CheckParentAccount, which is cached in kv store as JSON
+type accountStatus struct {
+	Role           string     `json:"role"`
+	Suspended      bool       `json:"suspended"`
+	SuspendReason  string     `json:"suspend_reason"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+	PWChangedAt    *time.Time `json:"pw_changed_at"`
+	PhoneCertified bool       `json:"phone_certified"`
+}
+
+// newAccountStatus return accountStatus of parent auth & phone linked to it (zero value if not linked)
+func newAccountStatus(pa domain.ParentAuth, ppc domain.ParentPhoneCertify) accountStatus {
+	return accountStatus{
+		Role:           domain.StringValue(pa.Role),
+		Suspended:      domain.BoolValue(pa.Suspended),
+		SuspendReason:  domain.StringValue(pa.SuspendReason),
+		SuspendedUntil: pa.SuspendedUntil,
+		PWChangedAt:    pa.PWChangedAt,
+		PhoneCertified: domain.BoolValue(ppc.Certified),
+	}
+}
+
+// parentAuth method return ParentAuth model having field of status, used for checking it with method of model
+func (as accountStatus) parentAuth() domain.ParentAuth {
+	return domain.ParentAuth{
+		Role:           domain.String(as.Role),
+		Suspended:      domain.Bool(as.Suspended),
+		SuspendReason:  domain.String(as.SuspendReason),
+		SuspendedUntil: as.SuspendedUntil,
+		PWChangedAt:    as.PWChangedAt,
+	}
+}
+
+// cachedAccountStatus return status of parent account cached in kv store, and if it is cached
+// status is regarded as not cached if kv store is unavailable, so that it is got from DB
+func (au *authUsecase) cachedAccountStatus(uuid string) (as accountStatus, ok bool) {
+	if au.myCfg.AccountStatusCacheTTL() <= 0 {
+		return
+	}
+
+	value, ok, err := au.kvStore.Get(accountStatusKeyPrefix + uuid)
+	if err != nil {
+		log.Printf("failed to get cached account status from kv store, err: %v", err)
+		return as, false
+	}
+	if !ok {
+		return
+	}
+	if err = json.Unmarshal([]byte(value), &as); err != nil {
+		log.Printf("failed to unmarshal cached account status of parent %s, err: %v", uuid, err)
+		return as, false
+	}
+	return as, true
+}
+
+// cacheAccountStatus cache status of parent account in kv store for AccountStatusCacheTTL
+func (au *authUsecase) cacheAccountStatus(uuid string, as accountStatus) {
+	ttl := au.myCfg.AccountStatusCacheTTL()
+	if ttl <= 0 {
+		return
+	}
+
+	value, _ := json.Marshal(as)
+	if err := au.kvStore.Set(accountStatusKeyPrefix+uuid, string(value), ttl); err != nil {
+		log.Printf("failed to cache account status in kv store, err: %v", err)
+	}
+}
+
+// invalidateAccountStatus delete cached status of parent account, which must be called after status is changed
+// (ex, suspension), so that change is applied to next request without waiting for cache to expire
+func (au *authUsecase) invalidateAccountStatus(uuid string) {
+	if au.myCfg.AccountStatusCacheTTL() <= 0 {
+		return
+	}
+
+	if err := au.kvStore.Delete(accountStatusKeyPrefix + uuid); err != nil {
+		log.Printf("failed to delete cached account status of parent %s from kv store, err: %v", uuid, err)
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// GetAuthStats implement GetAuthStats method of domain.AuthUsecase interface
+func (au *authUsecase) GetAuthStats(ctx context.Context, from, to time.Time) (stats domain.AuthStats, err error) {
+	if !from.Before(to) {
+		err = errors.New("from must be before to")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusBadRequest}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	counts, err := au.authEventRepository.CountByType(_tx, from, to)
+	if err != nil {
+		err = errors.Wrap(err, "CountByType return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// nothing is changed, so rollback rather than commit
+	_ = au.txHandler.Rollback(_tx)
+	stats = domain.AuthStats{
+		From:             domain.Timestamp(from),
+		To:               domain.Timestamp(to),
+		SignUps:          counts[domain.AuthEventSignUp],
+		CodesSent:        counts[domain.AuthEventCodeSent],
+		CertifySucceeded: counts[domain.AuthEventCertifySucceeded],
+		CertifyFailed:    counts[domain.AuthEventCertifyFailed],
+		Logins:           counts[domain.AuthEventLogin],
+	}
+	return
+}
+
+// recordAuthEvent store auth event aggregated in GetAuthStats in its own transaction
+// it is called after transaction of event was ended, so failure of record is only logged
+func (au *authUsecase) recordAuthEvent(ctx context.Context, _type string) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		log.Printf("failed to begin transaction to record auth event %s, err: %v", _type, err)
+		return
+	}
+
+	now := au.clock.Now()
+	if err = au.authEventRepository.Store(_tx, &domain.AuthEvent{Type: domain.String(_type), OccurredAt: &now}); err != nil {
+		log.Printf("failed to record auth event %s, err: %v", _type, err)
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	_ = au.txHandler.Commit(_tx)
+}
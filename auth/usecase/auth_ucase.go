@@ -3,11 +3,14 @@ package usecase
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"expvar"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pkg/errors"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/MyFirstBabyTime/Server/domain"
@@ -25,6 +28,30 @@ type authUsecase struct {
 	// parentPhoneCertifyRepository is repository interface about domain.ParentPhoneCertify model
 	parentPhoneCertifyRepository domain.ParentPhoneCertifyRepository
 
+	// parentSessionRepository is repository interface about domain.ParentSession model
+	parentSessionRepository domain.ParentSessionRepository
+
+	// auditLogRepository is repository interface about domain.AuditLog model
+	auditLogRepository domain.AuditLogRepository
+
+	// parentWebAuthnCredentialRepository is repository interface about domain.ParentWebAuthnCredential model
+	parentWebAuthnCredentialRepository domain.ParentWebAuthnCredentialRepository
+
+	// webAuthnCeremonyRepository is repository interface about domain.WebAuthnCeremony model
+	webAuthnCeremonyRepository domain.WebAuthnCeremonyRepository
+
+	// deviceVerificationRepository is repository interface about domain.DeviceVerification model
+	deviceVerificationRepository domain.DeviceVerificationRepository
+
+	// authEventRepository is repository interface about domain.AuthEvent model
+	authEventRepository domain.AuthEventRepository
+
+	// parentNotificationPreferenceRepository is repository interface about domain.ParentNotificationPreference model
+	parentNotificationPreferenceRepository domain.ParentNotificationPreferenceRepository
+
+	// phoneCertifyEventRepository is repository interface about domain.PhoneCertifyEvent model
+	phoneCertifyEventRepository domain.PhoneCertifyEventRepository
+
 	// txHandler is used for handling transaction to begin & commit or rollback
 	txHandler txHandler
 
@@ -39,6 +66,24 @@ type authUsecase struct {
 
 	// s3Agency is used as agency about aws s3 API
 	s3Agency s3Agency
+
+	// webAuthnAgency is used as agency about WebAuthn ceremony (passkey is not available if nil)
+	webAuthnAgency webAuthnAgency
+
+	// alertHook is used for alerting to operator about security signal (ex, spike of certify code mismatch)
+	alertHook alertHook
+
+	// clock is used for getting current time in every time-dependent decision
+	clock clock
+
+	// kvStore is used for keeping small & fast-changing state shared across instances (ex, count of mismatch)
+	kvStore kvStore
+
+	// pwnedChecker is used for checking if password is breached (not checked if nil)
+	pwnedChecker pwnedChecker
+
+	// phoneNumberHasher is used for keying state of phone number in kvStore & audit log with hash, instead of plaintext
+	phoneNumberHasher phoneNumberHasher
 }
 
 // AuthUsecase return implementation of domain.AuthUsecase
@@ -46,23 +91,93 @@ func AuthUsecase(
 	cfg authUsecaseConfig,
 	par domain.ParentAuthRepository,
 	ppr domain.ParentPhoneCertifyRepository,
+	psr domain.ParentSessionRepository,
+	alr domain.AuditLogRepository,
+	pcr domain.ParentWebAuthnCredentialRepository,
+	wcr domain.WebAuthnCeremonyRepository,
+	dvr domain.DeviceVerificationRepository,
+	aer domain.AuthEventRepository,
+	npr domain.ParentNotificationPreferenceRepository,
+	per domain.PhoneCertifyEventRepository,
 	th txHandler,
 	ma messageAgency,
 	hh hashHandler,
 	jh jwtHandler,
 	sa s3Agency,
+	wa webAuthnAgency,
+	ah alertHook,
+	cl clock,
+	kv kvStore,
+	pc pwnedChecker,
+	ph phoneNumberHasher,
 ) domain.AuthUsecase {
+	// fail fast, so that SMS without certify code is never sent because of wrong template
+	for _, purpose := range domain.CertifyPurposes {
+		if !strings.Contains(cfg.CertifyMessageTemplate(purpose), certifyCodePlaceholder) {
+			log.Fatalf("certify message template of %s must contain %s placeholder", purpose, certifyCodePlaceholder)
+		}
+	}
+
+	if tps := cfg.TestPhoneNumbers(); len(tps) > 0 {
+		if cfg.TestPhoneCertifyCode() == "" {
+			log.Fatal("test phone certify code must be set if test phone number is set")
+		}
+		masked := make([]string, len(tps))
+		for i, pn := range tps {
+			masked[i] = domain.MaskPhoneNumber(pn)
+		}
+		log.Printf("WARNING: test phone is active, %v is certified with fixed code without sending message", masked)
+	}
+
+	for _, op := range cfg.MaintenanceDisabledOperations() {
+		if !maintenanceOps[op] {
+			log.Fatalf("unknown operation %s in maintenance disabled operations", op)
+		}
+	}
+
+	for _, c := range cfg.CertifyChannels() {
+		if !certifyChannels[domain.CertifyChannel(c)] {
+			log.Fatalf("unknown certify channel %s, please set it to sms, voice or email", c)
+		}
+	}
+
+	if !sessionLimitPolicies[cfg.SessionLimitPolicy()] {
+		log.Fatalf("unknown session limit policy %s, please set it to reject or evict_oldest", cfg.SessionLimitPolicy())
+	}
+
+	if ah == nil {
+		ah = noopAlertHook{}
+	}
+
 	return &authUsecase{
 		myCfg: cfg,
 
 		parentAuthRepository:         par,
 		parentPhoneCertifyRepository: ppr,
+		parentSessionRepository:      psr,
+		auditLogRepository:           alr,
+
+		parentWebAuthnCredentialRepository: pcr,
+		webAuthnCeremonyRepository:         wcr,
+		deviceVerificationRepository:       dvr,
+		authEventRepository:                aer,
+
+		parentNotificationPreferenceRepository: npr,
+		phoneCertifyEventRepository:            per,
 
 		txHandler:     th,
 		messageAgency: ma,
 		hashHandler:   hh,
 		jwtHandler:    jh,
 		s3Agency:      sa,
+		alertHook:     ah,
+
+		webAuthnAgency: wa,
+		clock:          cl,
+		kvStore:        kv,
+		pwnedChecker:   pc,
+
+		phoneNumberHasher: ph,
 	}
 }
 
@@ -73,11 +188,156 @@ type authUsecaseConfig interface {
 
 	// ParentProfileS3Bucket return aws s3 bucket name for parent profile
 	ParentProfileS3Bucket() string
+
+	// ParentIDChangeInterval return minimum interval between parent ID change (not limited if 0)
+	ParentIDChangeInterval() time.Duration
+
+	// PhoneStatusBatchSize return max number of phone number in one certify status lookup
+	PhoneStatusBatchSize() int
+
+	// CertifyMessageTemplate return template of certify code message sent for purpose, having certifyCodePlaceholder
+	CertifyMessageTemplate(purpose domain.CertifyPurpose) string
+
+	// CertifyCodeCharset return character set which certify code is generated from
+	CertifyCodeCharset() string
+
+	// CertifyCodeLength return length of certify code
+	CertifyCodeLength() int
+
+	// NewDeviceLoginNotification return if notify to parent phone when parent logged in from new device
+	NewDeviceLoginNotification() bool
+
+	// ParentDataExportInterval return minimum interval between parent data export (not limited if 0)
+	ParentDataExportInterval() time.Duration
+
+	// LoginLockoutThreshold return number of consecutive failed login locking account (not locked if 0)
+	LoginLockoutThreshold() int
+
+	// LoginLockoutDuration return duration for which account is locked
+	LoginLockoutDuration() time.Duration
+
+	// LockoutNotification return if notify to parent phone when account is locked
+	LockoutNotification() bool
+
+	// LockoutNotificationInterval return minimum interval between lockout notification to same parent
+	LockoutNotificationInterval() time.Duration
+
+	// CertifyCodeMismatchAlertThreshold return count of certify code mismatch in window firing alert (not alerted if 0)
+	CertifyCodeMismatchAlertThreshold() int
+
+	// CertifyCodeMismatchAlertWindow return window in which certify code mismatch is counted for alert
+	CertifyCodeMismatchAlertWindow() time.Duration
+
+	// RequirePhoneCertification return if certified phone is required to sign up (phone is optional if false)
+	RequirePhoneCertification() bool
+
+	// UncertifiedPhoneDetail return if state of certify code is responded in sign up with uncertified phone
+	UncertifiedPhoneDetail() bool
+
+	// WebAuthnCeremonyTimeout return duration in which passkey ceremony must be finished after begun
+	WebAuthnCeremonyTimeout() time.Duration
+
+	// StepUpTokenDuration return valid duration of elevated token issued with step-up auth
+	StepUpTokenDuration() time.Duration
+
+	// NewDeviceVerification return if login from unrecognized device require certify code sent to parent phone
+	NewDeviceVerification() bool
+
+	// DeviceVerificationTimeout return duration in which device verification must be completed after login
+	DeviceVerificationTimeout() time.Duration
+
+	// IdempotentPhoneCertify return if correct code submitted again to phone certified already is treated as success
+	IdempotentPhoneCertify() bool
+
+	// WelcomeMessage return if welcome message is sent to parent phone after sign up
+	WelcomeMessage() bool
+
+	// WelcomeMessageTemplate return template of welcome message, having {name} placeholder
+	WelcomeMessageTemplate() string
+
+	// PasswordBreachCheck return if password found in data breach is rejected in sign up & password change
+	PasswordBreachCheck() bool
+
+	// MaxAccountsPerPhone return max number of account created with one phone number in window (not limited if 0)
+	MaxAccountsPerPhone() int
+
+	// AccountsPerPhoneWindow return window in which account created with one phone number is counted
+	AccountsPerPhoneWindow() time.Duration
+
+	// PasswordVerifyAttemptLimit return max number of password mismatch in verification in window (not limited if 0)
+	PasswordVerifyAttemptLimit() int
+
+	// PasswordVerifyAttemptWindow return window in which password mismatch in verification is counted
+	PasswordVerifyAttemptWindow() time.Duration
+
+	// ParentIDReservationTTL return duration for which parent ID is held by ReserveParentID
+	ParentIDReservationTTL() time.Duration
+
+	// ParentIDReservationLimit return max number of ReserveParentID of one client IP in window (not limited if 0)
+	ParentIDReservationLimit() int
+
+	// ParentIDReservationWindow return window in which ReserveParentID of one client IP is counted
+	ParentIDReservationWindow() time.Duration
+
+	// AccountStatusCacheTTL return duration for which account status checked with token is cached (not cached if 0)
+	AccountStatusCacheTTL() time.Duration
+
+	// TestPhoneNumbers return phone number certified with fixed code without sending message (none if empty)
+	TestPhoneNumbers() []string
+
+	// TestPhoneCertifyCode return fixed certify code of test phone numbers
+	TestPhoneCertifyCode() string
+
+	// MaintenanceMode return if maintenance mode is on, used until mode is changed by admin
+	MaintenanceMode() bool
+
+	// MaintenanceDisabledOperations return operation rejected while maintenance mode is on (ex, sign_up)
+	MaintenanceDisabledOperations() []string
+
+	// MaxParentSessions return max number of active session of one parent, checked in login (not limited if 0)
+	MaxParentSessions() int
+
+	// SessionLimitPolicy return how login exceeding MaxParentSessions is handled (reject, evict_oldest)
+	SessionLimitPolicy() string
+
+	// SessionEvictionNotification return if notify to parent phone when session is evicted by login of other device
+	SessionEvictionNotification() bool
+
+	// CertifyChannels return channel which certify code can be sent through, others are rejected (ex, sms)
+	CertifyChannels() []string
+
+	// CodeVerifyAttemptLimit return max number of certify code mismatch in VerifyCertifyCodeOnly in window (not limited if 0)
+	CodeVerifyAttemptLimit() int
+
+	// CodeVerifyAttemptWindow return window in which certify code mismatch in VerifyCertifyCodeOnly is counted
+	CodeVerifyAttemptWindow() time.Duration
+
+	// RestartCertificationLimit return max number of RestartCertification of one phone in window (not limited if 0)
+	RestartCertificationLimit() int
+
+	// RestartCertificationWindow return window in which RestartCertification of one phone is counted
+	RestartCertificationWindow() time.Duration
+}
+
+// certifyCodePlaceholder is placeholder replaced with certify code in certify message template
+const certifyCodePlaceholder = "{code}"
+
+// certifyChannels is set of channel able to be enabled in CertifyChannels of config, used for validating config
+var certifyChannels = map[domain.CertifyChannel]bool{
+	domain.CertifyChannelSMS:   true,
+	domain.CertifyChannelVoice: true,
+	domain.CertifyChannelEmail: true,
 }
 
+// parentNamePlaceholder is placeholder replaced with parent name in welcome message template
+const parentNamePlaceholder = "{name}"
+
+// welcomeMessageFailedMetric is expvar counter of welcome message failed to be sent
+var welcomeMessageFailedMetric = expvar.NewInt("auth_welcome_message_failed")
+
 // txHandler is used for handling transaction to begin & commit or rollback
 type txHandler interface {
-	// BeginTx method start transaction (get option from ctx)
+	// BeginTx method start transaction (option is get from ctx with tx.OptionsFromContext)
 	BeginTx(ctx context.Context, opts interface{}) (tx tx.Context, err error)
 
 	// Commit method commit transaction
@@ -89,8 +349,14 @@ type txHandler interface {
 
 // messageAgency is agency that agent various API about message
 type messageAgency interface {
-	// SendSMSToOne method send SMS message to one receiver
-	SendSMSToOne(receiver, content string) (err error)
+	// SendSMSToOne method send SMS message to one receiver & return result including segment count
+	SendSMSToOne(receiver, content string) (result domain.SMSResult, err error)
+
+	// SendVoiceCode method send certify code by voice call to one receiver
+	SendVoiceCode(receiver, content string) (err error)
+
+	// SendEmail method send email to one receiver
+	SendEmail(receiver, title, content string) (err error)
 }
 
 // hashHandler is interface about hash handler
@@ -99,13 +365,54 @@ type hashHandler interface {
 	GenerateHashWithMinSalt(pw string) (hash string, err error)
 
 	// CompareHashAndPW compare hashed value and password & return error
+	// return error implementing NeedRehash() if password is matched but hash need to be regenerated
 	CompareHashAndPW(hash, pw string) (err error)
 }
 
+// pwnedChecker is interface about checker finding if password is breached (ex, HaveIBeenPwned range API)
+type pwnedChecker interface {
+	// IsBreached method return if password is found in data breach
+	IsBreached(pw string) (breached bool, err error)
+}
+
+// phoneNumberHasher is interface about hasher of phone number (ex, HMAC keyed with server-side secret)
+type phoneNumberHasher interface {
+	// Hash return deterministic hash of phone number, so that state of phone is looked up with it
+	Hash(pn string) string
+}
+
 // jwtHandler is interface about JWT handler
 type jwtHandler interface {
 	// GenerateUUIDJWT generate & return JWT UUID token with type & time
+	// return error implementing SigningFailed() if failed to sign token
 	GenerateUUIDJWT(uuid, _type string, t time.Duration) (token string, err error)
+
+	// GenerateScopedUUIDJWT generate & return JWT UUID token like GenerateUUIDJWT, having scope claim
+	GenerateScopedUUIDJWT(uuid, _type, scope string, t time.Duration) (token string, err error)
+}
+
+// clock is interface about clock returning current time (ex, fake clock advanced manually in test)
+type clock interface {
+	// Now method return current time
+	Now() time.Time
+}
+
+// kvStore is interface about key-value store keeping state with TTL (ex, kv.Memory, kv.Redis)
+type kvStore interface {
+	// Incr method increase counter of key by 1 & return it, and ttl is set only if key is created
+	Incr(key string, ttl time.Duration) (int64, error)
+
+	// Get method return value of key & if key exist
+	Get(key string) (string, bool, error)
+
+	// SetNX method set value of key with ttl only if key doesn't exist, and return if value is set
+	SetNX(key, value string, ttl time.Duration) (bool, error)
+
+	// Set method set value of key with ttl, overwriting existing value (never expire if 0)
+	Set(key, value string, ttl time.Duration) error
+
+	// Delete method delete key, and do nothing if key doesn't exist
+	Delete(key string) error
 }
 
 // s3Agency is agency that agent various API about aws s3
@@ -115,8 +422,20 @@ type s3Agency interface {
 }
 
 // SendCertifyCodeToPhone implement SendCertifyCodeToPhone method of domain.AuthUsecase interface
-func (au *authUsecase) SendCertifyCodeToPhone(ctx context.Context, pn string) (err error) {
-	_tx, err := au.txHandler.BeginTx(ctx, nil)
+func (au *authUsecase) SendCertifyCodeToPhone(ctx context.Context, pn, ownerUUID string, purpose domain.CertifyPurpose, dest domain.CertifyCodeDestination) (err error) {
+	if err = au.checkMaintenance(maintenanceOpSendCertifyCode); err != nil {
+		return
+	}
+	if purpose == "" {
+		purpose = domain.CertifyPurposeSignUp
+	}
+	if dest.Channel != "" && !au.isEnabledCertifyChannel(dest.Channel) {
+		err = errors.Errorf("certify channel %s is not enabled in this server", dest.Channel)
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusBadRequest, Code: domain.UnsupportedCertifyChannel}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
 	if err != nil {
 		err = errors.Wrap(err, "failed to begin transaction")
 		return
@@ -125,31 +444,42 @@ func (au *authUsecase) SendCertifyCodeToPhone(ctx context.Context, pn string) (e
 	ppc, err := au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn)
 	switch err.(type) {
 	case nil:
-		if domain.StringValue(ppc.ParentUUID) != "" {
-			err = errors.New("this phone number is already in use")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyInUse}
-			_ = au.txHandler.Rollback(_tx)
-			return
-		}
-		ppc.CertifyCode = domain.Int64(ppc.GenerateCertifyCode())
-		ppc.Certified = domain.Bool(false)
-		switch err = au.parentPhoneCertifyRepository.Update(_tx, &ppc); err.(type) {
-		case nil:
-			break
-		default:
-			err = errors.Wrap(err, "phone Update return unexpected error")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		if err = au.renewCertifyCode(_tx, &ppc, ownerUUID, au.certifyChannelFor(ppc, &dest)); err != nil {
 			_ = au.txHandler.Rollback(_tx)
 			return
 		}
 	case domain.ErrRowNotExist:
 		ppc = domain.ParentPhoneCertify{
 			PhoneNumber: domain.String(pn),
-			CertifyCode: domain.Int64(ppc.GenerateCertifyCode()),
+			CertifyCode: domain.String(au.newCertifyCode(pn)),
 		}
-		switch err = au.parentPhoneCertifyRepository.Store(_tx, &ppc); err.(type) {
+		switch err = au.parentPhoneCertifyRepository.Store(_tx, &ppc); tErr := err.(type) {
 		case nil:
 			break
+		case domain.ErrEntryDuplicate:
+			if tErr.DuplicateKey != domain.DuplicateKeyPrimary {
+				err = errors.Wrap(err, "phone Store return unexpected duplicate error")
+				err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+				_ = au.txHandler.Rollback(_tx)
+				return
+			}
+			// row was stored by concurrent request, so re-read it & fall back to path updating existing row
+			// (row may not be visible in snapshot of this transaction, then it is unlinked one stored by sending code)
+			switch stored, err := au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn); err.(type) {
+			case nil:
+				ppc = stored
+			case domain.ErrRowNotExist:
+				break
+			default:
+				err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
+				err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+				_ = au.txHandler.Rollback(_tx)
+				return err
+			}
+			if err = au.renewCertifyCode(_tx, &ppc, ownerUUID, au.certifyChannelFor(ppc, &dest)); err != nil {
+				_ = au.txHandler.Rollback(_tx)
+				return
+			}
 		default:
 			err = errors.Wrap(err, "phone Store return unexpected error")
 			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
@@ -163,21 +493,146 @@ func (au *authUsecase) SendCertifyCodeToPhone(ctx context.Context, pn string) (e
 		return
 	}
 
-	content := fmt.Sprintf("[육아는 처음이지 인증 번호]\n회원가입 인증 번호: %d", domain.Int64Value(ppc.CertifyCode))
-	if err = au.messageAgency.SendSMSToOne(domain.StringValue(ppc.PhoneNumber), content); err != nil {
-		err = errors.Wrap(err, "SendSMSToOne return unexpected error")
+	channel := au.certifyChannelFor(ppc, &dest)
+	// channel is recorded before sending, so that row isn't left changed if code is sent but update failed
+	if err = au.parentPhoneCertifyRepository.Update(_tx, &domain.ParentPhoneCertify{
+		PhoneNumber: ppc.PhoneNumber,
+		SentChannel: domain.String(string(channel)),
+	}); err != nil {
+		err = errors.Wrap(err, "phone Update return unexpected error")
 		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
 		_ = au.txHandler.Rollback(_tx)
 		return
 	}
 
-	_ = au.txHandler.Commit(_tx)
+	// fixed code of test phone is known to tester already, so message is never sent to it
+	if au.isTestPhone(pn) {
+		log.Printf("certify code isn't sent to test phone %s, since it is certified with fixed code", domain.MaskPhoneNumber(pn))
+		_ = au.txHandler.Commit(_tx)
+		return nil
+	}
+
+	content := au.certifyMessage(purpose, domain.StringValue(ppc.CertifyCode))
+	if err = certifyCodeSendError(au.sendCertifyCode(domain.StringValue(ppc.PhoneNumber), content, dest)); err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// code is sent already, so if it isn't stored, code received by user never match & user must be asked to resend
+	if err = au.txHandler.Commit(_tx); err != nil {
+		log.Printf("ERROR: certify code is sent to %s but failed to commit it, so the code can't be certified, err: %v",
+			domain.MaskPhoneNumber(pn), err)
+		err = errors.Wrap(err, "certify code is sent but failed to commit it")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusServiceUnavailable, Code: domain.CertifyCodeSentButNotPersisted}
+		return
+	}
+	au.recordAuthEvent(ctx, domain.AuthEventCodeSent)
+	au.recordPhoneCertifyEvent(ctx, pn, domain.PhoneCertifyEventSent, channel)
 	return nil
 }
 
+// certifyCodeSendError return domain.UsecaseError classified from err returned in sending certify code, or nil if err is nil
+// error rejecting channel or receiver is client error, and only unexpected error of provider is internal error
+func certifyCodeSendError(err error) error {
+	switch err.(type) {
+	case nil:
+		return nil
+	case interface{ Unsupported() }:
+		err = errors.Wrap(err, "this channel is not supported to send certify code")
+		return domain.UsecaseError{UsecaseErr: err, Status: http.StatusBadRequest, Code: domain.UnsupportedCertifyChannel}
+	case interface{ InvalidReceiver() }:
+		err = errors.Wrap(err, "certify code can't be sent to this phone number")
+		return domain.UsecaseError{UsecaseErr: err, Status: http.StatusBadRequest, Code: domain.InvalidPhoneNumber}
+	// transient error was retried in message agency already, so client is asked to retry later
+	case interface{ Transient() }, interface{ Throttled() }:
+		err = errors.Wrap(err, "message provider is unavailable temporarily, retry later")
+		return domain.UsecaseError{UsecaseErr: err, Status: http.StatusServiceUnavailable}
+	default:
+		err = errors.Wrap(err, "failed to send certify code")
+		return domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+}
+
+// certifyChannelFor method return channel which certify code is sent through to phone of ppc, setting it to dest
+// channel of last successful certification is used if channel isn't chosen in request (and it is still enabled)
+// (email channel isn't used without address in request, since address is not stored)
+func (au *authUsecase) certifyChannelFor(ppc domain.ParentPhoneCertify, dest *domain.CertifyCodeDestination) domain.CertifyChannel {
+	if preferred := domain.CertifyChannel(domain.StringValue(ppc.PreferredChannel)); dest.Channel == "" &&
+		au.isEnabledCertifyChannel(preferred) && (preferred != domain.CertifyChannelEmail || dest.Email != "") {
+		dest.Channel = preferred
+	}
+	if dest.Channel == "" {
+		return domain.CertifyChannelSMS
+	}
+	return dest.Channel
+}
+
+// isEnabledCertifyChannel method return if certify code can be sent through channel, by CertifyChannels of config
+func (au *authUsecase) isEnabledCertifyChannel(channel domain.CertifyChannel) bool {
+	for _, c := range au.myCfg.CertifyChannels() {
+		if domain.CertifyChannel(c) == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// renewCertifyCode method replace certify code of phone row already stored with new one, if it can be sent to owner
+// (phone linked to other parent can't be). err is domain.UsecaseError, and transaction must be rolled back by caller on err
+// active code (sent but not certified yet) is kept if only channel is changed, so that code sent before isn't invalidated
+func (au *authUsecase) renewCertifyCode(_tx tx.Context, ppc *domain.ParentPhoneCertify, ownerUUID string, channel domain.CertifyChannel) (err error) {
+	linked := domain.StringValue(ppc.ParentUUID) != ""
+	if linked && (ownerUUID == "" || domain.StringValue(ppc.ParentUUID) != ownerUUID) {
+		err = errors.New("this phone number is already in use")
+		return domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyInUse}
+	}
+	if sent := domain.StringValue(ppc.SentChannel); !linked && !domain.BoolValue(ppc.Certified) &&
+		domain.StringValue(ppc.CertifyCode) != "" && sent != "" && sent != string(channel) {
+		return
+	}
+	ppc.CertifyCode = domain.String(au.newCertifyCode(domain.StringValue(ppc.PhoneNumber)))
+	if !linked {
+		// re-verification of linked phone is checked with VerifyCertifyCodeOnly, so keep certified state of it
+		ppc.Certified = domain.Bool(false)
+	}
+	if err = au.parentPhoneCertifyRepository.Update(_tx, ppc); err != nil {
+		err = errors.Wrap(err, "phone Update return unexpected error")
+		return domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+	return
+}
+
+// certifyMessage method return content of message delivering certify code, made from template of purpose
+func (au *authUsecase) certifyMessage(purpose domain.CertifyPurpose, code string) string {
+	return strings.ReplaceAll(au.myCfg.CertifyMessageTemplate(purpose), certifyCodePlaceholder, code)
+}
+
+// sendCertifyCode method send certify code content to phone number or email through channel in dest
+func (au *authUsecase) sendCertifyCode(pn, content string, dest domain.CertifyCodeDestination) (err error) {
+	// error returned from messageAgency is not wrapped, to keep error type asserted in caller
+	switch dest.Channel {
+	case domain.CertifyChannelSMS, "":
+		// queued message is accepted by provider, so it is success for client though delivery isn't confirmed yet
+		var result domain.SMSResult
+		if result, err = au.messageAgency.SendSMSToOne(pn, content); err == nil && result.Status == domain.SMSStatusQueued {
+			log.Printf("certify code to %s is queued in message provider", domain.MaskPhoneNumber(pn))
+		}
+	case domain.CertifyChannelVoice:
+		err = au.messageAgency.SendVoiceCode(pn, content)
+	case domain.CertifyChannelEmail:
+		if dest.Email == "" {
+			return errors.New("email must be set to send certify code by email")
+		}
+		err = au.messageAgency.SendEmail(dest.Email, "[육아는 처음이지 인증 번호]", content)
+	default:
+		err = errors.Errorf("unknown certify channel %s", dest.Channel)
+	}
+	return
+}
+
 // CertifyPhoneWithCode implement CertifyPhoneWithCode method of domain.AuthUsecase interface
-func (au *authUsecase) CertifyPhoneWithCode(ctx context.Context, pn string, code int64) (err error) {
-	_tx, err := au.txHandler.BeginTx(ctx, nil)
+func (au *authUsecase) CertifyPhoneWithCode(ctx context.Context, pn, code string) (status domain.PhoneCertifyStatus, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
 	if err != nil {
 		err = errors.Wrap(err, "failed to begin transaction")
 		return
@@ -186,19 +641,40 @@ func (au *authUsecase) CertifyPhoneWithCode(ctx context.Context, pn string, code
 	ppc, err := au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn)
 	switch err.(type) {
 	case nil:
+		// certifying phone is for signing up, which fail with phone linked to parent, so it is rejected early
+		// (owner of phone re-verify it with VerifyCertifyCodeOnly, which is authenticated with token)
+		if domain.StringValue(ppc.ParentUUID) != "" {
+			err = errors.New("this phone number is linked to another parent")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneOwnedByAnotherParent}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+		// retried request of client is succeeded again
+		if domain.BoolValue(ppc.Certified) &&
+			au.myCfg.IdempotentPhoneCertify() && au.isCorrectCertifyCode(ppc, code) {
+			// nothing is changed, so rollback rather than commit
+			_ = au.txHandler.Rollback(_tx)
+			status = domain.PhoneCertifyStatus{PhoneNumber: pn, Exist: true, Certified: true, InUse: false}
+			return
+		}
 		if domain.BoolValue(ppc.Certified) == true {
 			err = errors.New("this phone number is already certified")
 			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyCertified}
 			_ = au.txHandler.Rollback(_tx)
 			return
 		}
-		if code != domain.Int64Value(ppc.CertifyCode) {
+		if !au.isCorrectCertifyCode(ppc, code) {
+			au.certifyCodeMismatched()
 			err = errors.New("incorrect certify code to that phone number")
 			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.IncorrectCertifyCode}
 			_ = au.txHandler.Rollback(_tx)
+			au.recordAuthEvent(ctx, domain.AuthEventCertifyFailed)
+			au.recordPhoneCertifyEvent(ctx, pn, domain.PhoneCertifyEventVerifyFailed, domain.CertifyChannel(domain.StringValue(ppc.SentChannel)))
 			return
 		}
 		ppc.Certified = domain.Bool(true)
+		// channel which code was sent through is preferred in next sending, since code arrived through it
+		ppc.PreferredChannel = ppc.SentChannel
 		switch err = au.parentPhoneCertifyRepository.Update(_tx, &ppc); err.(type) {
 		case nil:
 			break
@@ -221,218 +697,1805 @@ func (au *authUsecase) CertifyPhoneWithCode(ctx context.Context, pn string, code
 	}
 
 	_ = au.txHandler.Commit(_tx)
-	return nil
+	au.recordAuthEvent(ctx, domain.AuthEventCertifySucceeded)
+	au.recordPhoneCertifyEvent(ctx, pn, domain.PhoneCertifyEventVerifySucceeded, domain.CertifyChannel(domain.StringValue(ppc.SentChannel)))
+	status = domain.PhoneCertifyStatus{
+		PhoneNumber: pn,
+		Exist:       true,
+		Certified:   domain.BoolValue(ppc.Certified),
+		InUse:       domain.StringValue(ppc.ParentUUID) != "",
+	}
+	return
 }
 
-// SignUpParent implement SignUpParent method of domain.AuthUsecase interface
-func (au *authUsecase) SignUpParent(ctx context.Context, pi struct {
-	*domain.ParentAuth
-	*domain.ParentPhoneCertify
-}, profile []byte) (uuid string, err error) {
-	_tx, err := au.txHandler.BeginTx(ctx, nil)
+// LinkPhoneToParent implement LinkPhoneToParent method of domain.AuthUsecase interface
+func (au *authUsecase) LinkPhoneToParent(ctx context.Context, uuid, pn, code string) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
 	if err != nil {
 		err = errors.Wrap(err, "failed to begin transaction")
 		return
 	}
 
-	ppc, err := au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, domain.StringValue(pi.PhoneNumber))
-	if err == nil && domain.BoolValue(ppc.Certified) == true {
+	switch pa, err := au.parentAuthRepository.GetByUUID(_tx, uuid); err.(type) {
+	case nil:
+		if domain.StringValue(pa.PhoneNumber) != "" {
+			err = errors.New("phone number is already linked to this parent")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentPhoneAlreadyLinked}
+			_ = au.txHandler.Rollback(_tx)
+			return err
+		}
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent auth with that uuid")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return err
+	default:
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return err
+	}
+
+	ppc, err := au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn)
+	switch err.(type) {
+	case nil:
 		if domain.StringValue(ppc.ParentUUID) != "" {
 			err = errors.New("this phone number is already in use")
 			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyInUse}
 			_ = au.txHandler.Rollback(_tx)
 			return
 		}
-
-		if hash, err := au.hashHandler.GenerateHashWithMinSalt(domain.StringValue(pi.PW)); err != nil {
-			err = errors.Wrap(err, "failed to GenerateHashWithMinSalt")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
-			_ = au.txHandler.Rollback(_tx)
-			return "", err
-		} else {
-			pi.PW = domain.String(hash)
-		}
-
-		if uuid, err = au.parentAuthRepository.GetAvailableUUID(_tx); err != nil {
-			pi.UUID = domain.String(pi.GenerateRandomUUID())
-		} else {
-			pi.UUID = domain.String(uuid)
-		}
-		if profile != nil && string(profile) != "" {
-			pi.ProfileUri = domain.String(pi.ParentAuth.GenerateProfileUri())
-		}
-
-		switch err = au.parentAuthRepository.Store(_tx, pi.ParentAuth); tErr := err.(type) {
-		case nil:
-			break
-		case domain.ErrInvalidModel:
-			err = errors.Wrap(err, "parent auth Store return invalid model")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
-			_ = au.txHandler.Rollback(_tx)
-			return
-		case domain.ErrEntryDuplicate:
-			switch tErr.DuplicateKey {
-			case "id", "parent_auth.id":
-				err = errors.New("this parent ID is already in use")
-				err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentIDAlreadyInUse}
-				_ = au.txHandler.Rollback(_tx)
-				return
-			default:
-				err = errors.Wrap(err, "parent auth Store return unexpected duplicate error")
-				err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
-				_ = au.txHandler.Rollback(_tx)
-				return
-			}
-		default:
-			err = errors.Wrap(err, "parent auth Store return unexpected error")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		// certified phone means its code is already used, so code must be sent again to be fresh
+		if domain.BoolValue(ppc.Certified) {
+			err = errors.New("certify code of this phone number is already used, request certify code again")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyCertified}
 			_ = au.txHandler.Rollback(_tx)
 			return
 		}
-	} else {
-		if _, ok := err.(domain.ErrRowNotExist); err == nil || ok {
-			err = errors.New("this phone number is not certified")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.UncertifiedPhone}
-			_ = au.txHandler.Rollback(_tx)
-			return
-		} else {
-			err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		if !au.isCorrectCertifyCode(ppc, code) {
+			au.certifyCodeMismatched()
+			err = errors.New("incorrect certify code to that phone number")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.IncorrectCertifyCode}
 			_ = au.txHandler.Rollback(_tx)
 			return
 		}
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist phone number, certify code must be requested before")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
 	}
 
-	ppc.ParentUUID = domain.String(domain.StringValue(pi.UUID))
-	if err = au.parentPhoneCertifyRepository.Update(_tx, &ppc); err != nil {
+	switch err = au.parentPhoneCertifyRepository.Update(_tx, &domain.ParentPhoneCertify{
+		PhoneNumber: domain.String(pn),
+		ParentUUID:  domain.String(uuid),
+		Certified:   domain.Bool(true),
+	}); err.(type) {
+	case nil:
+		break
+	case domain.ErrEntryDuplicate:
+		// another phone can be linked to parent between GetByUUID and Update
+		err = errors.New("phone number is already linked to this parent")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentPhoneAlreadyLinked}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
 		err = errors.Wrap(err, "phone Update return unexpected error")
 		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
 		_ = au.txHandler.Rollback(_tx)
 		return
 	}
 
-	if profile != nil && string(profile) != "" {
-		if _, err = au.s3Agency.PutObject(&s3.PutObjectInput{
-			Bucket: aws.String(au.myCfg.ParentProfileS3Bucket()),
-			Key:    aws.String(pi.ParentAuth.GenerateProfileUri()),
-			Body:   bytes.NewReader(profile),
-			ACL:    aws.String("public-read"),
-		}); err != nil {
-			err = errors.Wrap(err, "s3 PutObject return unexpected error")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
-			_ = au.txHandler.Rollback(_tx)
-			return
-		}
+	_ = au.txHandler.Commit(_tx)
+	au.invalidateAccountStatus(uuid)
+	log.Printf("phone %s is linked to parent %s", domain.MaskPhoneNumber(pn), uuid)
+	return
+}
+
+// SendStepUpCode implement SendStepUpCode method of domain.AuthUsecase interface
+func (au *authUsecase) SendStepUpCode(ctx context.Context, uuid string) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	ppc, err := au.stepUpPhoneOf(_tx, uuid)
+	_ = au.txHandler.Rollback(_tx) // nothing is written, and code is renewed in SendCertifyCodeToPhone
+	if err != nil {
+		return
+	}
+	return au.SendCertifyCodeToPhone(ctx, domain.StringValue(ppc.PhoneNumber), uuid, domain.CertifyPurposeStepUp, domain.CertifyCodeDestination{})
+}
+
+// stepUpPhoneOf method return phone linked to parent, which certify code of step-up is sent to & verified with
+// err is domain.UsecaseError with 409 if phone isn't linked or certified, since it doesn't prove parent then
+func (au *authUsecase) stepUpPhoneOf(_tx tx.Context, uuid string) (ppc domain.ParentPhoneCertify, err error) {
+	var pn string
+	switch pa, err := au.parentAuthRepository.GetByUUID(_tx, uuid); err.(type) {
+	case nil:
+		if pn = domain.StringValue(pa.PhoneNumber); pn == "" {
+			err = errors.New("no phone number is linked to this parent")
+			return ppc, domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.UncertifiedPhone}
+		}
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent auth with that uuid")
+		return ppc, domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+	default:
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		return ppc, domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+
+	if ppc, err = au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn); err != nil {
+		err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
+		return ppc, domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+	// phone linked without certification (RequirePhoneCertification false) doesn't prove parent
+	if !domain.BoolValue(ppc.Certified) {
+		err = errors.New("phone number linked to this parent is not certified")
+		return ppc, domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.UncertifiedPhone}
+	}
+	return
+}
+
+// StepUpWithPhoneCode implement StepUpWithPhoneCode method of domain.AuthUsecase interface
+func (au *authUsecase) StepUpWithPhoneCode(ctx context.Context, uuid, code string) (token string, err error) {
+	if err = au.checkCodeVerifyAttempts(uuid); err != nil {
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	ppc, err := au.stepUpPhoneOf(_tx, uuid)
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// code is replaced with one not sent to anyone whether matched or not, so that it can't be used for step-up again
+	// and wrong code can't be guessed again with same code (new code must be sent with SendStepUpCode)
+	matched := au.isCorrectCertifyCode(ppc, code)
+	ppc.CertifyCode = domain.String(ppc.GenerateCertifyCode(au.myCfg.CertifyCodeCharset(), au.myCfg.CertifyCodeLength()))
+	if err = au.parentPhoneCertifyRepository.Update(_tx, &ppc); err != nil {
+		err = errors.Wrap(err, "phone Update return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if !matched {
+		_ = au.txHandler.Commit(_tx)
+		au.certifyCodeMismatched()
+		au.countCodeVerifyMismatch(uuid)
+		err = errors.New("incorrect certify code to phone number of this parent")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.IncorrectCertifyCode}
+		return
+	}
+
+	switch token, err = au.jwtHandler.GenerateScopedUUIDJWT(uuid, "access_token", domain.TokenScopeElevated, au.myCfg.StepUpTokenDuration()); err.(type) {
+	case nil:
+		break
+	case interface{ SigningFailed() }:
+		err = errors.Wrap(err, "failed to issue elevated token")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError, Code: domain.TokenIssueFailed}
+		_ = au.txHandler.Rollback(_tx)
+		return "", err
+	default:
+		err = errors.Wrap(err, "GenerateScopedUUIDJWT return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return "", err
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	return
+}
+
+// GetParentPhoneCertification implement GetParentPhoneCertification method of domain.AuthUsecase interface
+// count of certify attempt is not stored per phone yet, so only certify status of phone is returned
+func (au *authUsecase) GetParentPhoneCertification(ctx context.Context, uuid string) (status domain.PhoneCertifyStatus, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	ppc, err := au.linkedPhoneCertify(_tx, uuid)
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// nothing is changed, so rollback rather than commit
+	_ = au.txHandler.Rollback(_tx)
+	status = domain.PhoneCertifyStatus{
+		PhoneNumber: domain.StringValue(ppc.PhoneNumber),
+		Exist:       true,
+		Certified:   domain.BoolValue(ppc.Certified),
+		InUse:       true,
+	}
+	return
+}
+
+// CancelParentPhoneCertification implement CancelParentPhoneCertification method of domain.AuthUsecase interface
+func (au *authUsecase) CancelParentPhoneCertification(ctx context.Context, uuid string) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	ppc, err := au.linkedPhoneCertify(_tx, uuid)
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// code is replaced with one not sent to anyone, so that code sent before can't be used any more
+	ppc.CertifyCode = domain.String(ppc.GenerateCertifyCode(au.myCfg.CertifyCodeCharset(), au.myCfg.CertifyCodeLength()))
+	if err = au.parentPhoneCertifyRepository.Update(_tx, &ppc); err != nil {
+		err = errors.Wrap(err, "phone Update return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	log.Printf("certify code of phone %s is cancelled by parent %s", domain.MaskPhoneNumber(domain.StringValue(ppc.PhoneNumber)), uuid)
+	return
+}
+
+// linkedPhoneCertify method return phone certify row of phone linked to parent with uuid
+// err is domain.UsecaseError, and transaction must be rolled back by caller on err
+func (au *authUsecase) linkedPhoneCertify(_tx tx.Context, uuid string) (ppc domain.ParentPhoneCertify, err error) {
+	var pn string
+	switch pa, err := au.parentAuthRepository.GetByUUID(_tx, uuid); err.(type) {
+	case nil:
+		if pn = domain.StringValue(pa.PhoneNumber); pn == "" {
+			err = errors.New("no phone number is linked to this parent")
+			return ppc, domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		}
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent auth with that uuid")
+		return ppc, domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+	default:
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		return ppc, domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+
+	if ppc, err = au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn); err != nil {
+		err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+	return
+}
+
+// RestartCertification implement RestartCertification method of domain.AuthUsecase interface
+func (au *authUsecase) RestartCertification(ctx context.Context, pn string) (err error) {
+	// route isn't authenticated, so restarting is limited per phone (in addition to rate limit per client IP)
+	if limit := au.myCfg.RestartCertificationLimit(); limit > 0 {
+		switch n, iErr := au.kvStore.Incr(au.phoneNumberKey("auth:restart_certification:", pn), au.myCfg.RestartCertificationWindow()); {
+		case iErr != nil:
+			log.Printf("failed to count restarting certification, so it is allowed, err: %v", iErr)
+		case n > int64(limit):
+			err = errors.New("certification of this phone number is restarted too many times, retry later")
+			return domain.UsecaseError{UsecaseErr: err, Status: http.StatusTooManyRequests}
+		}
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	switch ppc, err := au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn); err.(type) {
+	case nil:
+		if domain.StringValue(ppc.ParentUUID) != "" {
+			err = errors.New("this phone number is already in use")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyInUse}
+			_ = au.txHandler.Rollback(_tx)
+			return err
+		}
+		// certified phone is waiting for sign up, so that it must not be reset by anyone knowing the number
+		if domain.BoolValue(ppc.Certified) {
+			err = errors.New("this phone number is already certified")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyCertified}
+			_ = au.txHandler.Rollback(_tx)
+			return err
+		}
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist phone number")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return err
+	default:
+		err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return err
+	}
+
+	if err = au.parentPhoneCertifyRepository.Delete(_tx, pn); err != nil {
+		err = errors.Wrap(err, "phone Delete return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	return
+}
+
+// VerifyCertifyCodeOnly implement VerifyCertifyCodeOnly method of domain.AuthUsecase interface
+// phone not linked to parent is reported as not exist, so that it can't be used for guessing code of other phone
+func (au *authUsecase) VerifyCertifyCodeOnly(ctx context.Context, uuid, pn, code string) (matched bool, err error) {
+	if err = au.checkCodeVerifyAttempts(uuid); err != nil {
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	var channel domain.CertifyChannel
+	switch ppc, err := au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn); err.(type) {
+	case nil:
+		if domain.StringValue(ppc.ParentUUID) != uuid {
+			err = errors.New("not exist phone number linked to this parent")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+			_ = au.txHandler.Rollback(_tx)
+			return false, err
+		}
+		if matched = au.isCorrectCertifyCode(ppc, code); !matched {
+			au.certifyCodeMismatched()
+		}
+		channel = domain.CertifyChannel(domain.StringValue(ppc.SentChannel))
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist phone number linked to this parent")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return false, err
+	default:
+		err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return false, err
+	}
+
+	// nothing is changed, so rollback rather than commit
+	_ = au.txHandler.Rollback(_tx)
+	if matched {
+		au.recordPhoneCertifyEvent(ctx, pn, domain.PhoneCertifyEventVerifySucceeded, channel)
+		return
+	}
+	au.countCodeVerifyMismatch(uuid)
+	au.recordPhoneCertifyEvent(ctx, pn, domain.PhoneCertifyEventVerifyFailed, channel)
+	return
+}
+
+// codeVerifyMismatchKey return key of kvStore counting certify code mismatch of parent in re-verification & step-up
+func codeVerifyMismatchKey(uuid string) string {
+	return "auth:code_verify_mismatch:" + uuid
+}
+
+// checkCodeVerifyAttempts method return domain.UsecaseError if parent mismatched code CodeVerifyAttemptLimit times in window
+// count is shared by re-verification & step-up, so that code of linked phone can't be guessed more by using both
+func (au *authUsecase) checkCodeVerifyAttempts(uuid string) (err error) {
+	limit := au.myCfg.CodeVerifyAttemptLimit()
+	if limit <= 0 {
+		return
+	}
+
+	switch count, exist, gErr := au.kvStore.Get(codeVerifyMismatchKey(uuid)); {
+	case gErr != nil:
+		log.Printf("failed to get count of certify code mismatch in verification, so it is allowed, err: %v", gErr)
+	case exist:
+		if n, _ := strconv.Atoi(count); n >= limit {
+			err = errors.New("too many certify code mismatch in verification, retry later")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusTooManyRequests, Code: domain.TooManyCodeAttempts}
+		}
+	}
+	return
+}
+
+// countCodeVerifyMismatch method increase count of certify code mismatch of parent, checked in checkCodeVerifyAttempts
+func (au *authUsecase) countCodeVerifyMismatch(uuid string) {
+	if au.myCfg.CodeVerifyAttemptLimit() <= 0 {
+		return
+	}
+	if _, err := au.kvStore.Incr(codeVerifyMismatchKey(uuid), au.myCfg.CodeVerifyAttemptWindow()); err != nil {
+		log.Printf("failed to count certify code mismatch in verification, err: %v", err)
+	}
+}
+
+// CheckPhoneEligibility implement CheckPhoneEligibility method of domain.AuthUsecase interface
+func (au *authUsecase) CheckPhoneEligibility(ctx context.Context, pn string) (eligible bool, reason string, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	ppc, err := au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn)
+	switch err.(type) {
+	case nil:
+		break
+	case domain.ErrRowNotExist:
+		_ = au.txHandler.Commit(_tx)
+		return false, domain.PhoneIneligibleNotCertified, nil
+	default:
+		err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	_ = au.txHandler.Commit(_tx)
+
+	switch {
+	case domain.StringValue(ppc.ParentUUID) != "":
+		return false, domain.PhoneIneligibleAlreadyInUse, nil
+	case !domain.BoolValue(ppc.Certified):
+		return false, domain.PhoneIneligibleNotCertified, nil
+	}
+	return true, "", nil
+}
+
+// ValidateSignUpParent implement ValidateSignUpParent method of domain.AuthUsecase interface
+func (au *authUsecase) ValidateSignUpParent(ctx context.Context, id, pn string) (invalid map[string]string, err error) {
+	invalid = map[string]string{}
+
+	if pn != "" {
+		eligible, reason, err := au.CheckPhoneEligibility(ctx, pn)
+		if err != nil {
+			return nil, err
+		}
+		if !eligible {
+			invalid["phone_number"] = reason
+		}
+	}
+
+	if id == "" {
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	exist, err := au.parentAuthRepository.ExistsByID(_tx, domain.NormalizeParentID(id))
+	_ = au.txHandler.Rollback(_tx) // nothing is written
+	if err != nil {
+		err = errors.Wrap(err, "ExistsByID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return nil, err
+	}
+	if exist {
+		invalid["id"] = domain.ParentIDAlreadyInUseReason
+	}
+	return
+}
+
+// SignUpParent implement SignUpParent method of domain.AuthUsecase interface
+func (au *authUsecase) SignUpParent(ctx context.Context, pi struct {
+	*domain.ParentAuth
+	*domain.ParentPhoneCertify
+}, profile []byte, reservation string) (uuid string, err error) {
+	if err = au.checkMaintenance(maintenanceOpSignUp); err != nil {
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+	pi.ID = domain.String(domain.NormalizeParentID(domain.StringValue(pi.ID)))
+	pi.Name = domain.String(domain.NormalizeParentName(domain.StringValue(pi.Name)))
+
+	if err = au.checkParentIDReservation(domain.StringValue(pi.ID), reservation); err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// if phone certification is not required, phone is optional and linked without certification if provided
+	pn, requireCert := domain.StringValue(pi.PhoneNumber), au.myCfg.RequirePhoneCertification()
+	var ppc domain.ParentPhoneCertify
+	if requireCert || pn != "" {
+		ppc, err = au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn)
+	}
+	switch err.(type) {
+	case nil:
+		if requireCert && !domain.BoolValue(ppc.Certified) {
+			err = au.uncertifiedPhoneError(&ppc)
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+		if domain.StringValue(ppc.ParentUUID) != "" {
+			err = errors.New("this phone number is already in use")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyInUse}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	case domain.ErrRowNotExist:
+		if requireCert {
+			err = au.uncertifiedPhoneError(nil)
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	default:
+		err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	_, newPhone := err.(domain.ErrRowNotExist)
+
+	if err = au.checkAccountsPerPhone(pn); err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// hash is generated while checking breach & getting UUID, since it is independent of them and CPU-heavy
+	// it is started after phone check, so that it is never generated for sign up rejected by phone
+	hashed := au.generateHashAsync(domain.StringValue(pi.PW))
+
+	if err = au.checkPasswordBreached(domain.StringValue(pi.PW)); err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if uuid, err = au.parentAuthRepository.GetAvailableUUID(_tx); err != nil {
+		pi.UUID = domain.String(pi.GenerateRandomUUID())
+	} else {
+		pi.UUID = domain.String(uuid)
+	}
+
+	if hr := <-hashed; hr.err != nil {
+		err = errors.Wrap(hr.err, "failed to GenerateHashWithMinSalt")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return "", err
+	} else {
+		pi.PW = domain.String(hr.hash)
+	}
+	if profile != nil && string(profile) != "" {
+		pi.ProfileUri = domain.String(pi.ParentAuth.GenerateProfileUri())
+	}
+
+	switch err = au.parentAuthRepository.Store(_tx, pi.ParentAuth); tErr := err.(type) {
+	case nil:
+		break
+	case domain.ErrInvalidModel:
+		err = errors.Wrap(err, "parent auth Store return invalid model")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	case domain.ErrEntryDuplicate:
+		switch tErr.DuplicateKey {
+		case domain.DuplicateKeyParentID:
+			err = errors.New("this parent ID is already in use")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentIDAlreadyInUse}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		default:
+			err = errors.Wrap(err, "parent auth Store return unexpected duplicate error")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	default:
+		err = errors.Wrap(err, "parent auth Store return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if newPhone {
+		// phone never requested certify code is stored uncertified, only if phone certification is not required
+		switch err = au.parentPhoneCertifyRepository.Store(_tx, &domain.ParentPhoneCertify{
+			ParentUUID:  pi.UUID,
+			PhoneNumber: domain.String(pn),
+		}); err.(type) {
+		case nil:
+			break
+		case domain.ErrEntryDuplicate:
+			err = errors.New("this phone number is already in use")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyInUse}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		default:
+			err = errors.Wrap(err, "phone Store return unexpected error")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	} else if requireCert || pn != "" {
+		ppc.ParentUUID = domain.String(domain.StringValue(pi.UUID))
+		if err = au.parentPhoneCertifyRepository.Update(_tx, &ppc); err != nil {
+			err = errors.Wrap(err, "phone Update return unexpected error")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	}
+
+	if profile != nil && string(profile) != "" {
+		if _, err = au.s3Agency.PutObject(&s3.PutObjectInput{
+			Bucket: aws.String(au.myCfg.ParentProfileS3Bucket()),
+			Key:    aws.String(pi.ParentAuth.GenerateProfileUri()),
+			Body:   bytes.NewReader(profile),
+			ACL:    aws.String("public-read"),
+		}); err != nil {
+			err = errors.Wrap(err, "s3 PutObject return unexpected error")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	}
+
+	uuid = domain.StringValue(pi.UUID)
+	err = nil
+	_ = au.txHandler.Commit(_tx)
+	au.recordAuthEvent(ctx, domain.AuthEventSignUp)
+	au.countAccountForPhone(pn)
+
+	if au.myCfg.WelcomeMessage() {
+		go au.parentSignedUp(uuid, pn, domain.StringValue(pi.Name))
+	}
+	return
+}
+
+// uncertifiedPhoneError method return domain.UsecaseError about sign up with uncertified phone of ppc (nil if not exist)
+// state of certify code in ppc is set in Detail of error if UncertifiedPhoneDetail, so that client know what to do next
+func (au *authUsecase) uncertifiedPhoneError(ppc *domain.ParentPhoneCertify) error {
+	err := domain.UsecaseError{
+		UsecaseErr: errors.New("this phone number is not certified"),
+		Status:     http.StatusConflict,
+		Code:       domain.UncertifiedPhone,
+	}
+	if !au.myCfg.UncertifiedPhoneDetail() {
+		return err
+	}
+
+	// row of unlinked phone is stored only by sending certify code, and deleted by restarting certification
+	detail := domain.UncertifiedPhoneDetail{NextAction: domain.SignUpNextActionSendCode}
+	if ppc != nil {
+		detail.CodeSent = true
+		detail.CodeActive = domain.StringValue(ppc.CertifyCode) != ""
+	}
+	if detail.CodeActive {
+		detail.NextAction = domain.SignUpNextActionVerifyCode
+	}
+	err.Detail = detail
+	return err
+}
+
+// hashResult is result of hash generated by generateHashAsync
+type hashResult struct {
+	hash string
+	err  error
+}
+
+// generateHashAsync method generate hash of pw with min salt in another goroutine & return channel receiving result
+// channel is buffered, so that goroutine isn't blocked if caller return without receiving (ex, rejected by other check)
+func (au *authUsecase) generateHashAsync(pw string) <-chan hashResult {
+	hashed := make(chan hashResult, 1)
+	go func() {
+		hash, err := au.hashHandler.GenerateHashWithMinSalt(pw)
+		hashed <- hashResult{hash: hash, err: err}
+	}()
+	return hashed
+}
+
+// LoginParentAuth implement LoginParentAuth method of domain.AuthUsecase interface
+func (au *authUsecase) LoginParentAuth(ctx context.Context, id, pw string, device domain.DeviceInfo) (uuid, token, verificationID string, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	pa, err := au.parentAuthRepository.GetByID(_tx, domain.NormalizeParentID(id))
+	switch err.(type) {
+	case nil:
+		if pa.IsLocked(au.clock.Now()) {
+			err = errors.New("this account is locked by too many failed login, retry later")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.AccountLocked}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+
+		switch err = au.hashHandler.CompareHashAndPW(domain.StringValue(pa.PW), pw); err.(type) {
+		case nil:
+			break
+		case interface{ NeedRehash() }:
+			// hash generated before setting pepper, so rehash with pepper (fail of rehash don't fail login)
+			if hash, err := au.hashHandler.GenerateHashWithMinSalt(pw); err == nil {
+				_ = au.parentAuthRepository.Update(_tx, &domain.ParentAuth{UUID: pa.UUID, PW: domain.String(hash)})
+			}
+		case interface{ Mismatch() }:
+			locked, notify, fErr := au.recordParentLoginFailure(_tx, pa.ParentAuth)
+			if fErr != nil {
+				err = domain.UsecaseError{UsecaseErr: fErr, Status: http.StatusInternalServerError}
+				_ = au.txHandler.Rollback(_tx)
+				return
+			}
+			// failure count must be committed, even though login is failed
+			_ = au.txHandler.Commit(_tx)
+
+			if notify && domain.BoolValue(pa.Certified) {
+				go au.parentAccountLocked(domain.StringValue(pa.PhoneNumber), device)
+			}
+			if locked {
+				err = errors.New("incorrect password, and account is locked by too many failed login")
+				err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.AccountLocked}
+				return
+			}
+			err = errors.New("incorrect password")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.IncorrectParentPW}
+			return
+		default:
+			err = errors.Wrap(err, "CompareHashAndPW return unexpected error")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent ID")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.NotExistParentID}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "GetByID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// failed login count is kept until device is verified, so that guessing certify code is also locked out
+	if pn := domain.StringValue(pa.PhoneNumber); au.myCfg.NewDeviceVerification() && pn != "" && domain.BoolValue(pa.Certified) {
+		if verificationID, err = au.beginDeviceVerification(_tx, pa.ParentAuth, pn, device); err != nil {
+			_ = au.txHandler.Rollback(_tx)
+			return "", "", "", err
+		}
+		if verificationID != "" {
+			_ = au.txHandler.Commit(_tx)
+			return domain.StringValue(pa.UUID), "", verificationID, nil
+		}
+	}
+
+	if domain.IntValue(pa.FailedLoginCount) > 0 {
+		if err = au.parentAuthRepository.Update(_tx, &domain.ParentAuth{UUID: pa.UUID, FailedLoginCount: domain.Int(0)}); err != nil {
+			err = errors.Wrap(err, "failed to reset failed login count")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	}
+
+	token, newDevice, evicted, err := au.completeParentLogin(_tx, pa.ParentAuth, device)
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return "", "", "", err
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	au.recordAuthEvent(ctx, domain.AuthEventLogin)
+	if newDevice && au.myCfg.NewDeviceLoginNotification() {
+		go au.parentLoggedInFromNewDevice(domain.StringValue(pa.PhoneNumber), device)
+	}
+	if len(evicted) > 0 && au.myCfg.SessionEvictionNotification() {
+		go au.parentSessionsEvicted(domain.StringValue(pa.PhoneNumber), evicted)
+	}
+	return domain.StringValue(pa.UUID), token, "", nil
+}
+
+// completeParentLogin check if parent account is available, record session of device & issue access token
+// it is called after parent is authenticated with any method (password, passkey), so that every login go through
+// same session & token machinery. err is domain.UsecaseError, and transaction must be rolled back by caller on err
+func (au *authUsecase) completeParentLogin(_tx tx.Context, pa domain.ParentAuth, device domain.DeviceInfo) (token string, newDevice bool, evicted []domain.ParentSession, err error) {
+	if pa.IsSuspended(au.clock.Now()) {
+		err = errors.Errorf("this account is suspended, reason: %s", domain.StringValue(pa.SuspendReason))
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.AccountSuspended}
+		return
+	}
+
+	uuid := domain.StringValue(pa.UUID)
+	switch newDevice, evicted, err = au.recordParentSession(_tx, uuid, device); err.(type) {
+	case nil:
+		break
+	case domain.UsecaseError:
+		return "", false, nil, err
+	default:
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return "", false, nil, err
+	}
+
+	switch token, err = au.jwtHandler.GenerateUUIDJWT(uuid, "access_token", au.myCfg.AccessTokenDuration()); err.(type) {
+	case nil:
+		break
+	case interface{ SigningFailed() }:
+		err = errors.Wrap(err, "failed to issue access token")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError, Code: domain.TokenIssueFailed}
+		return "", false, nil, err
+	default:
+		err = errors.Wrap(err, "GenerateUUIDJWT return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return "", false, nil, err
+	}
+	return
+}
+
+// recordParentLoginFailure increase failed login count of parent, and lock account if count reach lockout threshold
+// return locked true if account is locked by this failure, and notify true if owner should be notified about it
+// (notification is limited to once in LockoutNotificationInterval, so that repeated lockout doesn't spam)
+func (au *authUsecase) recordParentLoginFailure(_tx tx.Context, pa domain.ParentAuth) (locked, notify bool, err error) {
+	threshold := au.myCfg.LoginLockoutThreshold()
+	if threshold <= 0 {
+		return
+	}
+
+	now := au.clock.Now()
+	update := &domain.ParentAuth{UUID: pa.UUID, FailedLoginCount: domain.Int(domain.IntValue(pa.FailedLoginCount) + 1)}
+	if locked = *update.FailedLoginCount >= threshold; locked {
+		update.FailedLoginCount = domain.Int(0)
+		update.LockedUntil = domain.Time(now.Add(au.myCfg.LoginLockoutDuration()))
+
+		notified := pa.LockoutNotifiedAt
+		if notify = au.myCfg.LockoutNotification() &&
+			(notified == nil || !now.Before(notified.Add(au.myCfg.LockoutNotificationInterval()))); notify {
+			update.LockoutNotifiedAt = &now
+		}
+		log.Printf("parent account is locked by too many failed login, uuid: %s", domain.StringValue(pa.UUID))
+	}
+
+	if err = au.parentAuthRepository.Update(_tx, update); err != nil {
+		err = errors.Wrap(err, "failed to update failed login count")
+		return false, false, err
+	}
+	return
+}
+
+// parentAccountLocked handle event that parent account is locked by notifying suspicious activity to linked phone
+// it is called after lockout was committed, so failure of notification is only logged
+func (au *authUsecase) parentAccountLocked(pn string, device domain.DeviceInfo) {
+	if pn == "" {
+		return
+	}
+
+	content := "[육아는 처음이지] 로그인 실패가 반복되어 계정이 일시적으로 잠겼습니다. 본인이 아니라면 비밀번호를 변경해 주세요."
+	if device.Platform != "" {
+		content += " (" + device.Platform + ")"
+	}
+	if _, err := au.messageAgency.SendSMSToOne(pn, content); err != nil {
+		log.Printf("failed to send lockout notification to %s, err: %v", domain.MaskPhoneNumber(pn), err)
+	}
+}
+
+// recordParentSession store session of parent with device inform, or update last used time if device is known
+// device without device id is known by user agent & platform, so that each login of it doesn't store new session
+// (otherwise client not sending device id is locked out by its own sessions with reject policy)
+// return newDevice true if parent logged in from device never used before (always false if device id is empty)
+// session limit is enforced if session becomes active by login, and evicted is session evicted for it
+// err is domain.UsecaseError if login is rejected by session limit
+func (au *authUsecase) recordParentSession(_tx tx.Context, parentUUID string, device domain.DeviceInfo) (newDevice bool, evicted []domain.ParentSession, err error) {
+	now := au.clock.Now()
+	var ps domain.ParentSession
+	if device.DeviceID != "" {
+		ps, err = au.parentSessionRepository.GetByParentUUIDAndDeviceID(_tx, parentUUID, device.DeviceID)
+	} else {
+		ps, err = au.parentSessionRepository.GetDevicelessByParentUUID(_tx, parentUUID, device.UserAgent, device.Platform)
+	}
+	switch err.(type) {
+	case nil:
+		// session of known device not used recently is not counted as active, so it is limited like new one
+		if ps.LastUsedAt == nil || ps.LastUsedAt.Before(au.activeSessionSince()) {
+			if evicted, err = au.enforceSessionLimit(_tx, parentUUID); err != nil {
+				return false, nil, err
+			}
+		}
+		err = au.parentSessionRepository.Update(_tx, &domain.ParentSession{
+			UUID:       ps.UUID,
+			UserAgent:  domain.String(device.UserAgent),
+			Platform:   domain.String(device.Platform),
+			LastUsedAt: &now,
+		})
+		if err != nil {
+			err = errors.Wrap(err, "failed to update parent session")
+		}
+		return false, evicted, err
+	case domain.ErrRowNotExist:
+		newDevice = device.DeviceID != ""
+	default:
+		return false, nil, errors.Wrap(err, "get session of device return unexpected error")
+	}
+
+	if evicted, err = au.enforceSessionLimit(_tx, parentUUID); err != nil {
+		return false, nil, err
+	}
+	if err = au.parentSessionRepository.Store(_tx, &domain.ParentSession{
+		ParentUUID: domain.String(parentUUID),
+		DeviceID:   domain.String(device.DeviceID),
+		UserAgent:  domain.String(device.UserAgent),
+		Platform:   domain.String(device.Platform),
+		CreatedAt:  &now,
+		LastUsedAt: &now,
+	}); err != nil {
+		return false, nil, errors.Wrap(err, "failed to store parent session")
+	}
+	return
+}
+
+// parentSignedUp handle event that parent signed up by sending welcome message to phone of parent
+// it is called after sign up was committed, so failure of message is only logged & counted
+// welcome message is non-essential, so it is not sent if parent opted out of it
+func (au *authUsecase) parentSignedUp(uuid, pn, name string) {
+	if pn == "" || !au.allowNonEssentialMessage(context.Background(), uuid) {
+		return
+	}
+
+	content := strings.ReplaceAll(au.myCfg.WelcomeMessageTemplate(), parentNamePlaceholder, name)
+	if _, err := au.messageAgency.SendSMSToOne(pn, content); err != nil {
+		welcomeMessageFailedMetric.Add(1)
+		log.Printf("failed to send welcome message to %s, err: %v", domain.MaskPhoneNumber(pn), err)
+	}
+}
+
+// parentLoggedInFromNewDevice handle event that parent logged in from new device by notifying to linked phone
+// it is called after login was committed, so failure of notification is only logged
+func (au *authUsecase) parentLoggedInFromNewDevice(pn string, device domain.DeviceInfo) {
+	if pn == "" {
+		return
+	}
+
+	content := "[육아는 처음이지] 새로운 기기에서 로그인되었습니다."
+	if device.Platform != "" {
+		content += " (" + device.Platform + ")"
+	}
+	if _, err := au.messageAgency.SendSMSToOne(pn, content); err != nil {
+		log.Printf("failed to send new device login notification to %s, err: %v", domain.MaskPhoneNumber(pn), err)
+	}
+}
+
+// GetParentInformByID implement GetParentInformByID method of domain.AuthUsecase interface
+func (au *authUsecase) GetParentInformByID(ctx context.Context, id string) (pi struct {
+	domain.ParentAuth
+	domain.ParentPhoneCertify
+}, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	pi, err = au.parentAuthRepository.GetByID(_tx, domain.NormalizeParentID(id))
+	switch err.(type) {
+	case nil:
+	case domain.ErrRowNotExist:
+		err = domain.UsecaseError{UsecaseErr: errors.New("not exist parent auth with that ID"), Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	return pi, err
+}
+
+// GetParentInformByUUID implement GetParentInformByUUID method of domain.AuthUsecase interface
+func (au *authUsecase) GetParentInformByUUID(ctx context.Context, uuid string) (pi struct {
+	domain.ParentAuth
+	domain.ParentPhoneCertify
+}, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	pi, err = au.parentAuthRepository.GetByUUID(_tx, uuid)
+	switch err.(type) {
+	case nil:
+	case domain.ErrRowNotExist:
+		err = domain.UsecaseError{UsecaseErr: errors.New("not exist parent auth with that uuid"), Status: http.StatusNotFound}
+	default:
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+
+	// nothing is changed, so rollback rather than commit
+	_ = au.txHandler.Rollback(_tx)
+	return
+}
+
+// UpdateParentInform implement UpdateParentInform method of domain.AuthUsecase interface
+func (au *authUsecase) UpdateParentInform(ctx context.Context, uuid string, pa *domain.ParentAuth, profile []byte) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+	pa.UUID = domain.String(uuid)
+	if pa.Name != nil {
+		pa.Name = domain.String(domain.NormalizeParentName(domain.StringValue(pa.Name)))
+	}
+
+	if profile != nil && len(profile) != 0 {
+		pa.ProfileUri = domain.String(pa.GenerateProfileUri())
+	}
+
+	if err = au.parentAuthRepository.Update(_tx, pa); err != nil {
+		err = errors.Wrap(err, "failed to Update")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if profile != nil && len(profile) != 0 {
+		if _, err = au.s3Agency.PutObject(&s3.PutObjectInput{
+			Bucket: aws.String(au.myCfg.ParentProfileS3Bucket()),
+			Key:    aws.String(domain.StringValue(pa.ProfileUri)),
+			Body:   bytes.NewReader(profile),
+			ACL:    aws.String("public-read"),
+		}); err != nil {
+			err = errors.Wrap(err, "s3 PutObject return unexpected error")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	return nil
+}
+
+// ChangeParentID implement ChangeParentID method of domain.AuthUsecase interface
+func (au *authUsecase) ChangeParentID(ctx context.Context, uuid, newID, pw string) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+	newID = domain.NormalizeParentID(newID)
+
+	pa, err := au.parentAuthRepository.GetByUUID(_tx, uuid)
+	switch err.(type) {
+	case nil:
+		break
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent auth with that uuid")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	switch err = au.hashHandler.CompareHashAndPW(domain.StringValue(pa.PW), pw); err.(type) {
+	case nil, interface{ NeedRehash() }:
+		break
+	case interface{ Mismatch() }:
+		err = errors.New("incorrect password")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.IncorrectParentPW}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "CompareHashAndPW return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	now := au.clock.Now()
+	if interval := au.myCfg.ParentIDChangeInterval(); interval > 0 && pa.IDChangedAt != nil {
+		if now.Before(pa.IDChangedAt.Add(interval)) {
+			err = errors.Errorf("parent ID can be changed once every %s", interval)
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentIDChangeTooSoon}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	}
+
+	switch exist, err := au.parentAuthRepository.ExistsByID(_tx, newID); {
+	case err != nil:
+		err = errors.Wrap(err, "ExistsByID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return err
+	case exist:
+		err = errors.New("this parent ID is already in use")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentIDAlreadyInUse}
+		_ = au.txHandler.Rollback(_tx)
+		return err
+	}
+
+	switch err = au.parentAuthRepository.Update(_tx, &domain.ParentAuth{
+		UUID:        domain.String(uuid),
+		ID:          domain.String(newID),
+		IDChangedAt: domain.Time(now),
+	}); err.(type) {
+	case nil:
+		break
+	case domain.ErrEntryDuplicate:
+		// ID can be taken by another parent between ExistsByID and Update
+		err = errors.New("this parent ID is already in use")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentIDAlreadyInUse}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "failed to Update")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	log.Printf("parent ID is changed, uuid: %s, before: %s, after: %s", uuid, domain.StringValue(pa.ID), newID)
+	_ = au.txHandler.Commit(_tx)
+	return nil
+}
+
+// ChangeParentPW implement ChangeParentPW method of domain.AuthUsecase interface
+func (au *authUsecase) ChangeParentPW(ctx context.Context, uuid, pw, newPW string) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	pa, err := au.parentAuthRepository.GetByUUID(_tx, uuid)
+	switch err.(type) {
+	case nil:
+		break
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent auth with that uuid")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	switch err = au.hashHandler.CompareHashAndPW(domain.StringValue(pa.PW), pw); err.(type) {
+	case nil, interface{ NeedRehash() }:
+		break
+	case interface{ Mismatch() }:
+		err = errors.New("incorrect password")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.IncorrectParentPW}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "CompareHashAndPW return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if err = au.checkPasswordBreached(newPW); err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	hash, err := au.hashHandler.GenerateHashWithMinSalt(newPW)
+	if err != nil {
+		err = errors.Wrap(err, "failed to GenerateHashWithMinSalt")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if err = au.parentAuthRepository.Update(_tx, &domain.ParentAuth{
+		UUID:        domain.String(uuid),
+		PW:          domain.String(hash),
+		PWChangedAt: domain.Time(au.clock.Now()),
+	}); err != nil {
+		err = errors.Wrap(err, "failed to Update")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	// token issued before is revoked by PWChangedAt, which must be applied without waiting for cache to expire
+	au.invalidateAccountStatus(uuid)
+	return nil
+}
+
+// VerifyParentPassword implement VerifyParentPassword method of domain.AuthUsecase interface
+// mismatch is counted in kvStore rather than in failed login count, so that it never lock account out
+func (au *authUsecase) VerifyParentPassword(ctx context.Context, uuid, pw string) (matched bool, err error) {
+	limit, key := au.myCfg.PasswordVerifyAttemptLimit(), "auth:password_verify_mismatch:"+uuid
+	if limit > 0 {
+		switch count, exist, gErr := au.kvStore.Get(key); {
+		case gErr != nil:
+			log.Printf("failed to get count of password mismatch in verification, so it is allowed, err: %v", gErr)
+		case exist:
+			if n, _ := strconv.Atoi(count); n >= limit {
+				err = errors.New("too many password mismatch in verification, retry later")
+				err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusTooManyRequests, Code: domain.TooManyPasswordAttempts}
+				return
+			}
+		}
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	pa, err := au.parentAuthRepository.GetByUUID(_tx, uuid)
+	// nothing is changed, so rollback rather than commit
+	_ = au.txHandler.Rollback(_tx)
+	switch err.(type) {
+	case nil:
+		break
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent auth with that uuid")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		return
+	default:
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return
+	}
+
+	// hash needing rehash is not rehashed here, since verification must not change parent
+	switch err = au.hashHandler.CompareHashAndPW(domain.StringValue(pa.PW), pw); err.(type) {
+	case nil, interface{ NeedRehash() }:
+		return true, nil
+	case interface{ Mismatch() }:
+		if limit > 0 {
+			if _, iErr := au.kvStore.Incr(key, au.myCfg.PasswordVerifyAttemptWindow()); iErr != nil {
+				log.Printf("failed to count password mismatch in verification, err: %v", iErr)
+			}
+		}
+		return false, nil
+	default:
+		err = errors.Wrap(err, "CompareHashAndPW return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return
 	}
-
-	uuid = domain.StringValue(pi.UUID)
-	err = nil
-	_ = au.txHandler.Commit(_tx)
-	return
 }
 
-// LoginParentAuth implement LoginParentAuth method of domain.AuthUsecase interface
-func (au *authUsecase) LoginParentAuth(ctx context.Context, id, pw string) (uuid, token string, err error) {
-	_tx, err := au.txHandler.BeginTx(ctx, nil)
+// GetPhoneCertifyStatuses implement GetPhoneCertifyStatuses method of domain.AuthUsecase interface
+func (au *authUsecase) GetPhoneCertifyStatuses(ctx context.Context, pns []string) (statuses []domain.PhoneCertifyStatus, err error) {
+	if max := au.myCfg.PhoneStatusBatchSize(); len(pns) > max {
+		err = errors.Errorf("phone number can be looked up at most %d at once", max)
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusBadRequest, Code: domain.TooManyPhoneNumbers}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
 	if err != nil {
 		err = errors.Wrap(err, "failed to begin transaction")
 		return
 	}
 
-	pa, err := au.parentAuthRepository.GetByID(_tx, id)
-	switch err.(type) {
-	case nil:
-		switch err = au.hashHandler.CompareHashAndPW(domain.StringValue(pa.PW), pw); err.(type) {
+	ppcs, err := au.parentPhoneCertifyRepository.GetByPhoneNumbers(_tx, pns)
+	if err != nil {
+		err = errors.Wrap(err, "GetByPhoneNumbers return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	_ = au.txHandler.Commit(_tx)
+
+	found := make(map[string]domain.ParentPhoneCertify, len(ppcs))
+	for _, ppc := range ppcs {
+		found[domain.StringValue(ppc.PhoneNumber)] = ppc
+	}
+
+	masked := make([]string, len(pns))
+	statuses = make([]domain.PhoneCertifyStatus, len(pns))
+	for i, pn := range pns {
+		ppc, exist := found[pn]
+		statuses[i] = domain.PhoneCertifyStatus{
+			PhoneNumber: pn,
+			Exist:       exist,
+			Certified:   domain.BoolValue(ppc.Certified),
+			InUse:       domain.StringValue(ppc.ParentUUID) != "",
+		}
+		masked[i] = domain.MaskPhoneNumber(pn)
+	}
+	log.Printf("phone certify status is looked up, phone numbers: %v", masked)
+	return
+}
+
+// CheckParentAccount implement CheckParentAccount method of domain.AuthUsecase interface
+// it is called in every authenticated request, so status of account is cached in kv store for AccountStatusCacheTTL
+func (au *authUsecase) CheckParentAccount(ctx context.Context, uuid string, iat time.Time) (status domain.ParentAccountStatus, err error) {
+	as, ok := au.cachedAccountStatus(uuid)
+	if !ok {
+		_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+		if err != nil {
+			err = errors.Wrap(err, "failed to begin transaction")
+			return status, err
+		}
+
+		pa, err := au.parentAuthRepository.GetByUUID(_tx, uuid)
+		switch err.(type) {
 		case nil:
 			break
-		case interface{ Mismatch() }:
-			err = errors.New("incorrect password")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.IncorrectParentPW}
+		case domain.ErrRowNotExist:
+			err = errors.New("not exist parent auth with that uuid")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusUnauthorized}
 			_ = au.txHandler.Rollback(_tx)
-			return
+			return status, err
 		default:
-			err = errors.Wrap(err, "CompareHashAndPW return unexpected error")
+			err = errors.Wrap(err, "GetByUUID return unexpected error")
 			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
 			_ = au.txHandler.Rollback(_tx)
-			return
+			return status, err
 		}
+		// nothing is changed, so rollback rather than commit
+		_ = au.txHandler.Rollback(_tx)
+
+		as = newAccountStatus(pa.ParentAuth, pa.ParentPhoneCertify)
+		au.cacheAccountStatus(uuid, as)
+	}
+
+	pa := as.parentAuth()
+	if pa.IsSuspended(au.clock.Now()) {
+		err = errors.Errorf("this account is suspended, reason: %s", as.SuspendReason)
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusForbidden, Code: domain.AccountSuspended}
+		return
+	}
+	if pa.IsTokenRevoked(iat) {
+		err = errors.New("token is issued before password change")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusUnauthorized}
+		return
+	}
+
+	status = domain.ParentAccountStatus{Role: as.Role, PhoneCertified: as.PhoneCertified}
+	return
+}
+
+// SuspendParent implement SuspendParent method of domain.AuthUsecase interface
+func (au *authUsecase) SuspendParent(ctx context.Context, uuid, reason string, until time.Time) (err error) {
+	return au.updateParentSuspension(ctx, &domain.ParentAuth{
+		UUID:           domain.String(uuid),
+		Suspended:      domain.Bool(true),
+		SuspendReason:  domain.String(reason),
+		SuspendedUntil: domain.Time(until),
+	})
+}
+
+// UnsuspendParent implement UnsuspendParent method of domain.AuthUsecase interface
+func (au *authUsecase) UnsuspendParent(ctx context.Context, uuid string) (err error) {
+	return au.updateParentSuspension(ctx, &domain.ParentAuth{
+		UUID:           domain.String(uuid),
+		Suspended:      domain.Bool(false),
+		SuspendReason:  domain.String(""),
+		SuspendedUntil: domain.Time(time.Time{}),
+	})
+}
+
+// updateParentSuspension method update suspension field of parent auth after checking if parent auth exist
+func (au *authUsecase) updateParentSuspension(ctx context.Context, pa *domain.ParentAuth) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	switch _, err = au.parentAuthRepository.GetByUUID(_tx, domain.StringValue(pa.UUID)); err.(type) {
+	case nil:
+		break
 	case domain.ErrRowNotExist:
-		err = errors.New("not exist parent ID")
-		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.NotExistParentID}
+		err = errors.New("not exist parent auth with that uuid")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
 		_ = au.txHandler.Rollback(_tx)
 		return
 	default:
-		err = errors.Wrap(err, "GetByID return unexpected error")
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
 		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
 		_ = au.txHandler.Rollback(_tx)
 		return
 	}
 
-	uuid = domain.StringValue(pa.UUID)
-	token, err = au.jwtHandler.GenerateUUIDJWT(uuid, "access_token", au.myCfg.AccessTokenDuration())
-	err = nil
+	if err = au.parentAuthRepository.Update(_tx, pa); err != nil {
+		err = errors.Wrap(err, "failed to Update")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
 
 	_ = au.txHandler.Commit(_tx)
-	return
+	au.invalidateAccountStatus(domain.StringValue(pa.UUID))
+	return nil
 }
 
-// GetParentInformByID implement GetParentInformByID method of domain.AuthUsecase interface
-func (au *authUsecase) GetParentInformByID(ctx context.Context, id string) (pi struct {
-	domain.ParentAuth
-	domain.ParentPhoneCertify
-}, err error) {
-	_tx, err := au.txHandler.BeginTx(ctx, nil)
+// ForceCertifyPhone implement ForceCertifyPhone method of domain.AuthUsecase interface
+func (au *authUsecase) ForceCertifyPhone(ctx context.Context, adminUUID, pn string) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
 	if err != nil {
 		err = errors.Wrap(err, "failed to begin transaction")
 		return
 	}
 
-	pi, err = au.parentAuthRepository.GetByID(_tx, id)
-	switch err.(type) {
+	switch ppc, err := au.parentPhoneCertifyRepository.GetByPhoneNumber(_tx, pn); err.(type) {
 	case nil:
+		if domain.BoolValue(ppc.Certified) {
+			err = errors.New("this phone number is already certified")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PhoneAlreadyCertified}
+			_ = au.txHandler.Rollback(_tx)
+			return err
+		}
 	case domain.ErrRowNotExist:
-		err = domain.UsecaseError{UsecaseErr: errors.New("not exist parent auth with that ID"), Status: http.StatusNotFound}
+		err = errors.New("not exist phone number, certify code must be requested before")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return err
+	default:
+		err = errors.Wrap(err, "GetByPhoneNumber return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return err
+	}
+
+	if err = au.parentPhoneCertifyRepository.Update(_tx, &domain.ParentPhoneCertify{
+		PhoneNumber: domain.String(pn),
+		Certified:   domain.Bool(true),
+	}); err != nil {
+		err = errors.Wrap(err, "phone Update return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	now := au.clock.Now()
+	if err = au.auditLogRepository.Store(_tx, &domain.AuditLog{
+		ActorUUID: domain.String(adminUUID),
+		Action:    domain.String(domain.AuditActionForceCertifyPhone),
+		Target:    domain.String(au.phoneNumberKey(domain.AuditTargetPhoneNumberPrefix, pn)),
+		CreatedAt: &now,
+	}); err != nil {
+		// force certify is not allowed without audit log, so rollback
+		err = errors.Wrap(err, "audit log Store return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
 		_ = au.txHandler.Rollback(_tx)
+		return
 	}
 
 	_ = au.txHandler.Commit(_tx)
-	return pi, err
+	log.Printf("phone %s is force certified by admin %s", domain.MaskPhoneNumber(pn), adminUUID)
+	return
 }
 
-// UpdateParentInform implement UpdateParentInform method of domain.AuthUsecase interface
-func (au *authUsecase) UpdateParentInform(ctx context.Context, uuid string, pa *domain.ParentAuth, profile []byte) (err error) {
-	_tx, err := au.txHandler.BeginTx(ctx, nil)
+// UnlockParentAccount implement UnlockParentAccount method of domain.AuthUsecase interface
+func (au *authUsecase) UnlockParentAccount(ctx context.Context, adminUUID, uuid string) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
 	if err != nil {
 		err = errors.Wrap(err, "failed to begin transaction")
 		return
 	}
-	pa.UUID = domain.String(uuid)
 
-	if profile != nil && len(profile) != 0 {
-		pa.ProfileUri = domain.String(pa.GenerateProfileUri())
+	switch _, err = au.parentAuthRepository.GetByUUID(_tx, uuid); err.(type) {
+	case nil:
+		break
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent auth with that uuid")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
 	}
 
-	if err = au.parentAuthRepository.Update(_tx, pa); err != nil {
+	if err = au.parentAuthRepository.Update(_tx, &domain.ParentAuth{
+		UUID:             domain.String(uuid),
+		FailedLoginCount: domain.Int(0),
+		LockedUntil:      domain.Time(time.Time{}),
+	}); err != nil {
 		err = errors.Wrap(err, "failed to Update")
 		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
 		_ = au.txHandler.Rollback(_tx)
 		return
 	}
 
-	if profile != nil && len(profile) != 0 {
-		if _, err = au.s3Agency.PutObject(&s3.PutObjectInput{
-			Bucket: aws.String(au.myCfg.ParentProfileS3Bucket()),
-			Key:    aws.String(domain.StringValue(pa.ProfileUri)),
-			Body:   bytes.NewReader(profile),
-			ACL:    aws.String("public-read"),
-		}); err != nil {
-			err = errors.Wrap(err, "s3 PutObject return unexpected error")
-			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
-			_ = au.txHandler.Rollback(_tx)
-			return
+	now := au.clock.Now()
+	if err = au.auditLogRepository.Store(_tx, &domain.AuditLog{
+		ActorUUID: domain.String(adminUUID),
+		Action:    domain.String(domain.AuditActionUnlockParent),
+		Target:    domain.String(uuid),
+		CreatedAt: &now,
+	}); err != nil {
+		// unlock is not allowed without audit log, so rollback
+		err = errors.Wrap(err, "audit log Store return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	log.Printf("parent %s is unlocked by admin %s", uuid, adminUUID)
+	return
+}
+
+// ListParents implement ListParents method of domain.AuthUsecase interface
+func (au *authUsecase) ListParents(ctx context.Context, cursor string, limit int) (parents []domain.ParentAuth, next, prev string, err error) {
+	c, err := domain.DecodeCursor(cursor)
+	if err != nil {
+		err = domain.UsecaseError{UsecaseErr: errors.Wrap(err, "invalid cursor"), Status: http.StatusBadRequest}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	pas, err := au.parentAuthRepository.ListAfter(_tx, c, limit)
+	if err != nil {
+		err = errors.Wrap(err, "ListAfter return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	_ = au.txHandler.Commit(_tx)
+
+	keys := make([]string, len(pas))
+	for i, pa := range pas {
+		keys[i] = domain.StringValue(pa.UUID)
+	}
+	from, to, next, prev := domain.Paginate(c, keys, limit)
+	return pas[from:to], next, prev, nil
+}
+
+// ListParentSessions implement ListParentSessions method of domain.AuthUsecase interface
+func (au *authUsecase) ListParentSessions(ctx context.Context, uuid, cursor string, limit int) (sessions []domain.ParentSession, next, prev string, err error) {
+	c, err := domain.DecodeCursor(cursor)
+	if err != nil {
+		err = domain.UsecaseError{UsecaseErr: errors.Wrap(err, "invalid cursor"), Status: http.StatusBadRequest}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	pss, err := au.parentSessionRepository.ListAfter(_tx, uuid, c, limit)
+	if err != nil {
+		err = errors.Wrap(err, "ListAfter return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	_ = au.txHandler.Commit(_tx)
+
+	keys := make([]string, len(pss))
+	for i, ps := range pss {
+		keys[i] = domain.StringValue(ps.UUID)
+	}
+	from, to, next, prev := domain.Paginate(c, keys, limit)
+	return pss[from:to], next, prev, nil
+}
+
+// CountActiveSessions implement CountActiveSessions method of domain.AuthUsecase interface
+// session is regarded as active if it is used in last access token duration, since token issued before is expired
+func (au *authUsecase) CountActiveSessions(ctx context.Context, uuid string) (count int, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	if count, err = au.parentSessionRepository.CountUsedSince(_tx, uuid, au.activeSessionSince()); err != nil {
+		err = errors.Wrap(err, "CountUsedSince return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// nothing is changed, so rollback rather than commit
+	_ = au.txHandler.Rollback(_tx)
+	return
+}
+
+// ExportParentData implement ExportParentData method of domain.AuthUsecase interface
+func (au *authUsecase) ExportParentData(ctx context.Context, uuid string) (export domain.ParentDataExport, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	pi, err := au.parentAuthRepository.GetByUUID(_tx, uuid)
+	switch err.(type) {
+	case nil:
+		break
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent auth with that uuid")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	targets := []string{uuid}
+	if pn := domain.StringValue(pi.PhoneNumber); pn != "" {
+		targets = append(targets, au.phoneNumberKey(domain.AuditTargetPhoneNumberPrefix, pn))
+	}
+	als, err := au.auditLogRepository.GetByActorUUIDOrTarget(_tx, uuid, targets)
+	if err != nil {
+		err = errors.Wrap(err, "GetByActorUUIDOrTarget return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// last export time is found from audit log, since every export is recorded in it
+	now := au.clock.Now()
+	if interval := au.myCfg.ParentDataExportInterval(); interval > 0 {
+		for _, al := range als {
+			if domain.StringValue(al.Action) != domain.AuditActionExportParentData || domain.StringValue(al.ActorUUID) != uuid {
+				continue
+			}
+			if al.CreatedAt != nil && now.Before(al.CreatedAt.Add(interval)) {
+				err = errors.Errorf("parent data can be exported once every %s", interval)
+				err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusTooManyRequests, Code: domain.DataExportTooSoon}
+				_ = au.txHandler.Rollback(_tx)
+				return
+			}
 		}
 	}
 
+	pss, err := au.parentSessionRepository.GetByParentUUID(_tx, uuid)
+	if err != nil {
+		err = errors.Wrap(err, "GetByParentUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if err = au.auditLogRepository.Store(_tx, &domain.AuditLog{
+		ActorUUID: domain.String(uuid),
+		Action:    domain.String(domain.AuditActionExportParentData),
+		Target:    domain.String(uuid),
+		CreatedAt: &now,
+	}); err != nil {
+		err = errors.Wrap(err, "audit log Store return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
 	_ = au.txHandler.Commit(_tx)
-	return nil
+
+	export = domain.ParentDataExport{
+		ExportedAt: domain.Timestamp(now),
+		Parent: domain.ParentAuthExport{
+			UUID:           domain.StringValue(pi.UUID),
+			ID:             domain.StringValue(pi.ID),
+			Name:           domain.StringValue(pi.Name),
+			ProfileUri:     domain.StringValue(pi.ProfileUri),
+			Role:           domain.StringValue(pi.Role),
+			Suspended:      domain.BoolValue(pi.Suspended),
+			SuspendReason:  domain.StringValue(pi.SuspendReason),
+			SuspendedUntil: domain.TimestampOf(pi.SuspendedUntil),
+			IDChangedAt:    domain.TimestampOf(pi.IDChangedAt),
+			PWChangedAt:    domain.TimestampOf(pi.PWChangedAt),
+		},
+		PhoneCertifications: []domain.ParentPhoneCertifyExport{},
+		Sessions:            make([]domain.ParentSessionExport, 0, len(pss)),
+		AuditLogs:           make([]domain.AuditLogExport, 0, len(als)+1),
+	}
+	if pn := domain.StringValue(pi.PhoneNumber); pn != "" {
+		export.PhoneCertifications = append(export.PhoneCertifications, domain.ParentPhoneCertifyExport{
+			PhoneNumber: pn,
+			Certified:   domain.BoolValue(pi.Certified),
+		})
+	}
+	for _, ps := range pss {
+		export.Sessions = append(export.Sessions, domain.ParentSessionExport{
+			UUID:       domain.StringValue(ps.UUID),
+			DeviceID:   domain.StringValue(ps.DeviceID),
+			UserAgent:  domain.StringValue(ps.UserAgent),
+			Platform:   domain.StringValue(ps.Platform),
+			CreatedAt:  domain.TimestampOf(ps.CreatedAt),
+			LastUsedAt: domain.TimestampOf(ps.LastUsedAt),
+		})
+	}
+	for _, al := range als {
+		export.AuditLogs = append(export.AuditLogs, domain.AuditLogExport{
+			ActorUUID: domain.StringValue(al.ActorUUID),
+			Action:    domain.StringValue(al.Action),
+			Target:    domain.StringValue(al.Target),
+			CreatedAt: domain.TimestampOf(al.CreatedAt),
+		})
+	}
+	export.AuditLogs = append(export.AuditLogs, domain.AuditLogExport{
+		ActorUUID: uuid,
+		Action:    domain.AuditActionExportParentData,
+		Target:    uuid,
+		CreatedAt: domain.TimestampOf(&now),
+	})
+	return
+}
+
+// checkPasswordBreached method return domain.UsecaseError if password is found in data breach by pwnedChecker
+// it is fail-open (password is allowed if checker fail), so that sign up isn't blocked by outage of external API
+func (au *authUsecase) checkPasswordBreached(pw string) (err error) {
+	if !au.myCfg.PasswordBreachCheck() || au.pwnedChecker == nil {
+		return
+	}
+
+	switch breached, cErr := au.pwnedChecker.IsBreached(pw); {
+	case cErr != nil:
+		log.Printf("failed to check if password is breached, so it is allowed, err: %v", cErr)
+	case breached:
+		err = errors.New("this password is found in data breach")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PasswordBreached}
+	}
+	return
+}
+
+// phoneNumberKey method return key of kvStore about pn, which is prefix followed by hash of normalized pn
+// kvStore isn't encrypted like parent_phone_certify, so plaintext phone number must not be kept in key
+// it is also used for target of audit log about pn, which isn't encrypted either
+func (au *authUsecase) phoneNumberKey(prefix, pn string) string {
+	return prefix + au.phoneNumberHasher.Hash(domain.NormalizePhoneNumber(pn))
+}
+
+// accountsPerPhoneKey method return key of kvStore counting account created with phone number in window
+func (au *authUsecase) accountsPerPhoneKey(pn string) string {
+	return au.phoneNumberKey("auth:accounts_per_phone:", pn)
+}
+
+// checkAccountsPerPhone method return domain.UsecaseError if number of account created with pn in window reach max
+// phone is reused by new account after withdrawal, so it is about curbing rapid re-signup, not about linked account
+// (count is read before sign up & increased after it, so that concurrent sign up may exceed max by few)
+func (au *authUsecase) checkAccountsPerPhone(pn string) (err error) {
+	limit := au.myCfg.MaxAccountsPerPhone()
+	if limit <= 0 || pn == "" {
+		return
+	}
+
+	count, exist, gErr := au.kvStore.Get(au.accountsPerPhoneKey(pn))
+	if gErr != nil {
+		log.Printf("failed to get count of account created with phone %s, so it is allowed, err: %v", domain.MaskPhoneNumber(pn), gErr)
+		return
+	}
+	if n, _ := strconv.Atoi(count); exist && n >= limit {
+		err = errors.New("too many account is created with this phone number in window")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.TooManyAccountsForPhone}
+	}
+	return
+}
+
+// countAccountForPhone method increase count of account created with pn in window, which is called after sign up
+func (au *authUsecase) countAccountForPhone(pn string) {
+	if au.myCfg.MaxAccountsPerPhone() <= 0 || pn == "" {
+		return
+	}
+	if _, err := au.kvStore.Incr(au.accountsPerPhoneKey(pn), au.myCfg.AccountsPerPhoneWindow()); err != nil {
+		log.Printf("failed to count account created with phone %s, err: %v", domain.MaskPhoneNumber(pn), err)
+	}
 }
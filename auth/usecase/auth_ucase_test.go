@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyFirstBabyTime/Server/auth/config"
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/hash"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+func TestAuthUsecase_SendCertifyCodeToPhone_OneRowPerPhone(t *testing.T) {
+	const pn = "01012345678"
+
+	t.Run("code requested again overwrite row", func(t *testing.T) {
+		deps := newTestDeps()
+		au := newTestAuthUsecase(t, deps)
+
+		for i := 0; i < 5; i++ {
+			err := au.SendCertifyCodeToPhone(context.Background(), pn, "", domain.CertifyPurposeSignUp, domain.CertifyCodeDestination{})
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, 1, deps.phoneCertifyRepo.count())
+		ppc, err := deps.phoneCertifyRepo.GetByPhoneNumber(nil, pn)
+		assert.NoError(t, err)
+		assert.False(t, domain.BoolValue(ppc.Certified))
+		assert.Contains(t, deps.messageAgency.lastSent().content, domain.StringValue(ppc.CertifyCode),
+			"code stored must be the one sent last")
+	})
+
+	t.Run("code requested concurrently", func(t *testing.T) {
+		deps := newTestDeps()
+		au := newTestAuthUsecase(t, deps)
+
+		const n = 20
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- au.SendCertifyCodeToPhone(context.Background(), pn, "", domain.CertifyPurposeSignUp, domain.CertifyCodeDestination{})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 1, deps.phoneCertifyRepo.count())
+
+		ppc, err := deps.phoneCertifyRepo.GetByPhoneNumber(nil, pn)
+		assert.NoError(t, err)
+		sent := false
+		for _, m := range deps.messageAgency.sent {
+			sent = sent || strings.Contains(m.content, domain.StringValue(ppc.CertifyCode))
+		}
+		assert.True(t, sent, "code stored must be one of code sent")
+	})
+}
+
+func TestAuthUsecase_SendCertifyCodeToPhone_ConcurrentFirstSend(t *testing.T) {
+	const pn = "01012345678"
+	deps := newTestDeps()
+	au := newTestAuthUsecase(t, deps)
+
+	// other request send code first between GetByPhoneNumber & Store, so that Store of this request is duplicated
+	var racingCode string
+	deps.phoneCertifyRepo.beforeStore = func(pn string) {
+		deps.phoneCertifyRepo.beforeStore = nil
+		assert.NoError(t, au.SendCertifyCodeToPhone(context.Background(), pn, "", domain.CertifyPurposeSignUp, domain.CertifyCodeDestination{}))
+		racingCode = deps.messageAgency.lastSent().content
+	}
+
+	err := au.SendCertifyCodeToPhone(context.Background(), pn, "", domain.CertifyPurposeSignUp, domain.CertifyCodeDestination{})
+	assert.NoError(t, err, "duplicated Store must fall back to updating row stored by other request")
+
+	assert.Equal(t, 1, deps.phoneCertifyRepo.count())
+	assert.Len(t, deps.messageAgency.sent, 2)
+	assert.NotEmpty(t, racingCode)
+
+	ppc, err := deps.phoneCertifyRepo.GetByPhoneNumber(nil, pn)
+	assert.NoError(t, err)
+	assert.False(t, domain.BoolValue(ppc.Certified))
+	assert.Contains(t, deps.messageAgency.lastSent().content, domain.StringValue(ppc.CertifyCode),
+		"code stored must be the one sent by request falling back to update")
+}
+
+// fakeParentAuthRepository is domain.ParentAuthRepository in which no parent is stored
+// every uuid is found in GetByUUID as parent linked to phone of pn, if it is set
+type fakeParentAuthRepository struct {
+	domain.ParentAuthRepository
+	pn string
+}
+
+func (pr fakeParentAuthRepository) GetByUUID(_ tx.Context, uuid string) (struct {
+	domain.ParentAuth
+	domain.ParentPhoneCertify
+}, error) {
+	pa := struct {
+		domain.ParentAuth
+		domain.ParentPhoneCertify
+	}{}
+	if pr.pn == "" {
+		return pa, domain.ErrRowNotExist{RepoErr: errors.New("parent auth is not exist")}
+	}
+	pa.UUID, pa.PhoneNumber = domain.String(uuid), domain.String(pr.pn)
+	return pa, nil
+}
+
+func (pr fakeParentAuthRepository) ExistsByID(_ tx.Context, _ string) (bool, error) {
+	return false, nil
+}
+
+func TestAuthUsecase_ReserveParentID_LimitPerClientIP(t *testing.T) {
+	deps := newTestDeps()
+	deps.parentAuthRepo = fakeParentAuthRepository{}
+	au := newTestAuthUsecase(t, deps)
+	limit := config.App.ParentIDReservationLimit()
+
+	for i := 0; i < limit; i++ {
+		_, _, err := au.ReserveParentID(context.Background(), fmt.Sprintf("parent%d", i), "10.0.0.1")
+		assert.NoError(t, err)
+	}
+
+	_, _, err := au.ReserveParentID(context.Background(), "parentover", "10.0.0.1")
+	if assert.IsType(t, domain.UsecaseError{}, err) {
+		assert.Equal(t, http.StatusTooManyRequests, err.(domain.UsecaseError).Status)
+	}
+
+	_, _, err = au.ReserveParentID(context.Background(), "parentover", "10.0.0.2")
+	assert.NoError(t, err, "reservation of other client must not be limited")
+}
+
+// signUpBenchConfig is config of BenchmarkAuthUsecase_SignUpParent, checking breach of password & not requiring phone
+type signUpBenchConfig struct {
+	authUsecaseConfig
+}
+
+func (signUpBenchConfig) PasswordBreachCheck() bool       { return true }
+func (signUpBenchConfig) RequirePhoneCertification() bool { return false }
+
+// latencyParentAuthRepository is domain.ParentAuthRepository sleeping latency in GetAvailableUUID, discarding stored row
+type latencyParentAuthRepository struct {
+	domain.ParentAuthRepository
+	latency time.Duration
+}
+
+func (pr latencyParentAuthRepository) GetAvailableUUID(_ tx.Context) (string, error) {
+	time.Sleep(pr.latency)
+	return "parent-uuid", nil
+}
+
+func (pr latencyParentAuthRepository) Store(_ tx.Context, _ *domain.ParentAuth) error { return nil }
+
+// hashedSignal is hashHandler signaling in done every time hash is generated
+type hashedSignal struct {
+	hashHandler
+	done chan struct{}
+}
+
+func (hs hashedSignal) GenerateHashWithMinSalt(pw string) (string, error) {
+	defer func() { hs.done <- struct{}{} }()
+	return hs.hashHandler.GenerateHashWithMinSalt(pw)
+}
+
+// latencyPwnedChecker is pwnedChecker sleeping latency, to model round trip of breach API
+// if hashed is set, it wait for hash to be generated first, to model sign up generating hash before other check
+type latencyPwnedChecker struct {
+	latency time.Duration
+	hashed  chan struct{}
+}
+
+func (pc latencyPwnedChecker) IsBreached(_ string) (bool, error) {
+	if pc.hashed != nil {
+		<-pc.hashed
+	}
+	time.Sleep(pc.latency)
+	return false, nil
+}
+
+func BenchmarkAuthUsecase_SignUpParent(b *testing.B) {
+	benchmarks := []struct {
+		name                       string
+		breachLatency, uuidLatency time.Duration
+	}{
+		{"no latency", 0, 0},
+		{"1ms breach check 1ms UUID", time.Millisecond, time.Millisecond},
+		{"5ms breach check 1ms UUID", 5 * time.Millisecond, time.Millisecond},
+	}
+
+	for _, bm := range benchmarks {
+		for _, sequential := range []bool{true, false} {
+			name := bm.name + " hash overlapped"
+			if sequential {
+				name = bm.name + " hash before checks"
+			}
+
+			b.Run(name, func(b *testing.B) {
+				pc, hashed := latencyPwnedChecker{latency: bm.breachLatency}, make(chan struct{}, 1)
+				if sequential {
+					pc.hashed = hashed
+				}
+				deps := newTestDeps()
+				deps.cfg = signUpBenchConfig{authUsecaseConfig: deps.cfg}
+				deps.parentAuthRepo = latencyParentAuthRepository{latency: bm.uuidLatency}
+				deps.hashHandler = hashedSignal{hashHandler: hash.BcryptHandler(""), done: hashed}
+				deps.pwnedChecker = pc
+				au := newTestAuthUsecase(b, deps)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := au.SignUpParent(context.Background(), struct {
+						*domain.ParentAuth
+						*domain.ParentPhoneCertify
+					}{
+						ParentAuth:         &domain.ParentAuth{ID: domain.String("parent1"), PW: domain.String("testpw1234"), Name: domain.String("parent")},
+						ParentPhoneCertify: &domain.ParentPhoneCertify{},
+					}, nil, ""); err != nil {
+						b.Fatal(err)
+					}
+					if !sequential {
+						<-hashed // signal is consumed here if it isn't waited in breach check
+					}
+				}
+			})
+		}
+	}
+}
+
+// sessionLimitTestConfig is config limiting parent to one active session, rejecting login exceeding it
+type sessionLimitTestConfig struct {
+	authUsecaseConfig
+}
+
+func (sessionLimitTestConfig) MaxParentSessions() int     { return 1 }
+func (sessionLimitTestConfig) SessionLimitPolicy() string { return sessionLimitPolicyReject }
+
+func TestAuthUsecase_RecordParentSession_WithoutDeviceID(t *testing.T) {
+	deps := newTestDeps()
+	deps.cfg = sessionLimitTestConfig{authUsecaseConfig: deps.cfg}
+	sessionRepo := &fakeParentSessionRepository{}
+	deps.parentSessionRepo = sessionRepo
+	au := newTestAuthUsecase(t, deps)
+	browser := domain.DeviceInfo{UserAgent: "Mozilla/5.0", Platform: "web"}
+
+	for i := 0; i < 3; i++ {
+		newDevice, _, err := au.recordParentSession(&fakeTx{}, "parent1", browser)
+		assert.NoError(t, err, "login of same client without device id must not be rejected by its own session")
+		assert.False(t, newDevice)
+	}
+	assert.Len(t, sessionRepo.sessions, 1)
+
+	_, _, err := au.recordParentSession(&fakeTx{}, "parent1", domain.DeviceInfo{UserAgent: "okhttp/4.9", Platform: "android"})
+	if assert.IsType(t, domain.UsecaseError{}, err) {
+		assert.Equal(t, domain.TooManySessions, err.(domain.UsecaseError).Code, "other client is limited as other session")
+	}
+}
+
+func TestAuthUsecase_StepUpWithPhoneCode_Mismatch(t *testing.T) {
+	const uuid, pn = "parent-111111111111", "01012345678"
+	deps := newTestDeps()
+	deps.parentAuthRepo = fakeParentAuthRepository{pn: pn}
+	deps.phoneCertifyRepo.rows[pn] = domain.ParentPhoneCertify{
+		ParentUUID:  domain.String(uuid),
+		PhoneNumber: domain.String(pn),
+		CertifyCode: domain.String("123456"),
+		Certified:   domain.Bool(true),
+	}
+	au := newTestAuthUsecase(t, deps)
+
+	_, err := au.StepUpWithPhoneCode(context.Background(), uuid, "654321")
+	if assert.IsType(t, domain.UsecaseError{}, err) {
+		assert.Equal(t, domain.IncorrectCertifyCode, err.(domain.UsecaseError).Code)
+	}
+	code := domain.StringValue(deps.phoneCertifyRepo.rows[pn].CertifyCode)
+	assert.NotEqual(t, "123456", code, "code must be replaced on mismatch, so that it can't be guessed again")
+
+	for i := 1; i < config.App.CodeVerifyAttemptLimit(); i++ {
+		_, err = au.StepUpWithPhoneCode(context.Background(), uuid, "654321")
+		assert.IsType(t, domain.UsecaseError{}, err)
+	}
+	_, err = au.StepUpWithPhoneCode(context.Background(), uuid, domain.StringValue(deps.phoneCertifyRepo.rows[pn].CertifyCode))
+	if assert.IsType(t, domain.UsecaseError{}, err) {
+		assert.Equal(t, domain.TooManyCodeAttempts, err.(domain.UsecaseError).Code, "step-up must be limited after mismatch limit")
+	}
+}
+
+func TestAuthUsecase_ForceCertifyPhone_HashedAuditTarget(t *testing.T) {
+	const adminUUID, uuid, pn = "parent-000000000000", "parent-111111111111", "01012345678"
+	deps := newTestDeps()
+	auditLogRepo := &fakeAuditLogRepository{}
+	deps.parentAuthRepo, deps.parentSessionRepo, deps.auditLogRepo = fakeParentAuthRepository{pn: pn}, &fakeParentSessionRepository{}, auditLogRepo
+	deps.phoneCertifyRepo.rows[pn] = domain.ParentPhoneCertify{ParentUUID: domain.String(uuid), PhoneNumber: domain.String(pn), Certified: domain.Bool(false)}
+	au := newTestAuthUsecase(t, deps)
+
+	assert.NoError(t, au.ForceCertifyPhone(context.Background(), adminUUID, pn))
+	if assert.Len(t, auditLogRepo.logs, 1) {
+		target := domain.StringValue(auditLogRepo.logs[0].Target)
+		assert.NotContains(t, target, pn, "plaintext phone number must not be kept in audit log")
+		assert.Equal(t, domain.AuditTargetPhoneNumberPrefix+fakePhoneNumberHasher{}.Hash(pn), target)
+	}
+
+	export, err := au.ExportParentData(context.Background(), uuid)
+	assert.NoError(t, err)
+	if assert.Len(t, export.AuditLogs, 2, "force certify of linked phone must be found with hash of it") {
+		assert.Equal(t, domain.AuditActionForceCertifyPhone, export.AuditLogs[0].Action)
+	}
+}
+
+// transientSendError is error of message provider, which is unavailable temporarily
+type transientSendError struct{ error }
+
+func (transientSendError) Transient() {}
+
+func TestAuthUsecase_BeginDeviceVerification(t *testing.T) {
+	const uuid, pn = "parent-111111111111", "01012345678"
+	newDeps := func() *testDeps {
+		deps := newTestDeps()
+		deps.phoneCertifyRepo.rows[pn] = domain.ParentPhoneCertify{
+			ParentUUID:  domain.String(uuid),
+			PhoneNumber: domain.String(pn),
+			CertifyCode: domain.String("123456"),
+			Certified:   domain.Bool(true),
+		}
+		deps.deviceVerifyRepo = &fakeDeviceVerificationRepository{}
+		return deps
+	}
+	pa := domain.ParentAuth{UUID: domain.String(uuid)}
+
+	t.Run("code is kept in verification", func(t *testing.T) {
+		deps := newDeps()
+		au := newTestAuthUsecase(t, deps)
+
+		id, err := au.beginDeviceVerification(&fakeTx{}, pa, pn, domain.DeviceInfo{})
+		assert.NoError(t, err)
+		dv := deps.deviceVerifyRepo.(*fakeDeviceVerificationRepository).rows[id]
+		assert.Contains(t, deps.messageAgency.lastSent().content, domain.StringValue(dv.CertifyCode))
+		assert.Equal(t, "123456", domain.StringValue(deps.phoneCertifyRepo.rows[pn].CertifyCode),
+			"code of phone row (ex, of step-up) must not be replaced by device verification")
+	})
+
+	t.Run("transient error of provider", func(t *testing.T) {
+		deps := newDeps()
+		deps.messageAgency.err = transientSendError{errors.New("provider timeout")}
+		au := newTestAuthUsecase(t, deps)
+
+		_, err := au.beginDeviceVerification(&fakeTx{}, pa, pn, domain.DeviceInfo{})
+		if assert.IsType(t, domain.UsecaseError{}, err) {
+			assert.Equal(t, http.StatusServiceUnavailable, err.(domain.UsecaseError).Status)
+		}
+	})
+}
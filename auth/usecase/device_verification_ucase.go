@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"net/http"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// CompleteDeviceVerification implement CompleteDeviceVerification method of domain.AuthUsecase interface
+func (au *authUsecase) CompleteDeviceVerification(ctx context.Context, verificationID, code string) (uuid, token string, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	dv, err := au.deviceVerificationRepository.GetByID(_tx, verificationID)
+	switch err.(type) {
+	case nil:
+		break
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist device verification of that ID")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "device verification GetByID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// verification begun before code was kept in it has no code, so it is regarded as expired
+	if dv.IsExpired(au.clock.Now()) || domain.StringValue(dv.CertifyCode) == "" {
+		_ = au.deviceVerificationRepository.Delete(_tx, verificationID)
+		_ = au.txHandler.Commit(_tx)
+		err = errors.New("device verification is expired, login again")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.DeviceVerificationExpired}
+		return
+	}
+
+	pa, err := au.parentAuthRepository.GetByUUID(_tx, domain.StringValue(dv.ParentUUID))
+	if err != nil {
+		err = errors.Wrap(err, "GetByUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	if pa.IsLocked(au.clock.Now()) {
+		err = errors.New("this account is locked by too many failed login, retry later")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.AccountLocked}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	// incorrect code is counted as failed login, so that guessing code is locked out like guessing password
+	// code is compared as code of phone, so that fixed code of test phone is accepted as in other certification
+	if !au.isCorrectCertifyCode(domain.ParentPhoneCertify{PhoneNumber: pa.PhoneNumber, CertifyCode: dv.CertifyCode}, code) {
+		au.certifyCodeMismatched()
+		locked, notify, fErr := au.recordParentLoginFailure(_tx, pa.ParentAuth)
+		if fErr != nil {
+			err = domain.UsecaseError{UsecaseErr: fErr, Status: http.StatusInternalServerError}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+		_ = au.txHandler.Commit(_tx)
+
+		if notify {
+			go au.parentAccountLocked(domain.StringValue(pa.PhoneNumber), dv.DeviceInfo())
+		}
+		if locked {
+			err = errors.New("incorrect certify code, and account is locked by too many failed login")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.AccountLocked}
+			return
+		}
+		err = errors.New("incorrect certify code to phone number of this parent")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.IncorrectCertifyCode}
+		return
+	}
+
+	if err = au.deviceVerificationRepository.Delete(_tx, verificationID); err != nil {
+		err = errors.Wrap(err, "device verification Delete return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if domain.IntValue(pa.FailedLoginCount) > 0 {
+		if err = au.parentAuthRepository.Update(_tx, &domain.ParentAuth{UUID: pa.UUID, FailedLoginCount: domain.Int(0)}); err != nil {
+			err = errors.Wrap(err, "failed to reset failed login count")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+			_ = au.txHandler.Rollback(_tx)
+			return
+		}
+	}
+
+	// parent is notified with certify code already, so new device login notification is not sent again
+	var evicted []domain.ParentSession
+	if token, _, evicted, err = au.completeParentLogin(_tx, pa.ParentAuth, dv.DeviceInfo()); err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return "", "", err
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	au.recordAuthEvent(ctx, domain.AuthEventLogin)
+	if len(evicted) > 0 && au.myCfg.SessionEvictionNotification() {
+		go au.parentSessionsEvicted(domain.StringValue(pa.PhoneNumber), evicted)
+	}
+	return domain.StringValue(pa.UUID), token, nil
+}
+
+// beginDeviceVerification send certify code to phone of parent & return ID of verification if device is unrecognized
+// (not recorded in session of parent), or return empty ID if device is recognized and login go on without verification
+// err is domain.UsecaseError, and transaction must be rolled back by caller on err
+func (au *authUsecase) beginDeviceVerification(_tx tx.Context, pa domain.ParentAuth, pn string, device domain.DeviceInfo) (id string, err error) {
+	uuid := domain.StringValue(pa.UUID)
+	// device without device id can't be recognized, so it is always verified
+	if device.DeviceID != "" {
+		switch _, err = au.parentSessionRepository.GetByParentUUIDAndDeviceID(_tx, uuid, device.DeviceID); err.(type) {
+		case nil:
+			return "", nil
+		case domain.ErrRowNotExist:
+			break
+		default:
+			err = errors.Wrap(err, "GetByParentUUIDAndDeviceID return unexpected error")
+			return "", domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		}
+	}
+
+	// suspended account is rejected before certify code is sent, as it is in completeParentLogin
+	if pa.IsSuspended(au.clock.Now()) {
+		err = errors.Errorf("this account is suspended, reason: %s", domain.StringValue(pa.SuspendReason))
+		return "", domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.AccountSuspended}
+	}
+
+	// code is kept only in verification, so that code shared in phone row (ex, of step-up) isn't replaced by login
+	dv := &domain.DeviceVerification{
+		ParentUUID:  domain.String(uuid),
+		DeviceID:    domain.String(device.DeviceID),
+		UserAgent:   domain.String(device.UserAgent),
+		Platform:    domain.String(device.Platform),
+		CertifyCode: domain.String(au.newCertifyCode(pn)),
+		ExpiresAt:   domain.Time(au.clock.Now().Add(au.myCfg.DeviceVerificationTimeout())),
+	}
+	if err = au.deviceVerificationRepository.Store(_tx, dv); err != nil {
+		err = errors.Wrap(err, "device verification Store return unexpected error")
+		return "", domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+
+	// verification is stored in transaction rolled back by caller on err, so it isn't left pending if code isn't sent
+	content := au.certifyMessage(domain.CertifyPurposeDeviceVerification, domain.StringValue(dv.CertifyCode))
+	if _, err = au.messageAgency.SendSMSToOne(pn, content); err != nil {
+		return "", certifyCodeSendError(err)
+	}
+	return domain.StringValue(dv.ID), nil
+}
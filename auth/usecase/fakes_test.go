@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/auth/config"
+	_clock "github.com/MyFirstBabyTime/Server/clock"
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/kv"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// fakeTx is tx.Context used in test, which doesn't have real transaction
+type fakeTx struct {
+	context.Context
+}
+
+func (ft *fakeTx) Tx() interface{}     { return nil }
+func (ft *fakeTx) SetTx(_ interface{}) {}
+
+// fakeTxHandler is txHandler beginning fakeTx, counting transaction ended
+type fakeTxHandler struct {
+	mu                 sync.Mutex
+	commits, rollbacks int
+}
+
+func (th *fakeTxHandler) BeginTx(ctx context.Context, _ interface{}) (tx.Context, error) {
+	return &fakeTx{Context: ctx}, nil
+}
+
+func (th *fakeTxHandler) Commit(_ tx.Context) error {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	th.commits++
+	return nil
+}
+
+func (th *fakeTxHandler) Rollback(_ tx.Context) error {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	th.rollbacks++
+	return nil
+}
+
+// fakePhoneCertifyRepository is domain.ParentPhoneCertifyRepository keeping row in map keyed by phone number (PK)
+type fakePhoneCertifyRepository struct {
+	domain.ParentPhoneCertifyRepository
+
+	mu   sync.Mutex
+	rows map[string]domain.ParentPhoneCertify
+
+	// beforeStore is called before Store insert row if set (ex, to store same row by concurrent request)
+	beforeStore func(pn string)
+}
+
+func newFakePhoneCertifyRepository() *fakePhoneCertifyRepository {
+	return &fakePhoneCertifyRepository{rows: map[string]domain.ParentPhoneCertify{}}
+}
+
+func (pr *fakePhoneCertifyRepository) GetByPhoneNumber(_ tx.Context, pn string) (domain.ParentPhoneCertify, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	ppc, ok := pr.rows[pn]
+	if !ok {
+		return domain.ParentPhoneCertify{}, domain.ErrRowNotExist{RepoErr: errors.New("parent phone certify is not exist")}
+	}
+	return ppc, nil
+}
+
+func (pr *fakePhoneCertifyRepository) Store(_ tx.Context, ppc *domain.ParentPhoneCertify) error {
+	pn := domain.StringValue(ppc.PhoneNumber)
+	if pr.beforeStore != nil {
+		pr.beforeStore(pn)
+	}
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if _, ok := pr.rows[pn]; ok {
+		err := errors.New("parent phone certify is already exist")
+		return domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: domain.DuplicateKeyPrimary}
+	}
+	row := *ppc
+	if row.Certified == nil {
+		row.Certified = domain.Bool(false)
+	}
+	pr.rows[pn] = row
+	return nil
+}
+
+func (pr *fakePhoneCertifyRepository) Update(_ tx.Context, ppc *domain.ParentPhoneCertify) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pn := domain.StringValue(ppc.PhoneNumber)
+	row, ok := pr.rows[pn]
+	if !ok {
+		return nil
+	}
+	if ppc.ParentUUID != nil {
+		row.ParentUUID = ppc.ParentUUID
+	}
+	if ppc.CertifyCode != nil {
+		row.CertifyCode = ppc.CertifyCode
+	}
+	if ppc.Certified != nil {
+		row.Certified = ppc.Certified
+	}
+	if ppc.SentChannel != nil {
+		row.SentChannel = ppc.SentChannel
+	}
+	if ppc.PreferredChannel != nil {
+		row.PreferredChannel = ppc.PreferredChannel
+	}
+	pr.rows[pn] = row
+	return nil
+}
+
+// count return number of row stored
+func (pr *fakePhoneCertifyRepository) count() int {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	return len(pr.rows)
+}
+
+// fakeParentSessionRepository is domain.ParentSessionRepository keeping session of parent in slice
+type fakeParentSessionRepository struct {
+	domain.ParentSessionRepository
+
+	mu       sync.Mutex
+	sessions []domain.ParentSession
+}
+
+// find return index of last used session matched by match in sessions, and -1 if not exist (mu must be locked)
+func (sr *fakeParentSessionRepository) find(match func(s domain.ParentSession) bool) int {
+	found := -1
+	for i, s := range sr.sessions {
+		if match(s) && (found < 0 || s.LastUsedAt.After(*sr.sessions[found].LastUsedAt)) {
+			found = i
+		}
+	}
+	return found
+}
+
+func (sr *fakeParentSessionRepository) get(match func(s domain.ParentSession) bool) (domain.ParentSession, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if i := sr.find(match); i >= 0 {
+		return sr.sessions[i], nil
+	}
+	return domain.ParentSession{}, domain.ErrRowNotExist{RepoErr: errors.New("parent session is not exist")}
+}
+
+func (sr *fakeParentSessionRepository) GetByParentUUIDAndDeviceID(_ tx.Context, parentUUID, deviceID string) (domain.ParentSession, error) {
+	return sr.get(func(s domain.ParentSession) bool {
+		return domain.StringValue(s.ParentUUID) == parentUUID && domain.StringValue(s.DeviceID) == deviceID
+	})
+}
+
+func (sr *fakeParentSessionRepository) GetDevicelessByParentUUID(_ tx.Context, parentUUID, userAgent, platform string) (domain.ParentSession, error) {
+	return sr.get(func(s domain.ParentSession) bool {
+		return domain.StringValue(s.ParentUUID) == parentUUID && domain.StringValue(s.DeviceID) == "" &&
+			domain.StringValue(s.UserAgent) == userAgent && domain.StringValue(s.Platform) == platform
+	})
+}
+
+func (sr *fakeParentSessionRepository) CountUsedSince(_ tx.Context, parentUUID string, since time.Time) (int, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	count := 0
+	for _, s := range sr.sessions {
+		if domain.StringValue(s.ParentUUID) == parentUUID && !s.LastUsedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (sr *fakeParentSessionRepository) Store(_ tx.Context, ps *domain.ParentSession) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	ps.UUID = domain.String("s-" + strconv.Itoa(len(sr.sessions)))
+	sr.sessions = append(sr.sessions, *ps)
+	return nil
+}
+
+func (sr *fakeParentSessionRepository) Update(_ tx.Context, ps *domain.ParentSession) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if i := sr.find(func(s domain.ParentSession) bool { return domain.StringValue(s.UUID) == domain.StringValue(ps.UUID) }); i >= 0 {
+		sr.sessions[i].LastUsedAt = ps.LastUsedAt
+	}
+	return nil
+}
+
+func (sr *fakeParentSessionRepository) GetByParentUUID(_ tx.Context, parentUUID string) (pss []domain.ParentSession, _ error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	for _, s := range sr.sessions {
+		if domain.StringValue(s.ParentUUID) == parentUUID {
+			pss = append(pss, s)
+		}
+	}
+	return pss, nil
+}
+
+// fakeAuditLogRepository is domain.AuditLogRepository keeping audit log in slice in order of stored
+type fakeAuditLogRepository struct {
+	mu   sync.Mutex
+	logs []domain.AuditLog
+}
+
+func (ar *fakeAuditLogRepository) GetByActorUUIDOrTarget(_ tx.Context, actorUUID string, targets []string) (als []domain.AuditLog, _ error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	for _, al := range ar.logs {
+		matched := domain.StringValue(al.ActorUUID) == actorUUID
+		for _, target := range targets {
+			matched = matched || domain.StringValue(al.Target) == target
+		}
+		if matched {
+			als = append(als, al)
+		}
+	}
+	return als, nil
+}
+
+func (ar *fakeAuditLogRepository) Store(_ tx.Context, al *domain.AuditLog) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	al.ID = domain.Int64(int64(len(ar.logs) + 1))
+	ar.logs = append(ar.logs, *al)
+	return nil
+}
+
+// fakeDeviceVerificationRepository is domain.DeviceVerificationRepository keeping verification in map keyed by ID
+type fakeDeviceVerificationRepository struct {
+	domain.DeviceVerificationRepository
+
+	mu   sync.Mutex
+	rows map[string]domain.DeviceVerification
+}
+
+func (dr *fakeDeviceVerificationRepository) Store(_ tx.Context, dv *domain.DeviceVerification) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if dr.rows == nil {
+		dr.rows = map[string]domain.DeviceVerification{}
+	}
+	dv.ID = domain.String(dv.GenerateRandomID())
+	dr.rows[domain.StringValue(dv.ID)] = *dv
+	return nil
+}
+
+// fakeAuthEventRepository is domain.AuthEventRepository discarding event
+type fakeAuthEventRepository struct {
+	domain.AuthEventRepository
+}
+
+func (er fakeAuthEventRepository) Store(_ tx.Context, _ *domain.AuthEvent) error { return nil }
+
+// fakePhoneCertifyEventRepository is domain.PhoneCertifyEventRepository discarding event
+type fakePhoneCertifyEventRepository struct {
+	domain.PhoneCertifyEventRepository
+}
+
+func (er fakePhoneCertifyEventRepository) Store(_ tx.Context, _ *domain.PhoneCertifyEvent) error {
+	return nil
+}
+
+// sentMessage is message sent through fakeMessageAgency
+type sentMessage struct {
+	receiver, content string
+}
+
+// fakeMessageAgency is messageAgency recording message sent with SMS
+type fakeMessageAgency struct {
+	mu   sync.Mutex
+	sent []sentMessage
+
+	// err is returned from SendSMSToOne without recording message if set (ex, to fail provider)
+	err error
+}
+
+func (ma *fakeMessageAgency) SendSMSToOne(receiver, content string) (domain.SMSResult, error) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+	if ma.err != nil {
+		return domain.SMSResult{}, ma.err
+	}
+	ma.sent = append(ma.sent, sentMessage{receiver: receiver, content: content})
+	return domain.SMSResult{Status: domain.SMSStatusSent, MsgType: "SMS", Segments: 1}, nil
+}
+
+func (ma *fakeMessageAgency) SendVoiceCode(_, _ string) error {
+	return errors.New("voice is not supported in fake message agency")
+}
+
+func (ma *fakeMessageAgency) SendEmail(_, _, _ string) error {
+	return errors.New("email is not supported in fake message agency")
+}
+
+// lastSent return last message sent, and empty if nothing is sent
+func (ma *fakeMessageAgency) lastSent() sentMessage {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+	if len(ma.sent) == 0 {
+		return sentMessage{}
+	}
+	return ma.sent[len(ma.sent)-1]
+}
+
+// fakePhoneNumberHasher is phoneNumberHasher using SHA-256 without key
+type fakePhoneNumberHasher struct{}
+
+func (fakePhoneNumberHasher) Hash(pn string) string {
+	sum := sha256.Sum256([]byte(pn))
+	return hex.EncodeToString(sum[:])
+}
+
+// testDeps is dependency of authUsecase created in newTestAuthUsecase, replaced in test if needed before creation
+type testDeps struct {
+	cfg               authUsecaseConfig
+	parentAuthRepo    domain.ParentAuthRepository
+	phoneCertifyRepo  *fakePhoneCertifyRepository
+	parentSessionRepo domain.ParentSessionRepository
+	deviceVerifyRepo  domain.DeviceVerificationRepository
+	auditLogRepo      domain.AuditLogRepository
+	txHandler         *fakeTxHandler
+	messageAgency     *fakeMessageAgency
+	hashHandler       hashHandler
+	jwtHandler        jwtHandler
+	kvStore           kvStore
+	pwnedChecker      pwnedChecker
+}
+
+// newTestDeps return testDeps with fake & in-memory dependency, using default value of config
+func newTestDeps() *testDeps {
+	return &testDeps{
+		cfg:              config.App,
+		phoneCertifyRepo: newFakePhoneCertifyRepository(),
+		txHandler:        &fakeTxHandler{},
+		messageAgency:    &fakeMessageAgency{},
+		kvStore:          kv.Memory(_clock.Real()),
+	}
+}
+
+// newTestAuthUsecase return authUsecase created with deps
+func newTestAuthUsecase(tb testing.TB, deps *testDeps) *authUsecase {
+	tb.Helper()
+	au := AuthUsecase(
+		deps.cfg,
+		deps.parentAuthRepo, deps.phoneCertifyRepo, deps.parentSessionRepo, deps.auditLogRepo, nil, nil, deps.deviceVerifyRepo,
+		fakeAuthEventRepository{}, nil, fakePhoneCertifyEventRepository{},
+		deps.txHandler, deps.messageAgency, deps.hashHandler, deps.jwtHandler, nil, nil, nil,
+		_clock.Real(), deps.kvStore, deps.pwnedChecker, fakePhoneNumberHasher{},
+	)
+	return au.(*authUsecase)
+}
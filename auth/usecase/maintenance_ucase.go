@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"net/http"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// maintenanceModeKey is key of kv store keeping maintenance mode, so that mode changed by admin apply to every instance
+const maintenanceModeKey = "auth:maintenance_mode"
+
+// operation value disabled in maintenance mode, which is set in MaintenanceDisabledOperations of config
+const (
+	maintenanceOpSignUp          = "sign_up"
+	maintenanceOpSendCertifyCode = "send_certify_code"
+)
+
+// maintenanceOps is set of operation able to be disabled in maintenance mode, used for validating config
+var maintenanceOps = map[string]bool{
+	maintenanceOpSignUp:          true,
+	maintenanceOpSendCertifyCode: true,
+}
+
+// GetMaintenanceMode implement GetMaintenanceMode method of domain.AuthUsecase interface
+func (au *authUsecase) GetMaintenanceMode(ctx context.Context) (enabled bool, err error) {
+	return au.maintenanceMode(), nil
+}
+
+// SetMaintenanceMode implement SetMaintenanceMode method of domain.AuthUsecase interface
+func (au *authUsecase) SetMaintenanceMode(ctx context.Context, adminUUID string, enabled bool) (err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	mode := "off"
+	if enabled {
+		mode = "on"
+	}
+
+	now := au.clock.Now()
+	if err = au.auditLogRepository.Store(_tx, &domain.AuditLog{
+		ActorUUID: domain.String(adminUUID),
+		Action:    domain.String(domain.AuditActionSetMaintenanceMode),
+		Target:    domain.String(mode),
+		CreatedAt: &now,
+	}); err != nil {
+		// mode change is not allowed without audit log, so rollback
+		err = errors.Wrap(err, "audit log Store return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if err = au.kvStore.Set(maintenanceModeKey, mode, 0); err != nil {
+		err = errors.Wrap(err, "failed to set maintenance mode in kv store")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	log.Printf("maintenance mode is turned %s by admin %s", mode, adminUUID)
+	return
+}
+
+// maintenanceMode return if maintenance mode is on, by mode set by admin in kv store or by config if never set
+// mode of config is used if kv store is unavailable, so that operation is not blocked by failure of kv store
+func (au *authUsecase) maintenanceMode() bool {
+	mode, ok, err := au.kvStore.Get(maintenanceModeKey)
+	if err != nil {
+		log.Printf("failed to get maintenance mode from kv store, err: %v", err)
+		return au.myCfg.MaintenanceMode()
+	}
+	if !ok {
+		return au.myCfg.MaintenanceMode()
+	}
+	return mode == "on"
+}
+
+// checkMaintenance return domain.UsecaseError with 503 if op is disabled in maintenance mode & the mode is on
+func (au *authUsecase) checkMaintenance(op string) error {
+	disabled := false
+	for _, dop := range au.myCfg.MaintenanceDisabledOperations() {
+		if dop == op {
+			disabled = true
+			break
+		}
+	}
+	if !disabled || !au.maintenanceMode() {
+		return nil
+	}
+
+	err := errors.Errorf("%s is disabled in maintenance mode", op)
+	return domain.UsecaseError{UsecaseErr: err, Status: http.StatusServiceUnavailable, Code: domain.UnderMaintenance}
+}
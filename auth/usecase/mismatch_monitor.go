@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"expvar"
+	"log"
+	"time"
+)
+
+// certifyCodeMismatchMetric is metric counting certify code mismatch (incorrect certify code) since process start
+var certifyCodeMismatchMetric = expvar.NewInt("auth_certify_code_mismatch")
+
+// key of state in kvStore used for detecting spike of certify code mismatch across instances
+const (
+	certifyCodeMismatchCountKey   = "auth:certify_code_mismatch:count"
+	certifyCodeMismatchAlertedKey = "auth:certify_code_mismatch:alerted"
+)
+
+// alertHook is interface about hook alerting to operator (ex, paging on-call), noopAlertHook is used if not injected
+type alertHook interface {
+	// CertifyCodeMismatchSpiked is called when count of certify code mismatch in window exceed threshold,
+	// which likely indicate brute-force attempt (called at most once in each window)
+	CertifyCodeMismatchSpiked(count int, window time.Duration)
+}
+
+// noopAlertHook is alertHook doing nothing, used if alert hook is not injected
+type noopAlertHook struct{}
+
+func (noopAlertHook) CertifyCodeMismatchSpiked(int, time.Duration) {}
+
+// certifyCodeMismatched handle event that certify code is mismatched, by counting it & alerting spike of mismatch
+// mismatch is counted in fixed window started at first mismatch, and alert is fired only by instance setting flag
+// threshold 0 disable alert, but mismatch is still counted in certifyCodeMismatchMetric
+func (au *authUsecase) certifyCodeMismatched() {
+	certifyCodeMismatchMetric.Add(1)
+	window, threshold := au.myCfg.CertifyCodeMismatchAlertWindow(), au.myCfg.CertifyCodeMismatchAlertThreshold()
+	if threshold <= 0 || window <= 0 {
+		return
+	}
+
+	count, err := au.kvStore.Incr(certifyCodeMismatchCountKey, window)
+	if err != nil {
+		log.Printf("failed to count certify code mismatch, err: %v", err)
+		return
+	}
+	if count <= int64(threshold) {
+		return
+	}
+
+	switch first, err := au.kvStore.SetNX(certifyCodeMismatchAlertedKey, "1", window); {
+	case err != nil:
+		log.Printf("failed to set flag of certify code mismatch alert, err: %v", err)
+	case first:
+		log.Printf("certify code mismatch spiked, count: %d, window: %s", count, window)
+		go au.alertHook.CertifyCodeMismatchSpiked(int(count), window)
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"net/http"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// GetNotificationPreferences implement GetNotificationPreferences method of domain.AuthUsecase interface
+func (au *authUsecase) GetNotificationPreferences(ctx context.Context, uuid string) (prefs domain.NotificationPreferences, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	prefs, err = au.notificationPreferences(_tx, uuid)
+	// nothing is changed, so rollback rather than commit
+	_ = au.txHandler.Rollback(_tx)
+	return
+}
+
+// UpdateNotificationPreferences implement UpdateNotificationPreferences method of domain.AuthUsecase interface
+func (au *authUsecase) UpdateNotificationPreferences(ctx context.Context, uuid string, marketing bool) (prefs domain.NotificationPreferences, err error) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	np := &domain.ParentNotificationPreference{ParentUUID: domain.String(uuid), Marketing: domain.Bool(marketing)}
+	switch _, err = au.parentNotificationPreferenceRepository.GetByParentUUID(_tx, uuid); err.(type) {
+	case nil:
+		err = au.parentNotificationPreferenceRepository.Update(_tx, np)
+	case domain.ErrRowNotExist:
+		err = au.parentNotificationPreferenceRepository.Store(_tx, np)
+	default:
+		err = errors.Wrap(err, "GetByParentUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	switch err.(type) {
+	case nil:
+		break
+	case domain.ErrNoReferencedRow:
+		err = errors.New("not exist parent auth with that uuid")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "failed to save notification preference")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	prefs = domain.DefaultNotificationPreferences()
+	prefs.Marketing = marketing
+	return
+}
+
+// notificationPreferences return notification preferences of parent, or default one if parent never changed it
+// err is domain.UsecaseError, and transaction must be rolled back by caller on err
+func (au *authUsecase) notificationPreferences(_tx tx.Context, uuid string) (prefs domain.NotificationPreferences, err error) {
+	prefs = domain.DefaultNotificationPreferences()
+	switch np, err := au.parentNotificationPreferenceRepository.GetByParentUUID(_tx, uuid); err.(type) {
+	case nil:
+		prefs.Marketing = domain.BoolValue(np.Marketing)
+	case domain.ErrRowNotExist:
+		break
+	default:
+		err = errors.Wrap(err, "GetByParentUUID return unexpected error")
+		return prefs, domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+	return
+}
+
+// allowNonEssentialMessage return if non-essential message (ex, welcome) can be sent to parent by preferences
+// it is called after transaction of event was ended, so message is not sent if preferences can't be got
+func (au *authUsecase) allowNonEssentialMessage(ctx context.Context, uuid string) bool {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		log.Printf("failed to begin transaction to get notification preference, err: %v", err)
+		return false
+	}
+
+	prefs, err := au.notificationPreferences(_tx, uuid)
+	_ = au.txHandler.Rollback(_tx)
+	if err != nil {
+		log.Printf("failed to get notification preference of parent %s, err: %v", uuid, err)
+		return false
+	}
+	return prefs.Marketing
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// parentIDReservationKeyPrefix is prefix of kv store key keeping reservation of parent ID, followed by normalized ID
+const parentIDReservationKeyPrefix = "auth:parent_id_reservation:"
+
+// parentIDReservationCountKeyPrefix is prefix of kv store key counting reservation of client, followed by client IP
+const parentIDReservationCountKeyPrefix = "auth:parent_id_reservation_count:"
+
+// ReserveParentID implement ReserveParentID method of domain.AuthUsecase interface
+func (au *authUsecase) ReserveParentID(ctx context.Context, id, clientIP string) (reservation string, expiresAt time.Time, err error) {
+	// reservation is first step of sign up, so it is disabled with sign up
+	if err = au.checkMaintenance(maintenanceOpSignUp); err != nil {
+		return
+	}
+
+	// route isn't authenticated, so reservation is limited per client IP not to let one client hold many ID
+	if limit := au.myCfg.ParentIDReservationLimit(); limit > 0 && clientIP != "" {
+		switch n, iErr := au.kvStore.Incr(parentIDReservationCountKeyPrefix+clientIP, au.myCfg.ParentIDReservationWindow()); {
+		case iErr != nil:
+			log.Printf("failed to count reservation of parent ID, so it is allowed, err: %v", iErr)
+		case n > int64(limit):
+			err = errors.New("parent ID is reserved too many times by this client, retry later")
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusTooManyRequests}
+			return
+		}
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	id = domain.NormalizeParentID(id)
+	switch exist, err := au.parentAuthRepository.ExistsByID(_tx, id); {
+	case err != nil:
+		err = errors.Wrap(err, "ExistsByID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return "", time.Time{}, err
+	case exist:
+		err = errors.New("this parent ID is already in use")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentIDAlreadyInUse}
+		_ = au.txHandler.Rollback(_tx)
+		return "", time.Time{}, err
+	}
+	// nothing is changed, so rollback rather than commit
+	_ = au.txHandler.Rollback(_tx)
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	reservation = hex.EncodeToString(b)
+	ttl := au.myCfg.ParentIDReservationTTL()
+
+	switch ok, err := au.kvStore.SetNX(parentIDReservationKeyPrefix+id, reservation, ttl); {
+	case err != nil:
+		err = errors.Wrap(err, "failed to set reservation of parent ID in kv store")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return "", time.Time{}, err
+	case !ok:
+		err = errors.New("this parent ID is reserved by another client")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentIDReservedByAnother}
+		return "", time.Time{}, err
+	}
+
+	expiresAt = au.clock.Now().Add(ttl)
+	return
+}
+
+// checkParentIDReservation return domain.UsecaseError with 409 if id is reserved with reservation other than one given
+// sign up is not blocked if kv store is unavailable, since unique key of ID still prevent duplicate in that case
+func (au *authUsecase) checkParentIDReservation(id, reservation string) error {
+	holder, ok, err := au.kvStore.Get(parentIDReservationKeyPrefix + id)
+	if err != nil {
+		log.Printf("failed to get reservation of parent ID from kv store, err: %v", err)
+		return nil
+	}
+	if !ok || holder == reservation {
+		return nil
+	}
+
+	err = errors.New("this parent ID is reserved by another client")
+	return domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.ParentIDReservedByAnother}
+}
@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"net/http"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// webAuthnAgency is used as agency about WebAuthn (passkey) ceremony, verifying attestation & assertion of authenticator
+// options & response are JSON exchanged with client, and session is opaque data kept by usecase between begin & finish
+type webAuthnAgency interface {
+	// BeginRegistration return options of credential creation for user & session of the ceremony
+	BeginRegistration(user domain.WebAuthnUser) (options, session []byte, err error)
+
+	// FinishRegistration verify attestation response with session & return credential to be stored
+	// return error implementing VerificationFailed() if response is not verified
+	FinishRegistration(user domain.WebAuthnUser, session, response []byte) (cred domain.ParentWebAuthnCredential, err error)
+
+	// BeginLogin return options of credential assertion for user & session of the ceremony
+	BeginLogin(user domain.WebAuthnUser) (options, session []byte, err error)
+
+	// FinishLogin verify assertion response with session & return credential asserted, having updated sign count
+	// return error implementing VerificationFailed() if response is not verified (ex, signature, sign count)
+	FinishLogin(user domain.WebAuthnUser, session, response []byte) (cred domain.ParentWebAuthnCredential, err error)
+}
+
+// BeginParentPasskeyRegistration implement BeginParentPasskeyRegistration method of domain.AuthUsecase interface
+func (au *authUsecase) BeginParentPasskeyRegistration(ctx context.Context, uuid string) (options []byte, ceremonyID string, err error) {
+	if au.webAuthnAgency == nil {
+		err = errors.New("passkey is not available in this server")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotImplemented, Code: domain.PasskeyUnavailable}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	_, user, err := au.webAuthnUser(_tx, au.parentAuthRepository.GetByUUID, uuid)
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	options, session, err := au.webAuthnAgency.BeginRegistration(user)
+	if err != nil {
+		err = errors.Wrap(err, "BeginRegistration return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if ceremonyID, err = au.storeWebAuthnCeremony(_tx, uuid, domain.WebAuthnCeremonyRegistration, session); err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	return
+}
+
+// FinishParentPasskeyRegistration implement FinishParentPasskeyRegistration method of domain.AuthUsecase interface
+func (au *authUsecase) FinishParentPasskeyRegistration(ctx context.Context, uuid, ceremonyID string, response []byte) (err error) {
+	if au.webAuthnAgency == nil {
+		err = errors.New("passkey is not available in this server")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotImplemented, Code: domain.PasskeyUnavailable}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	wc, err := au.takeWebAuthnCeremony(_tx, ceremonyID, domain.WebAuthnCeremonyRegistration)
+	if err == nil && domain.StringValue(wc.ParentUUID) != uuid {
+		err = errors.New("not exist passkey registration of that ceremony ID")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+	}
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_, user, err := au.webAuthnUser(_tx, au.parentAuthRepository.GetByUUID, uuid)
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	cred, err := au.webAuthnAgency.FinishRegistration(user, wc.Session, response)
+	switch err.(type) {
+	case nil:
+		break
+	case interface{ VerificationFailed() }:
+		err = errors.New("failed to verify passkey registration")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PasskeyVerificationFailed}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "FinishRegistration return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	now := au.clock.Now()
+	cred.ParentUUID, cred.CreatedAt = domain.String(uuid), &now
+	switch err = au.parentWebAuthnCredentialRepository.Store(_tx, &cred); err.(type) {
+	case nil:
+		break
+	case domain.ErrEntryDuplicate:
+		err = errors.New("this passkey is already registered")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PasskeyVerificationFailed}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "credential Store return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	log.Printf("passkey is registered, uuid: %s", uuid)
+	return
+}
+
+// BeginParentPasskeyLogin implement BeginParentPasskeyLogin method of domain.AuthUsecase interface
+func (au *authUsecase) BeginParentPasskeyLogin(ctx context.Context, id string) (options []byte, ceremonyID string, err error) {
+	if au.webAuthnAgency == nil {
+		err = errors.New("passkey is not available in this server")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotImplemented, Code: domain.PasskeyUnavailable}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	_, user, err := au.webAuthnUser(_tx, au.parentAuthRepository.GetByID, domain.NormalizeParentID(id))
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	if len(user.Credentials) == 0 {
+		err = errors.New("no passkey is registered to this parent")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.NoPasskeyRegistered}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	options, session, err := au.webAuthnAgency.BeginLogin(user)
+	if err != nil {
+		err = errors.Wrap(err, "BeginLogin return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	if ceremonyID, err = au.storeWebAuthnCeremony(_tx, user.UUID, domain.WebAuthnCeremonyLogin, session); err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	return
+}
+
+// FinishParentPasskeyLogin implement FinishParentPasskeyLogin method of domain.AuthUsecase interface
+// login with passkey is not limited by lockout of password login, since lockout is against guessing password
+func (au *authUsecase) FinishParentPasskeyLogin(ctx context.Context, ceremonyID string, response []byte, device domain.DeviceInfo) (uuid, token string, err error) {
+	if au.webAuthnAgency == nil {
+		err = errors.New("passkey is not available in this server")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotImplemented, Code: domain.PasskeyUnavailable}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	wc, err := au.takeWebAuthnCeremony(_tx, ceremonyID, domain.WebAuthnCeremonyLogin)
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	pa, user, err := au.webAuthnUser(_tx, au.parentAuthRepository.GetByUUID, domain.StringValue(wc.ParentUUID))
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	cred, err := au.webAuthnAgency.FinishLogin(user, wc.Session, response)
+	switch err.(type) {
+	case nil:
+		break
+	case interface{ VerificationFailed() }:
+		err = errors.New("failed to verify passkey")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PasskeyVerificationFailed}
+		// ceremony is taken (deleted) even though verification is failed, so that it is not retried
+		_ = au.txHandler.Commit(_tx)
+		return
+	default:
+		err = errors.Wrap(err, "FinishLogin return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	now := au.clock.Now()
+	if err = au.parentWebAuthnCredentialRepository.Update(_tx, &domain.ParentWebAuthnCredential{
+		ID:         cred.ID,
+		SignCount:  cred.SignCount,
+		LastUsedAt: &now,
+	}); err != nil {
+		err = errors.Wrap(err, "credential Update return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+
+	token, newDevice, evicted, err := au.completeParentLogin(_tx, pa.ParentAuth, device)
+	if err != nil {
+		_ = au.txHandler.Rollback(_tx)
+		return "", "", err
+	}
+
+	_ = au.txHandler.Commit(_tx)
+	au.recordAuthEvent(ctx, domain.AuthEventLogin)
+	if newDevice && au.myCfg.NewDeviceLoginNotification() {
+		go au.parentLoggedInFromNewDevice(domain.StringValue(pa.PhoneNumber), device)
+	}
+	if len(evicted) > 0 && au.myCfg.SessionEvictionNotification() {
+		go au.parentSessionsEvicted(domain.StringValue(pa.PhoneNumber), evicted)
+	}
+	return domain.StringValue(pa.UUID), token, nil
+}
+
+// webAuthnUser return parent got with getParent (GetByUUID or GetByID) & WebAuthn user having credentials of it
+// err is domain.UsecaseError, and transaction must be rolled back by caller on err
+func (au *authUsecase) webAuthnUser(_tx tx.Context, getParent func(tx.Context, string) (struct {
+	domain.ParentAuth
+	domain.ParentPhoneCertify
+}, error), key string) (pa struct {
+	domain.ParentAuth
+	domain.ParentPhoneCertify
+}, user domain.WebAuthnUser, err error) {
+	pa, err = getParent(_tx, key)
+	switch err.(type) {
+	case nil:
+		break
+	case domain.ErrRowNotExist:
+		err = errors.New("not exist parent ID")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.NotExistParentID}
+		return
+	default:
+		err = errors.Wrap(err, "get parent auth return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return
+	}
+
+	creds, err := au.parentWebAuthnCredentialRepository.GetByParentUUID(_tx, domain.StringValue(pa.UUID))
+	if err != nil {
+		err = errors.Wrap(err, "credential GetByParentUUID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return
+	}
+
+	user = domain.WebAuthnUser{
+		UUID:        domain.StringValue(pa.UUID),
+		ID:          domain.StringValue(pa.ID),
+		Name:        domain.StringValue(pa.Name),
+		Credentials: creds,
+	}
+	return
+}
+
+// storeWebAuthnCeremony store session of WebAuthn ceremony begun by parent & return ID of the ceremony
+// err is domain.UsecaseError, and transaction must be rolled back by caller on err
+func (au *authUsecase) storeWebAuthnCeremony(_tx tx.Context, uuid, _type string, session []byte) (id string, err error) {
+	wc := &domain.WebAuthnCeremony{
+		ParentUUID: domain.String(uuid),
+		Type:       domain.String(_type),
+		Session:    session,
+		ExpiresAt:  domain.Time(au.clock.Now().Add(au.myCfg.WebAuthnCeremonyTimeout())),
+	}
+	if err = au.webAuthnCeremonyRepository.Store(_tx, wc); err != nil {
+		err = errors.Wrap(err, "ceremony Store return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return
+	}
+	return domain.StringValue(wc.ID), nil
+}
+
+// takeWebAuthnCeremony get WebAuthn ceremony of id & type and delete it, so that ceremony is finished only once
+// err is domain.UsecaseError, and transaction must be rolled back by caller on err
+func (au *authUsecase) takeWebAuthnCeremony(_tx tx.Context, id, _type string) (wc domain.WebAuthnCeremony, err error) {
+	wc, err = au.webAuthnCeremonyRepository.GetByID(_tx, id)
+	switch err.(type) {
+	case nil:
+		if domain.StringValue(wc.Type) != _type {
+			err = errors.Errorf("not exist passkey %s of that ceremony ID", _type)
+			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+			return
+		}
+	case domain.ErrRowNotExist:
+		err = errors.Errorf("not exist passkey %s of that ceremony ID", _type)
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusNotFound}
+		return
+	default:
+		err = errors.Wrap(err, "ceremony GetByID return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return
+	}
+
+	if err = au.webAuthnCeremonyRepository.Delete(_tx, id); err != nil {
+		err = errors.Wrap(err, "ceremony Delete return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		return
+	}
+	if wc.IsExpired(au.clock.Now()) {
+		err = errors.New("passkey ceremony is expired, begin it again")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.PasskeyCeremonyExpired}
+		return
+	}
+	return
+}
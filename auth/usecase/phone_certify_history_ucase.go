@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// ListPhoneCertifyHistory implement ListPhoneCertifyHistory method of domain.AuthUsecase interface
+func (au *authUsecase) ListPhoneCertifyHistory(ctx context.Context, pn, cursor string, limit int) (events []domain.PhoneCertifyEvent, next, prev string, err error) {
+	c, err := domain.DecodeCursor(cursor)
+	if err != nil {
+		err = domain.UsecaseError{UsecaseErr: errors.Wrap(err, "invalid cursor"), Status: http.StatusBadRequest}
+		return
+	}
+
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		err = errors.Wrap(err, "failed to begin transaction")
+		return
+	}
+
+	pces, err := au.phoneCertifyEventRepository.ListAfter(_tx, pn, c, limit)
+	if err != nil {
+		err = errors.Wrap(err, "ListAfter return unexpected error")
+		err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	// nothing is changed, so rollback rather than commit
+	_ = au.txHandler.Rollback(_tx)
+
+	keys := make([]string, len(pces))
+	for i, pce := range pces {
+		keys[i] = strconv.FormatInt(domain.Int64Value(pce.ID), 10)
+	}
+	from, to, next, prev := domain.Paginate(c, keys, limit)
+	return pces[from:to], next, prev, nil
+}
+
+// recordPhoneCertifyEvent store event in certification of phone listed in ListPhoneCertifyHistory in its own transaction
+// it is called after transaction of event was ended, so failure of record is only logged
+func (au *authUsecase) recordPhoneCertifyEvent(ctx context.Context, pn, _type string, channel domain.CertifyChannel) {
+	_tx, err := au.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
+	if err != nil {
+		log.Printf("failed to begin transaction to record phone certify event %s, err: %v", _type, err)
+		return
+	}
+
+	now := au.clock.Now()
+	pce := &domain.PhoneCertifyEvent{PhoneNumber: domain.String(pn), Type: domain.String(_type), OccurredAt: &now}
+	if channel != "" {
+		pce.Channel = domain.String(string(channel))
+	}
+	if err = au.phoneCertifyEventRepository.Store(_tx, pce); err != nil {
+		log.Printf("failed to record phone certify event %s of %s, err: %v", _type, domain.MaskPhoneNumber(pn), err)
+		_ = au.txHandler.Rollback(_tx)
+		return
+	}
+	_ = au.txHandler.Commit(_tx)
+}
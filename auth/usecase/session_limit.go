@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// policy value of login exceeding MaxParentSessions, which is set in SessionLimitPolicy of config
+const (
+	sessionLimitPolicyReject      = "reject"
+	sessionLimitPolicyEvictOldest = "evict_oldest"
+)
+
+// sessionLimitPolicies is set of policy of session limit, used for validating config
+var sessionLimitPolicies = map[string]bool{
+	sessionLimitPolicyReject:      true,
+	sessionLimitPolicyEvictOldest: true,
+}
+
+// activeSessionSince return time after which used session is regarded as active (same as in CountActiveSessions)
+func (au *authUsecase) activeSessionSince() time.Time {
+	return au.clock.Now().Add(-au.myCfg.AccessTokenDuration())
+}
+
+// enforceSessionLimit method make room for new active session of parent within MaxParentSessions, by policy of config
+// return domain.UsecaseError with 409 if policy is reject, or session evicted (least recently used first) if evict_oldest
+// limit is advisory, since token is stateless: evicting delete session row only, and its token is valid until expired
+// err is domain.UsecaseError, and transaction must be rolled back by caller on err
+func (au *authUsecase) enforceSessionLimit(_tx tx.Context, parentUUID string) (evicted []domain.ParentSession, err error) {
+	max := au.myCfg.MaxParentSessions()
+	if max <= 0 {
+		return
+	}
+
+	since := au.activeSessionSince()
+	count, err := au.parentSessionRepository.CountUsedSince(_tx, parentUUID, since)
+	if err != nil {
+		err = errors.Wrap(err, "CountUsedSince return unexpected error")
+		return nil, domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+	if count < max {
+		return
+	}
+
+	if au.myCfg.SessionLimitPolicy() == sessionLimitPolicyReject {
+		err = errors.Errorf("parent can't be logged in to more than %d device at the same time", max)
+		return nil, domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict, Code: domain.TooManySessions}
+	}
+
+	// session more than max (ex, max is lowered) is evicted together, so that parent has max session after login
+	if evicted, err = au.parentSessionRepository.GetLeastRecentlyUsedSince(_tx, parentUUID, since, count-max+1); err != nil {
+		err = errors.Wrap(err, "GetLeastRecentlyUsedSince return unexpected error")
+		return nil, domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+	}
+	for _, s := range evicted {
+		switch err = au.parentSessionRepository.Delete(_tx, domain.StringValue(s.UUID)); err.(type) {
+		case nil, domain.ErrRowNotExist:
+			break
+		default:
+			err = errors.Wrap(err, "session Delete return unexpected error")
+			return nil, domain.UsecaseError{UsecaseErr: err, Status: http.StatusInternalServerError}
+		}
+	}
+	log.Printf("%d session of parent %s is evicted to keep session limit %d", len(evicted), parentUUID, max)
+	return evicted, nil
+}
+
+// parentSessionsEvicted handle event that session of parent is evicted by login of other device, by notifying to phone
+func (au *authUsecase) parentSessionsEvicted(pn string, evicted []domain.ParentSession) {
+	if pn == "" || len(evicted) == 0 {
+		return
+	}
+
+	content := fmt.Sprintf("[육아는 처음이지] 새로운 기기에서 로그인되어, 가장 오래 사용하지 않은 기기 %d대의 세션이 정리되었습니다.", len(evicted))
+	if _, err := au.messageAgency.SendSMSToOne(pn, content); err != nil {
+		log.Printf("failed to send session eviction notification to %s, err: %v", domain.MaskPhoneNumber(pn), err)
+	}
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// isTestPhone method return if pn is test phone number, which is certified with fixed code without sending message
+// (test phone is used by app-store review & E2E test, and not allowed in production deployment)
+func (au *authUsecase) isTestPhone(pn string) bool {
+	for _, testPN := range au.myCfg.TestPhoneNumbers() {
+		if testPN == pn {
+			return true
+		}
+	}
+	return false
+}
+
+// newCertifyCode method return new certify code to be sent to pn, which is fixed code if pn is test phone
+func (au *authUsecase) newCertifyCode(pn string) string {
+	if au.isTestPhone(pn) {
+		return strings.ToUpper(au.myCfg.TestPhoneCertifyCode())
+	}
+	return (&domain.ParentPhoneCertify{}).GenerateCertifyCode(au.myCfg.CertifyCodeCharset(), au.myCfg.CertifyCodeLength())
+}
+
+// isCorrectCertifyCode method return if code is certify code of ppc, or fixed code if phone of ppc is test phone
+// fixed code is accepted though code stored in row is different, since row may be stored before phone is allowed
+func (au *authUsecase) isCorrectCertifyCode(ppc domain.ParentPhoneCertify, code string) bool {
+	if ppc.IsCorrectCertifyCode(code) {
+		return true
+	}
+	if !au.isTestPhone(domain.StringValue(ppc.PhoneNumber)) {
+		return false
+	}
+	fixed := domain.ParentPhoneCertify{CertifyCode: domain.String(strings.ToUpper(au.myCfg.TestPhoneCertifyCode()))}
+	return fixed.IsCorrectCertifyCode(code)
+}
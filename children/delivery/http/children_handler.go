@@ -22,6 +22,9 @@ type childrenHandler struct {
 type jwtHandler interface {
 	// ParseUUIDFromToken parse token & return token payload and type
 	ParseUUIDFromToken(c *gin.Context)
+
+	// RequireCertifiedAccount reject request of account without certified phone, used after ParseUUIDFromToken
+	RequireCertifiedAccount(c *gin.Context)
 }
 
 // validator is interface used for validating struct value
@@ -37,18 +40,18 @@ func NewChildrenHandler(r *gin.Engine, cu domain.ChildrenUsecase, v validator, j
 		jwtHandler: jh,
 	}
 
-	r.POST("parents/uuid/:parent_uuid/children", h.jwtHandler.ParseUUIDFromToken, h.CreateNewChildren)
+	r.POST("parents/uuid/:parent_uuid/children", h.jwtHandler.ParseUUIDFromToken, h.jwtHandler.RequireCertifiedAccount, h.CreateNewChildren)
 }
 
 func (ch *childrenHandler) CreateNewChildren(c *gin.Context) {
 	req := new(createNewChildrenRequest)
 	if err := ch.bindRequest(req, c); err != nil {
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		c.JSON(http.StatusBadRequest, badRequestResp(c, err))
 		return
 	}
 
 	if c.GetString("uuid") != req.ParentUUID {
-		c.JSON(http.StatusForbidden, defaultResp(http.StatusForbidden, 0, "you can't access with that uuid token"))
+		c.JSON(http.StatusForbidden, localizedResp(c, http.StatusForbidden, 0, "you can't access with that uuid token"))
 		return
 	}
 
@@ -60,7 +63,7 @@ func (ch *childrenHandler) CreateNewChildren(c *gin.Context) {
 
 	if t, err := time.Parse("2006-01-02", req.Birth); err != nil {
 		err = errors.Wrap(err, "failed to parse birth time string")
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		c.JSON(http.StatusBadRequest, badRequestResp(c, err))
 		return
 	} else {
 		chi.Birth = domain.Time(t)
@@ -84,14 +87,13 @@ func (ch *childrenHandler) CreateNewChildren(c *gin.Context) {
 
 	switch uuid, err := ch.cUsecase.CreateNewChildren(c.Request.Context(), chi, profile); tErr := err.(type) {
 	case nil:
-		resp := defaultResp(http.StatusCreated, 0, "succeed to create new children")
-		resp["children_uuid"] = uuid
+		resp := createNewChildrenResponse{response: defaultResp(http.StatusCreated, 0, "succeed to create new children")}
+		resp.ChildrenUUID = uuid
 		c.JSON(http.StatusCreated, resp)
 	case domain.UsecaseError:
-		c.JSON(tErr.Status, defaultResp(tErr.Status, tErr.Code, tErr.Error()))
+		c.JSON(tErr.Status, usecaseErrorResp(c, tErr))
 	default:
-		msg := errors.Wrap(err, "CreateNewChildren return unexpected error").Error()
-		c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, msg))
+		c.JSON(http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "CreateNewChildren return unexpected error")))
 	}
 	return
 }
@@ -108,12 +110,3 @@ func (ch *childrenHandler) bindRequest(req interface {
 	}
 	return nil
 }
-
-// defaultResp return response have status, code, message inform
-func defaultResp(status, code int, msg string) (resp gin.H) {
-	resp = gin.H{}
-	resp["status"] = status
-	resp["code"] = code
-	resp["message"] = msg
-	return
-}
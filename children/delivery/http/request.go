@@ -8,11 +8,11 @@ import (
 
 // createNewChildrenRequest is request for childrenHandler.CreateNewChildren
 type createNewChildrenRequest struct {
-	ParentUUID    string                `uri:"parent_uuid" validate:"required"`
+	ParentUUID    string                `uri:"parent_uuid" json:"-" validate:"required"`
 	Name          string                `form:"name" json:"name" validate:"required,max=20"`
 	Birth         string                `form:"birth" json:"birth" validate:"required,max=20"`
 	Sex           string                `form:"sex" json:"sex" validate:"required,max=20,oneof=male female"`
-	Profile       *multipart.FileHeader `form:"profile"`
+	Profile       *multipart.FileHeader `form:"profile" json:"-"`
 	ProfileBase64 string                `json:"profile_base64"`
 }
 
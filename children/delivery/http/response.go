@@ -0,0 +1,83 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/locale"
+)
+
+// response is response envelope having status, code, message inform, embedded in every response of handler
+// response having more field embed it, to keep status, code, message field in top level of body
+type response struct {
+	Status  int    `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+
+	// Key is stable key of error, which message is localized with (set only in error response)
+	Key string `json:"key,omitempty"`
+
+	// Ref is reference id of internal error, set only in response of internal error
+	Ref string `json:"ref,omitempty"`
+}
+
+// defaultResp return response have status, code, message inform
+func defaultResp(status, code int, msg string) response {
+	return response{Status: status, Code: code, Message: msg}
+}
+
+// localizedResp return response with message localized in locale negotiated from Accept-Language of c
+// msg is used as it is if catalog doesn't have message about key of status & code
+func localizedResp(c *gin.Context, status, code int, msg string) response {
+	key := domain.ErrorKey(status, code)
+	if localized, ok := locale.Message(locale.Negotiate(c.GetHeader("Accept-Language")), key); ok {
+		msg = localized
+	}
+	resp := defaultResp(status, code, msg)
+	resp.Key = key
+	return resp
+}
+
+// badRequestResp return 400 response about err returned from binding or validating request, with localized message
+func badRequestResp(c *gin.Context, err error) response {
+	if msg, ok := locale.ValidationMessage(locale.Negotiate(c.GetHeader("Accept-Language")), err); ok {
+		resp := defaultResp(http.StatusBadRequest, 0, msg)
+		resp.Key = domain.ErrorKey(http.StatusBadRequest, 0)
+		return resp
+	}
+	return localizedResp(c, http.StatusBadRequest, 0, err.Error())
+}
+
+// internalErrorResp log err with generated reference id & return 500 response having the id instead of err,
+// so that internal error (ex, DB error text) is not exposed to client, and operator find log with the id
+func internalErrorResp(c *gin.Context, err error) response {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	ref := hex.EncodeToString(b)
+
+	log.Printf("internal error, ref: %s, err: %v", ref, err)
+	resp := localizedResp(c, http.StatusInternalServerError, 0, "internal error,")
+	resp.Message += " ref: " + ref
+	resp.Ref = ref
+	return resp
+}
+
+// usecaseErrorResp return localized response about domain.UsecaseError, hiding error with internalErrorResp if status is 5xx
+func usecaseErrorResp(c *gin.Context, err domain.UsecaseError) response {
+	if err.Status >= http.StatusInternalServerError {
+		resp := internalErrorResp(c, err)
+		resp.Status, resp.Code, resp.Key = err.Status, err.Code, domain.ErrorKey(err.Status, err.Code)
+		return resp
+	}
+	return localizedResp(c, err.Status, err.Code, err.Error())
+}
+
+// createNewChildrenResponse is response for childrenHandler.CreateNewChildren
+type createNewChildrenResponse struct {
+	response
+	ChildrenUUID string `json:"children_uuid,omitempty"`
+}
@@ -52,7 +52,7 @@ type childrenUsecaseConfig interface {
 
 // txHandler is used for handling transaction to begin & commit or rollback
 type txHandler interface {
-	// BeginTx method start transaction (get option from ctx)
+	// BeginTx method start transaction (option is get from ctx with tx.OptionsFromContext)
 	BeginTx(ctx context.Context, opts interface{}) (tx tx.Context, err error)
 
 	// Commit method commit transaction
@@ -69,7 +69,7 @@ type s3Agency interface {
 }
 
 func (cu *childrenUsecase) CreateNewChildren(ctx context.Context, c *domain.Children, profile []byte) (uuid string, err error) {
-	_tx, err := cu.txHandler.BeginTx(ctx, nil)
+	_tx, err := cu.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
 	if err != nil {
 		err = errors.Wrap(err, "failed to begin transaction")
 		return
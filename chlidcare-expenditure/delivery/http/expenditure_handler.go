@@ -23,6 +23,9 @@ type validator interface {
 type jwtHandler interface {
 	// ParseUUIDFromToken parse token & return token payload and type
 	ParseUUIDFromToken(c *gin.Context)
+
+	// RequireCertifiedAccount reject request of account without certified phone, used after ParseUUIDFromToken
+	RequireCertifiedAccount(c *gin.Context)
 }
 
 // NewExpenditureHandler vil initialize the expenditure endpoint
@@ -33,13 +36,13 @@ func NewExpenditureHandler(r *gin.Engine, eu domain.ExpenditureUsecase, v valida
 		jwtHandler: jh,
 	}
 
-	r.POST("expenditure/registration", h.jwtHandler.ParseUUIDFromToken, h.ExpenditureRegistration)
+	r.POST("expenditure/registration", h.jwtHandler.ParseUUIDFromToken, h.jwtHandler.RequireCertifiedAccount, h.ExpenditureRegistration)
 }
 
 func (eh *expenditureHandler) ExpenditureRegistration(c *gin.Context) {
 	req := new(expenditureRegistration)
 	if err := eh.bindRequest(req, c); err != nil {
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		c.JSON(http.StatusBadRequest, badRequestResp(c, err))
 		return
 	}
 
@@ -56,10 +59,9 @@ func (eh *expenditureHandler) ExpenditureRegistration(c *gin.Context) {
 		resp := defaultResp(http.StatusOK, 0, "succeed to registration expenditure")
 		c.JSON(http.StatusOK, resp)
 	case domain.UsecaseError:
-		c.JSON(tErr.Status, defaultResp(tErr.Status, tErr.Code, tErr.Error()))
+		c.JSON(tErr.Status, usecaseErrorResp(c, tErr))
 	default:
-		msg := errors.Wrap(err, "ExpenditureRegistration return unexpected error").Error()
-		c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, msg))
+		c.JSON(http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ExpenditureRegistration return unexpected error")))
 	}
 	return
 }
@@ -76,12 +78,3 @@ func (eh *expenditureHandler) bindRequest(req interface {
 	}
 	return nil
 }
-
-// defaultResp return response have status, code, message inform
-func defaultResp(status, code int, msg string) (resp gin.H) {
-	resp = gin.H{}
-	resp["status"] = status
-	resp["code"] = code
-	resp["message"] = msg
-	return
-}
@@ -0,0 +1,17 @@
+package mysql
+
+import (
+	"strings"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// duplicateKey return domain.DuplicateKey of key in table, which is name of key parsed from entry duplicate error
+// name is prefixed with table since MySQL 8.0.19 (ex, expenditure_baby_tag.PRIMARY), so the prefix is trimmed
+// tables of expenditure have no unique key except primary key, so other key is domain.DuplicateKeyUnknown
+func duplicateKey(table, key string) domain.DuplicateKey {
+	if strings.TrimPrefix(key, table+".") == "PRIMARY" {
+		return domain.DuplicateKeyPrimary
+	}
+	return domain.DuplicateKeyUnknown
+}
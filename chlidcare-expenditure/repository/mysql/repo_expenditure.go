@@ -125,7 +125,7 @@ func (er *expenditureRepository) Store(ctx tx.Context, e *domain.Expenditure, ba
 		case mysqlerr.ER_DUP_ENTRY:
 			err = errors.Wrap(err, "failed to insert expenditure_baby_tag")
 			_, key := er.sqlMsgParser.EntryDuplicate(tErr.Message)
-			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: key}
+			err = domain.ErrEntryDuplicate{RepoErr: err, DuplicateKey: duplicateKey("expenditure_baby_tag", key)}
 			return
 		default:
 			err = errors.Wrap(err, "insert expenditure_baby_tag unexpected mysql error")
@@ -34,7 +34,7 @@ func ExpenditureUsecase(
 
 // txHandler is used for handling transaction to begin & commit or rollback
 type txHandler interface {
-	// BeginTx method start transaction (get option from ctx)
+	// BeginTx method start transaction (option is get from ctx with tx.OptionsFromContext)
 	BeginTx(ctx context.Context, opts interface{}) (tx tx.Context, err error)
 
 	// Commit method commit transaction
@@ -49,7 +49,7 @@ type elasticSearch interface {
 }
 
 func (eu *expenditureUsecase) ExpenditureRegistration(ctx context.Context, req *domain.Expenditure, babyUUIDs []string) (err error) {
-	_tx, err := eu.txHandler.BeginTx(ctx, nil)
+	_tx, err := eu.txHandler.BeginTx(ctx, tx.OptionsFromContext(ctx))
 	if err != nil {
 		err = errors.Wrap(err, "failed to begin transaction")
 		return
@@ -87,7 +87,7 @@ func (eu *expenditureUsecase) ExpenditureRegistration(ctx context.Context, req *
 		}
 	case domain.ErrEntryDuplicate:
 		switch tErr.DuplicateKey {
-		case "expenditure_baby_tag.PRIMARY":
+		case domain.DuplicateKeyPrimary:
 			err = errors.New("expenditure_baby_tag Store duplicate value")
 			err = domain.UsecaseError{UsecaseErr: err, Status: http.StatusConflict}
 			_ = eu.txHandler.Rollback(_tx)
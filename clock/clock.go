@@ -0,0 +1,47 @@
+// Package clock provide clock returning current time, injected into component deciding with time
+// so that time-dependent decision (ex, expiry, cooldown) can be tested by advancing fake clock without sleeping
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// realClock is clock returning current time with time.Now
+type realClock struct{}
+
+// Real return clock returning current time with time.Now, used in production
+func Real() realClock { return realClock{} }
+
+// Now method return current time
+func (realClock) Now() time.Time { return time.Now() }
+
+// fakeClock is clock returning time set manually, used in test
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Fake return fake clock returning t until it is advanced or set
+func Fake(t time.Time) *fakeClock { return &fakeClock{now: t} }
+
+// Now method return time set in fake clock
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+// Advance method advance time of fake clock by d
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.now = fc.now.Add(d)
+}
+
+// Set method set time of fake clock to t
+func (fc *fakeClock) Set(t time.Time) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.now = t
+}
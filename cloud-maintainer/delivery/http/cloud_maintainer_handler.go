@@ -32,7 +32,7 @@ func NewCloudMaintainerHandler(r *gin.Engine, cu domain.CloudMaintainerUsecase,
 func (ch *cloudMaintainerHandler) ContainerRedeploy(c *gin.Context) {
 	req := new(containerRedeployRequest)
 	if err := ch.bindRequest(req, c); err != nil {
-		c.JSON(http.StatusBadRequest, defaultResp(http.StatusBadRequest, 0, err.Error()))
+		c.JSON(http.StatusBadRequest, badRequestResp(c, err))
 		return
 	}
 
@@ -41,10 +41,9 @@ func (ch *cloudMaintainerHandler) ContainerRedeploy(c *gin.Context) {
 		resp := defaultResp(http.StatusOK, 0, "succeed to container redeploy")
 		c.JSON(http.StatusOK, resp)
 	case domain.UsecaseError:
-		c.JSON(tErr.Status, defaultResp(tErr.Status, tErr.Code, tErr.Error()))
+		c.JSON(tErr.Status, usecaseErrorResp(c, tErr))
 	default:
-		msg := errors.Wrap(err, "ContainerRedeploy return unexpected error").Error()
-		c.JSON(http.StatusInternalServerError, defaultResp(http.StatusInternalServerError, 0, msg))
+		c.JSON(http.StatusInternalServerError, internalErrorResp(c, errors.Wrap(err, "ContainerRedeploy return unexpected error")))
 	}
 	return
 }
@@ -61,12 +60,3 @@ func (ch *cloudMaintainerHandler) bindRequest(req interface {
 	}
 	return nil
 }
-
-// defaultResp return response have status, code, message inform
-func defaultResp(status, code int, msg string) (resp gin.H) {
-	resp = gin.H{}
-	resp["status"] = status
-	resp["code"] = code
-	resp["message"] = msg
-	return
-}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// AuditLogRepository is repository interface about AuditLog model
+type AuditLogRepository interface {
+	GetByActorUUIDOrTarget(ctx tx.Context, actorUUID string, targets []string) ([]AuditLog, error)
+	Store(ctx tx.Context, al *AuditLog) error
+}
+
+// action value of AuditLog model
+const (
+	AuditActionForceCertifyPhone  = "force_certify_phone"
+	AuditActionExportParentData   = "export_parent_data"
+	AuditActionUnlockParent       = "unlock_parent"
+	AuditActionSetMaintenanceMode = "set_maintenance_mode"
+)
+
+// AuditTargetPhoneNumberPrefix is prefix of AuditLog target about phone number, followed by hash of phone number
+// audit log isn't encrypted, so plaintext phone number must not be kept in target
+const AuditTargetPhoneNumberPrefix = "phone:"
+
+// AuditLog is model represent record of privileged action, such as action performed by admin
+type AuditLog struct {
+	ID        *int64     `db:"id"`
+	ActorUUID *string    `db:"actor_uuid" validate:"not_empty,uuid=parent"`
+	Action    *string    `db:"action" validate:"not_empty,max=50"`
+	Target    *string    `db:"target" validate:"max=100"`
+	CreatedAt *time.Time `db:"created_at"`
+}
+
+// TableName return table name about AuditLog model
+func (al AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// Schema return schema SQL about AuditLog model
+// actor_uuid has no foreign key, so that audit log remain after actor account is deleted
+func (al AuditLog) Schema() string {
+	return `CREATE TABLE audit_log (
+		id         BIGINT       NOT NULL AUTO_INCREMENT,
+		actor_uuid CHAR(11)     NOT NULL,
+		action     VARCHAR(50)  NOT NULL,
+		target     VARCHAR(100),
+		created_at DATETIME     NOT NULL,
+		PRIMARY KEY (id),
+		INDEX (actor_uuid)
+	);`
+}
@@ -2,30 +2,67 @@ package domain
 
 import (
 	"context"
+	cryptoRand "crypto/rand"
+	"crypto/subtle"
 	"fmt"
+	"math/big"
 	"math/rand"
-	"strconv"
+	"strings"
 	"time"
 
+	"golang.org/x/text/unicode/norm"
+
 	"github.com/MyFirstBabyTime/Server/tx"
 )
 
 // AuthUsecase is abstract interface about usecase layer using in delivery layer
 type AuthUsecase interface {
-	// SendCertifyCodeToPhone method send certify code to phone with pn(phone number)
-	SendCertifyCodeToPhone(ctx context.Context, pn string) error
+	// SendCertifyCodeToPhone method send certify code to phone with pn(phone number) through channel in dest
+	// ownerUUID is uuid of authenticated requester for re-verification of linked phone (empty in sign up flow)
+	// code can be sent to phone linked to parent only if ownerUUID is uuid of that parent, and certified is kept
+	// purpose decide template of message, which is CertifyPurposeSignUp if empty
+	SendCertifyCodeToPhone(ctx context.Context, pn, ownerUUID string, purpose CertifyPurpose, dest CertifyCodeDestination) error
 
 	// CertifyPhoneWithCode method certify phone with certify code
-	CertifyPhoneWithCode(ctx context.Context, pn string, code int64) error
+	// return resulting status of phone, so that client branch by whether it is linked to parent (login vs sign up)
+	CertifyPhoneWithCode(ctx context.Context, pn, code string) (status PhoneCertifyStatus, err error)
+
+	// RestartCertification method remove uncertified state of phone, so that certification start over clean
+	// certified phone (waiting for sign up) isn't removed, and restarting is limited per phone in window
+	RestartCertification(ctx context.Context, pn string) error
+
+	// VerifyCertifyCodeOnly method return if certify code is matched without changing certified state of phone
+	// only phone linked to parent of uuid is verified, and mismatch is limited in window
+	VerifyCertifyCodeOnly(ctx context.Context, uuid, pn, code string) (matched bool, err error)
+
+	// CheckPhoneEligibility method check if phone can be used for sign up & return reason if not eligible
+	CheckPhoneEligibility(ctx context.Context, pn string) (eligible bool, reason string, err error)
+
+	// ValidateSignUpParent method check ID & phone as SignUpParent do without writing & return reason by field
+	// field with empty value is not checked, and no field is in invalid if sign up is available with them
+	ValidateSignUpParent(ctx context.Context, id, pn string) (invalid map[string]string, err error)
+
+	// ReserveParentID method hold ID for a while in multi-step sign up, so that another client can't sign up with it
+	// returned reservation must be given in SignUpParent, and reservation expire at expiresAt automatically
+	// clientIP is IP of client reserving, which is limited in number of reservation since route isn't authenticated
+	ReserveParentID(ctx context.Context, id, clientIP string) (reservation string, expiresAt time.Time, err error)
 
 	// SignUpParent method create new parent auth with ParentAuth, ParentPhoneCertify model & profile multipart
+	// if ID is reserved, reservation returned from ReserveParentID must be given (ignored if ID is not reserved)
 	SignUpParent(ctx context.Context, pi struct {
 		*ParentAuth
 		*ParentPhoneCertify
-	}, profile []byte) (uuid string, err error)
+	}, profile []byte, reservation string) (uuid string, err error)
+
+	// LoginParentAuth method login parent auth from device & return logged ParentAuth model, token
+	// session is recorded with device inform, and new device is notified if configured
+	// if login from unrecognized device require verification, token is empty & return ID of verification completed
+	// with certify code sent to parent phone in CompleteDeviceVerification
+	LoginParentAuth(ctx context.Context, id, pw string, device DeviceInfo) (uuid, token, verificationID string, err error)
 
-	// LoginParentAuth method login parent auth & return logged ParentAuth model, token
-	LoginParentAuth(ctx context.Context, id, pw string) (uuid, token string, err error)
+	// CompleteDeviceVerification method complete login waiting for device verification with certify code
+	// & return uuid, token like LoginParentAuth. device is recognized in later login after it is completed
+	CompleteDeviceVerification(ctx context.Context, verificationID, code string) (uuid, token string, err error)
 
 	// GetParentInformByID method get ParentAuth & ParentPhoneCertify model inform by parent ID
 	GetParentInformByID(ctx context.Context, id string) (struct {
@@ -33,8 +70,216 @@ type AuthUsecase interface {
 		ParentPhoneCertify
 	}, error)
 
+	// GetParentInformByUUID method get ParentAuth & ParentPhoneCertify model inform by parent uuid
+	GetParentInformByUUID(ctx context.Context, uuid string) (struct {
+		ParentAuth
+		ParentPhoneCertify
+	}, error)
+
 	// UpdateParentInform method update ParentAuth model inform & profile image with parent uuid
 	UpdateParentInform(ctx context.Context, uuid string, pa *ParentAuth, profile []byte) (err error)
+
+	// ChangeParentID method change ID of parent after verifying password
+	ChangeParentID(ctx context.Context, uuid, newID, pw string) (err error)
+
+	// ChangeParentPW method change password of parent after verifying current password
+	// token issued before password change is not available after that
+	ChangeParentPW(ctx context.Context, uuid, pw, newPW string) (err error)
+
+	// VerifyParentPassword method return if password of parent is matched, without issuing token or changing parent
+	// mismatch is counted apart from login lockout, and verification is rejected while mismatch in window exceed limit
+	VerifyParentPassword(ctx context.Context, uuid, pw string) (matched bool, err error)
+
+	// GetPhoneCertifyStatuses method get certify status of phone numbers in one query (used by admin)
+	GetPhoneCertifyStatuses(ctx context.Context, pns []string) (statuses []PhoneCertifyStatus, err error)
+
+	// CheckParentAccount method check if parent account is available (not suspended) for token issued at iat
+	// & return status of account (ex, role). token issued before password change is not available
+	CheckParentAccount(ctx context.Context, uuid string, iat time.Time) (status ParentAccountStatus, err error)
+
+	// SuspendParent method suspend parent account with reason until time (suspend permanently if until is zero)
+	SuspendParent(ctx context.Context, uuid, reason string, until time.Time) (err error)
+
+	// UnsuspendParent method unsuspend parent account
+	UnsuspendParent(ctx context.Context, uuid string) (err error)
+
+	// LinkPhoneToParent method link phone to parent having no phone (ex, signed up with social login) with certify code
+	// certify code must be sent to phone with SendCertifyCodeToPhone before, and not used yet
+	LinkPhoneToParent(ctx context.Context, uuid, pn, code string) error
+
+	// SendStepUpCode method send new certify code for step-up to certified phone linked to parent already authenticated
+	SendStepUpCode(ctx context.Context, uuid string) (err error)
+
+	// StepUpWithPhoneCode method verify certify code sent to linked phone of parent already authenticated
+	// & return short-lived token having TokenScopeElevated, required in sensitive request (ex, change ID)
+	// certify code must be sent with SendStepUpCode before, and it is replaced after verified whether matched or not
+	// mismatch is counted with re-verification, and rejected with TooManyCodeAttempts over CodeVerifyAttemptLimit
+	StepUpWithPhoneCode(ctx context.Context, uuid, code string) (token string, err error)
+
+	// GetParentPhoneCertification method return certify status of phone linked to parent (self-service)
+	GetParentPhoneCertification(ctx context.Context, uuid string) (status PhoneCertifyStatus, err error)
+
+	// CancelParentPhoneCertification method invalidate certify code sent to phone linked to parent (ex, by hijacker)
+	// certified state of phone is kept, so parent keep using the phone without certifying again
+	CancelParentPhoneCertification(ctx context.Context, uuid string) error
+
+	// BeginParentPasskeyRegistration method begin registration of passkey to parent already signed up
+	// return options of credential creation passed to authenticator & ID of ceremony used in finish
+	BeginParentPasskeyRegistration(ctx context.Context, uuid string) (options []byte, ceremonyID string, err error)
+
+	// FinishParentPasskeyRegistration method verify response of authenticator & store passkey credential of parent
+	FinishParentPasskeyRegistration(ctx context.Context, uuid, ceremonyID string, response []byte) error
+
+	// BeginParentPasskeyLogin method begin login with passkey of parent having id
+	// return options of credential assertion passed to authenticator & ID of ceremony used in finish
+	BeginParentPasskeyLogin(ctx context.Context, id string) (options []byte, ceremonyID string, err error)
+
+	// FinishParentPasskeyLogin method verify assertion of authenticator & return uuid, token like LoginParentAuth
+	FinishParentPasskeyLogin(ctx context.Context, ceremonyID string, response []byte, device DeviceInfo) (uuid, token string, err error)
+
+	// ListParents method return page of parent list at cursor, with cursor of next & prev page (used by admin)
+	ListParents(ctx context.Context, cursor string, limit int) (parents []ParentAuth, next, prev string, err error)
+
+	// ListParentSessions method return page of session list of parent at cursor, with cursor of next & prev page
+	ListParentSessions(ctx context.Context, uuid, cursor string, limit int) (sessions []ParentSession, next, prev string, err error)
+
+	// CountActiveSessions method return count of active session of parent, used in last access token duration
+	CountActiveSessions(ctx context.Context, uuid string) (count int, err error)
+
+	// ExportParentData method gather all data about parent (except password hash) into one document
+	// export is recorded in audit log, and limited to once in interval since it is expensive
+	ExportParentData(ctx context.Context, uuid string) (export ParentDataExport, err error)
+
+	// ForceCertifyPhone method certify phone without certify code by admin & record it in audit log
+	ForceCertifyPhone(ctx context.Context, adminUUID, pn string) (err error)
+
+	// UnlockParentAccount method reset failed login count & lockout of parent by admin & record it in audit log
+	UnlockParentAccount(ctx context.Context, adminUUID, uuid string) (err error)
+
+	// GetMaintenanceMode method return if maintenance mode is on, in which some operation (ex, sign up) is rejected
+	GetMaintenanceMode(ctx context.Context) (enabled bool, err error)
+
+	// SetMaintenanceMode method turn maintenance mode on or off by admin & record it in audit log
+	SetMaintenanceMode(ctx context.Context, adminUUID string, enabled bool) (err error)
+
+	// ListPhoneCertifyHistory method return page of certify event (code sent, verified or not) of phone in order of occurrence
+	ListPhoneCertifyHistory(ctx context.Context, pn, cursor string, limit int) (events []PhoneCertifyEvent, next, prev string, err error)
+
+	// GetAuthStats method return count of auth event (sign up, certify code, login) occurred in [from, to)
+	GetAuthStats(ctx context.Context, from, to time.Time) (stats AuthStats, err error)
+
+	// GetNotificationPreferences method return notification preferences of parent (default if never changed)
+	GetNotificationPreferences(ctx context.Context, uuid string) (prefs NotificationPreferences, err error)
+
+	// UpdateNotificationPreferences method change whether parent receive non-essential message
+	UpdateNotificationPreferences(ctx context.Context, uuid string, marketing bool) (prefs NotificationPreferences, err error)
+}
+
+// ParentAccountStatus is status of parent account returned from AuthUsecase.CheckParentAccount, used in access control
+type ParentAccountStatus struct {
+	Role string
+
+	// PhoneCertified represent if certified phone is linked to parent account
+	PhoneCertified bool
+}
+
+// reason value returned from AuthUsecase.CheckPhoneEligibility if phone is not eligible for sign up
+const (
+	PhoneIneligibleAlreadyInUse = "already_in_use"
+	PhoneIneligibleNotCertified = "not_certified"
+
+	// ParentIDAlreadyInUseReason is returned from AuthUsecase.ValidateSignUpParent if ID is not available
+	ParentIDAlreadyInUseReason = "already_in_use"
+)
+
+// PhoneCertifyStatus represent certify status of phone number
+type PhoneCertifyStatus struct {
+	PhoneNumber string `json:"phone_number"`
+	Exist       bool   `json:"exist"`
+	Certified   bool   `json:"certified"`
+	InUse       bool   `json:"in_use"`
+}
+
+// MaskPhoneNumber function return phone number masked except for first 3 & last 4 digit (used in log)
+func MaskPhoneNumber(pn string) string {
+	if len(pn) <= 7 {
+		return strings.Repeat("*", len(pn))
+	}
+	return pn[:3] + strings.Repeat("*", len(pn)-7) + pn[len(pn)-4:]
+}
+
+// NormalizePhoneNumber function return phone number removed surrounding space & hyphen, space between digits
+func NormalizePhoneNumber(pn string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(pn))
+}
+
+// IsValidPhoneNumber function return if phone number is 11 digits (should be normalized before)
+func IsValidPhoneNumber(pn string) bool {
+	if len(pn) != 11 {
+		return false
+	}
+	for _, r := range pn {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CertifyChannel represent channel which certify code is delivered through
+type CertifyChannel string
+
+const (
+	CertifyChannelSMS   CertifyChannel = "sms"
+	CertifyChannelVoice CertifyChannel = "voice"
+	CertifyChannelEmail CertifyChannel = "email"
+)
+
+// CertifyPurpose represent purpose which certify code is sent for, deciding template of message
+type CertifyPurpose string
+
+const (
+	CertifyPurposeSignUp             CertifyPurpose = "sign_up"
+	CertifyPurposePasswordReset      CertifyPurpose = "password_reset"
+	CertifyPurposePhoneChange        CertifyPurpose = "phone_change"
+	CertifyPurposeStepUp             CertifyPurpose = "step_up"
+	CertifyPurposeDeviceVerification CertifyPurpose = "device_verification"
+)
+
+// CertifyPurposes is every CertifyPurpose, used for validating template of each purpose
+var CertifyPurposes = []CertifyPurpose{
+	CertifyPurposeSignUp,
+	CertifyPurposePasswordReset,
+	CertifyPurposePhoneChange,
+	CertifyPurposeStepUp,
+	CertifyPurposeDeviceVerification,
+}
+
+// UncertifiedPhoneDetail is Detail of UsecaseError about uncertified phone in sign up, telling client what to do next
+type UncertifiedPhoneDetail struct {
+	// CodeSent represent if certify code was ever sent to phone
+	CodeSent bool `json:"code_sent"`
+
+	// CodeActive represent if certify code sent to phone can be verified now (not replaced or deleted)
+	CodeActive bool `json:"code_active"`
+
+	// NextAction is action client should take, which is SignUpNextActionSendCode or SignUpNextActionVerifyCode
+	NextAction string `json:"next_action"`
+}
+
+// action suggested to client in UncertifiedPhoneDetail
+const (
+	SignUpNextActionSendCode   = "send_code"
+	SignUpNextActionVerifyCode = "verify_code"
+)
+
+// CertifyCodeDestination represent where certify code is delivered to
+// preferred channel of phone (channel of last successful certification) or SMS is used if Channel is empty
+type CertifyCodeDestination struct {
+	Channel CertifyChannel
+
+	// Email is destination of CertifyChannelEmail channel
+	Email string
 }
 
 // ParentAuthRepository is repository interface about ParentAuth model
@@ -47,25 +292,61 @@ type ParentAuthRepository interface {
 		ParentAuth
 		ParentPhoneCertify
 	}, error)
+	ExistsByID(ctx tx.Context, id string) (exist bool, err error)
+	ListAfter(ctx tx.Context, cursor Cursor, limit int) ([]ParentAuth, error)
 	GetAvailableUUID(ctx tx.Context) (uuid string, err error)
 	Store(ctx tx.Context, pa *ParentAuth) error
 	Update(ctx tx.Context, pa *ParentAuth) error
 }
 
+// ParentSessionRepository is repository interface about ParentSession model
+type ParentSessionRepository interface {
+	GetByParentUUID(ctx tx.Context, parentUUID string) ([]ParentSession, error)
+	GetByParentUUIDAndDeviceID(ctx tx.Context, parentUUID, deviceID string) (ParentSession, error)
+	GetDevicelessByParentUUID(ctx tx.Context, parentUUID, userAgent, platform string) (ParentSession, error)
+	ListAfter(ctx tx.Context, parentUUID string, cursor Cursor, limit int) ([]ParentSession, error)
+	CountUsedSince(ctx tx.Context, parentUUID string, since time.Time) (int, error)
+	GetLeastRecentlyUsedSince(ctx tx.Context, parentUUID string, since time.Time, limit int) ([]ParentSession, error)
+	Store(ctx tx.Context, ps *ParentSession) error
+	Update(ctx tx.Context, ps *ParentSession) error
+	Delete(ctx tx.Context, uuid string) error
+}
+
 // ParentPhoneCertifyRepository is repository interface about ParentPhoneCertify model
 type ParentPhoneCertifyRepository interface {
 	GetByPhoneNumber(ctx tx.Context, pn string) (ParentPhoneCertify, error)
+	GetByPhoneNumbers(ctx tx.Context, pns []string) ([]ParentPhoneCertify, error)
 	Store(ctx tx.Context, ppc *ParentPhoneCertify) error
 	Update(ctx tx.Context, ppc *ParentPhoneCertify) error
+	Delete(ctx tx.Context, pn string) error
 }
 
+// role value of ParentAuth model
+const (
+	ParentRoleParent = "parent"
+	ParentRoleAdmin  = "admin"
+)
+
+// TokenScopeElevated is scope of token issued with step-up auth, required in sensitive request
+const TokenScopeElevated = "elevated"
+
 // ParentAuth is model represent parent auth using in auth domain
 type ParentAuth struct {
-	UUID       *string `db:"uuid" validate:"not_empty,uuid=parent"`
-	ID         *string `db:"id" validate:"not_empty,min=4,max=20"`
-	PW         *string `db:"pw" validate:"not_empty"`
-	Name       *string `db:"name" validate:"not_empty,max=20"`
-	ProfileUri *string `db:"profile_uri"`
+	UUID           *string    `db:"uuid" validate:"not_empty,uuid=parent"`
+	ID             *string    `db:"id" validate:"not_empty,min=4,max=20"`
+	PW             *string    `db:"pw" validate:"not_empty"`
+	Name           *string    `db:"name" validate:"not_empty,max=20"`
+	ProfileUri     *string    `db:"profile_uri"`
+	Role           *string    `db:"role" validate:"omitempty,oneof=parent admin"`
+	Suspended      *bool      `db:"suspended"`
+	SuspendReason  *string    `db:"suspend_reason" validate:"max=100"`
+	SuspendedUntil *time.Time `db:"suspended_until"`
+	IDChangedAt    *time.Time `db:"id_changed_at"`
+	PWChangedAt    *time.Time `db:"pw_changed_at"`
+
+	FailedLoginCount  *int       `db:"failed_login_count"`
+	LockedUntil       *time.Time `db:"locked_until"`
+	LockoutNotifiedAt *time.Time `db:"lockout_notified_at"`
 }
 
 // TableName return table name about ParentAuth model
@@ -81,10 +362,32 @@ func (pa ParentAuth) Schema() string {
 		pw          VARCHAR(100) NOT NULL,
 		name        VARCHAR(10)  NOT NULL,
 		profile_uri VARCHAR(100),
+		role            VARCHAR(10)  NOT NULL DEFAULT 'parent',
+		suspended       TINYINT      NOT NULL DEFAULT 0,
+		suspend_reason  VARCHAR(100),
+		suspended_until DATETIME,
+		id_changed_at   DATETIME,
+		pw_changed_at   DATETIME,
+		failed_login_count  INT NOT NULL DEFAULT 0,
+		locked_until        DATETIME,
+		lockout_notified_at DATETIME,
 		PRIMARY KEY (uuid)
 	);`
 }
 
+// IsSuspended method return if parent account is suspended at now
+func (pa ParentAuth) IsSuspended(now time.Time) bool {
+	if !BoolValue(pa.Suspended) {
+		return false
+	}
+	return pa.SuspendedUntil == nil || pa.SuspendedUntil.IsZero() || now.Before(*pa.SuspendedUntil)
+}
+
+// IsLocked method return if parent account is locked by too many failed login at now
+func (pa ParentAuth) IsLocked(now time.Time) bool {
+	return pa.LockedUntil != nil && now.Before(*pa.LockedUntil)
+}
+
 // GenerateRandomUUID method return random UUID value
 func (pa ParentAuth) GenerateRandomUUID() string {
 	rand.Seed(time.Now().UnixNano())
@@ -96,6 +399,30 @@ func (pa ParentAuth) GenerateRandomUUID() string {
 	return fmt.Sprintf("p%s", string(random))
 }
 
+// NormalizeParentID function return parent ID normalized to lower case (parent ID is case-insensitive)
+func NormalizeParentID(id string) string {
+	return strings.ToLower(id)
+}
+
+// NormalizeParentName function return parent name normalized to prevent impersonation with similar looking name
+// name is NFKC normalized (ex, fullwidth to halfwidth), stripped zero-width character & collapsed whitespace
+func NormalizeParentName(name string) string {
+	name = norm.NFKC.String(name)
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+			return -1
+		}
+		return r
+	}, name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// IsTokenRevoked method return if token issued at iat is revoked by password change
+func (pa ParentAuth) IsTokenRevoked(iat time.Time) bool {
+	return pa.PWChangedAt != nil && iat.Before(pa.PWChangedAt.Truncate(time.Second))
+}
+
 // GenerateProfileUri method return ProfileUri value with field value
 func (pa ParentAuth) GenerateProfileUri() string {
 	return fmt.Sprintf("/profiles/parents/uuid/%s", StringValue(pa.UUID))
@@ -127,11 +454,18 @@ func (pa ParentAuth) GenerateValidModel() ParentAuth {
 }
 
 // ParentPhoneCertify is model represent parent phone number using in auth domain
+// PhoneNumber is stored as hash & encrypted value of it in repository, not as plaintext
 type ParentPhoneCertify struct {
 	ParentUUID  *string `db:"parent_uuid" validate:"uuid=parent"`
 	PhoneNumber *string `db:"phone_number" validate:"not_empty,len=11"`
-	CertifyCode *int64  `db:"certify_code" validate:"not_empty,range=100000~999999"`
+	CertifyCode *string `db:"certify_code" validate:"not_empty,min=4,max=10"`
 	Certified   *bool   `db:"certified"`
+
+	// SentChannel is channel which current certify code was sent through
+	SentChannel *string `db:"sent_channel" validate:"max=10"`
+
+	// PreferredChannel is channel of last successful certification, used if channel isn't set in sending code
+	PreferredChannel *string `db:"preferred_channel" validate:"max=10"`
 }
 
 // TableName return table name about ParentPhoneNumber model
@@ -143,32 +477,56 @@ func (pn ParentPhoneCertify) TableName() string {
 func (pn ParentPhoneCertify) Schema() string {
 	return `CREATE TABLE parent_phone_certify (
 		parent_uuid  CHAR(11) UNIQUE,
-		phone_number CHAR(11) NOT NULL,
-		certify_code INT(11)  NOT NULL,
+		phone_number_hash   CHAR(64)      NOT NULL,
+		phone_number_cipher VARBINARY(64) NOT NULL,
+		certify_code VARCHAR(10) NOT NULL,
 		certified    TINYINT  NOT NULL DEFAULT 0,
-		PRIMARY KEY (phone_number),
+		sent_channel      VARCHAR(10),
+		preferred_channel VARCHAR(10),
+		PRIMARY KEY (phone_number_hash),
 		FOREIGN KEY (parent_uuid)
         	REFERENCES parent_auth(uuid)
         	ON DELETE CASCADE
 	);`
 }
 
-// GenerateCertifyCode method return CertifyCode value
-func (pn *ParentPhoneCertify) GenerateCertifyCode() int64 {
-	rand.Seed(time.Now().UnixNano())
-	is := []rune("0123456789")
-	random := make([]rune, 6)
-	for i := range random {
-		random[i] = is[rand.Intn(len(is))]
+// character set which certify code is generated from
+const (
+	CertifyCodeNumericCharset = "0123456789"
+
+	// CertifyCodeAlphanumericCharset exclude ambiguous character (O, 0, I, 1)
+	CertifyCodeAlphanumericCharset = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+)
+
+// GenerateCertifyCode method return CertifyCode value having length character randomly chosen from charset
+// crypto/rand is used instead of math/rand, so that code can't be guessed from timing
+func (pn *ParentPhoneCertify) GenerateCertifyCode(charset string, length int) string {
+	code := make([]byte, length)
+	for i := range code {
+		n, err := cryptoRand.Int(cryptoRand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			panic(fmt.Sprintf("failed to read random value from crypto/rand, err: %v", err))
+		}
+		code[i] = charset[n.Int64()]
 	}
-	v, _ := strconv.Atoi(string(random))
-	return int64(v)
+	return string(code)
+}
+
+// constantTimeCompare is comparison used in IsCorrectCertifyCode, declared as variable to be observed in test
+var constantTimeCompare = subtle.ConstantTimeCompare
+
+// IsCorrectCertifyCode method return if code is equal to CertifyCode with constant-time comparison
+// (to avoid leaking information about CertifyCode via response timing), letter case of code is ignored
+func (pn ParentPhoneCertify) IsCorrectCertifyCode(code string) bool {
+	expected := StringValue(pn.CertifyCode)
+	received := strings.ToUpper(strings.TrimSpace(code))
+	return constantTimeCompare([]byte(expected), []byte(received)) == 1
 }
 
 // GenerateValidModel method return model referenced by value with set valid value
 func (pn ParentPhoneCertify) GenerateValidModel() ParentPhoneCertify {
 	var (
-		validCertifyCode = Int64(123456)
+		validCertifyCode = String("123456")
 	)
 
 	if pn.CertifyCode == nil {
@@ -177,3 +535,103 @@ func (pn ParentPhoneCertify) GenerateValidModel() ParentPhoneCertify {
 
 	return pn
 }
+
+// DeviceInfo is device metadata of client which parent login from
+type DeviceInfo struct {
+	// DeviceID is device id supplied by client (new device is not detected if empty)
+	DeviceID  string
+	UserAgent string
+	Platform  string
+}
+
+// ParentSession is model represent login session of parent with device inform using in auth domain
+type ParentSession struct {
+	UUID       *string    `db:"uuid" validate:"not_empty,uuid=session"`
+	ParentUUID *string    `db:"parent_uuid" validate:"not_empty,uuid=parent"`
+	DeviceID   *string    `db:"device_id" validate:"max=100"`
+	UserAgent  *string    `db:"user_agent" validate:"max=255"`
+	Platform   *string    `db:"platform" validate:"max=20"`
+	CreatedAt  *time.Time `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
+
+// TableName return table name about ParentSession model
+func (ps ParentSession) TableName() string {
+	return "parent_session"
+}
+
+// Schema return schema SQL about ParentSession model
+func (ps ParentSession) Schema() string {
+	return `CREATE TABLE parent_session (
+		uuid         CHAR(11)     NOT NULL,
+		parent_uuid  CHAR(11)     NOT NULL,
+		device_id    VARCHAR(100),
+		user_agent   VARCHAR(255),
+		platform     VARCHAR(20),
+		created_at   DATETIME     NOT NULL,
+		last_used_at DATETIME     NOT NULL,
+		PRIMARY KEY (uuid),
+		INDEX (parent_uuid, device_id),
+		FOREIGN KEY (parent_uuid)
+			REFERENCES parent_auth(uuid)
+			ON DELETE CASCADE
+	);`
+}
+
+// GenerateRandomUUID method return random UUID value
+func (ps ParentSession) GenerateRandomUUID() string {
+	rand.Seed(time.Now().UnixNano())
+	is := []rune("0123456789")
+	random := make([]rune, 10)
+	for i := range random {
+		random[i] = is[rand.Intn(len(is))]
+	}
+	return fmt.Sprintf("s%s", string(random))
+}
+
+// ParentDataExport is document having all data about parent, exported for privacy law (GDPR, PIPA)
+type ParentDataExport struct {
+	ExportedAt          Timestamp                  `json:"exported_at"`
+	Parent              ParentAuthExport           `json:"parent"`
+	PhoneCertifications []ParentPhoneCertifyExport `json:"phone_certifications"`
+	Sessions            []ParentSessionExport      `json:"sessions"`
+	AuditLogs           []AuditLogExport           `json:"audit_logs"`
+}
+
+// ParentAuthExport is ParentAuth model in ParentDataExport (password hash is excluded)
+type ParentAuthExport struct {
+	UUID           string     `json:"uuid"`
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	ProfileUri     string     `json:"profile_uri,omitempty"`
+	Role           string     `json:"role"`
+	Suspended      bool       `json:"suspended"`
+	SuspendReason  string     `json:"suspend_reason,omitempty"`
+	SuspendedUntil *Timestamp `json:"suspended_until,omitempty"`
+	IDChangedAt    *Timestamp `json:"id_changed_at,omitempty"`
+	PWChangedAt    *Timestamp `json:"pw_changed_at,omitempty"`
+}
+
+// ParentPhoneCertifyExport is ParentPhoneCertify model in ParentDataExport (certify code is excluded)
+type ParentPhoneCertifyExport struct {
+	PhoneNumber string `json:"phone_number"`
+	Certified   bool   `json:"certified"`
+}
+
+// ParentSessionExport is ParentSession model in ParentDataExport
+type ParentSessionExport struct {
+	UUID       string     `json:"uuid"`
+	DeviceID   string     `json:"device_id,omitempty"`
+	UserAgent  string     `json:"user_agent,omitempty"`
+	Platform   string     `json:"platform,omitempty"`
+	CreatedAt  *Timestamp `json:"created_at"`
+	LastUsedAt *Timestamp `json:"last_used_at"`
+}
+
+// AuditLogExport is AuditLog model in ParentDataExport
+type AuditLogExport struct {
+	ActorUUID string     `json:"actor_uuid"`
+	Action    string     `json:"action"`
+	Target    string     `json:"target,omitempty"`
+	CreatedAt *Timestamp `json:"created_at"`
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// AuthEventRepository is repository interface about AuthEvent model
+type AuthEventRepository interface {
+	CountByType(ctx tx.Context, from, to time.Time) (map[string]int, error)
+	Store(ctx tx.Context, ae *AuthEvent) error
+}
+
+// type value of AuthEvent model
+const (
+	AuthEventSignUp           = "sign_up"
+	AuthEventCodeSent         = "code_sent"
+	AuthEventCertifySucceeded = "certify_succeeded"
+	AuthEventCertifyFailed    = "certify_failed"
+	AuthEventLogin            = "login"
+)
+
+// AuthEvent is model represent occurrence of auth event, which is aggregated in AuthStats for dashboard
+// event has no reference to parent, since it is used only for count
+type AuthEvent struct {
+	ID         *int64     `db:"id"`
+	Type       *string    `db:"type" validate:"not_empty,max=20"`
+	OccurredAt *time.Time `db:"occurred_at"`
+}
+
+// TableName return table name about AuthEvent model
+func (ae AuthEvent) TableName() string {
+	return "auth_event"
+}
+
+// Schema return schema SQL about AuthEvent model
+func (ae AuthEvent) Schema() string {
+	return `CREATE TABLE auth_event (
+		id          BIGINT      NOT NULL AUTO_INCREMENT,
+		type        VARCHAR(20) NOT NULL,
+		occurred_at DATETIME    NOT NULL,
+		PRIMARY KEY (id),
+		INDEX (occurred_at, type)
+	);`
+}
+
+// AuthStats is count of auth event occurred in date range, returned from AuthUsecase.GetAuthStats
+type AuthStats struct {
+	From             Timestamp `json:"from"`
+	To               Timestamp `json:"to"`
+	SignUps          int       `json:"sign_ups"`
+	CodesSent        int       `json:"codes_sent"`
+	CertifySucceeded int       `json:"certify_succeeded"`
+	CertifyFailed    int       `json:"certify_failed"`
+	Logins           int       `json:"logins"`
+}
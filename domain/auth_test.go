@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParentPhoneCertify_IsCorrectCertifyCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		certifyCode *string
+		code        string
+		expected    bool
+	}{
+		{"match", String("AB12CD"), "AB12CD", true},
+		{"match ignoring case & space", String("AB12CD"), " ab12cd ", true},
+		{"mismatch with same length", String("AB12CD"), "AB12CE", false},
+		{"mismatch with different length", String("AB12CD"), "AB12C", false},
+		{"mismatch with empty code", String("AB12CD"), "", false},
+		{"mismatch with nil certify code", nil, "AB12CD", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			constantTimeCompare = func(x, y []byte) int {
+				calls++
+				return compareWithoutHook(x, y)
+			}
+			defer func() { constantTimeCompare = compareWithoutHook }()
+
+			ppc := ParentPhoneCertify{CertifyCode: tt.certifyCode}
+			assert.Equal(t, tt.expected, ppc.IsCorrectCertifyCode(tt.code))
+			assert.Equal(t, 1, calls, "constant-time comparison must be used regardless of result")
+		})
+	}
+}
+
+// compareWithoutHook is constantTimeCompare declared in package, kept to be restored after test
+var compareWithoutHook = constantTimeCompare
+
+func TestParentPhoneCertify_GenerateCertifyCode(t *testing.T) {
+	charsets := []string{CertifyCodeNumericCharset, CertifyCodeAlphanumericCharset}
+	for _, charset := range charsets {
+		t.Run(charset, func(t *testing.T) {
+			const length, count = 6, 20000
+			var ppc ParentPhoneCertify
+
+			freq := map[rune]int{}
+			unique := map[string]bool{}
+			for i := 0; i < count; i++ {
+				code := ppc.GenerateCertifyCode(charset, length)
+				assert.Len(t, code, length)
+				for _, r := range code {
+					assert.Contains(t, charset, string(r))
+					freq[r]++
+				}
+				unique[code] = true
+			}
+
+			// every character is chosen within 20% of expected frequency (far more than deviation of uniform)
+			expected := float64(length*count) / float64(len(charset))
+			for _, r := range charset {
+				assert.InDelta(t, expected, float64(freq[r]), expected*0.2, "frequency of %c", r)
+			}
+
+			// expected number of collision among count codes is about count^2 / (2 * len(charset)^length)
+			space := 1.0
+			for i := 0; i < length; i++ {
+				space *= float64(len(charset))
+			}
+			collisions := float64(count - len(unique))
+			assert.LessOrEqual(t, collisions, 3*float64(count)*float64(count)/(2*space)+5, "too many duplicated code")
+		})
+	}
+}
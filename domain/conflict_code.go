@@ -1,18 +1,142 @@
 package domain
 
 const (
-	// use in authUsecase.SendCertifyCodeToPhone
-	PhoneAlreadyInUse = -101
+	// use in authUsecase.SendCertifyCodeToPhone (PhoneAlreadyInUse also in RestartCertification)
+	PhoneAlreadyInUse              = -101
+	UnsupportedCertifyChannel      = -102
+	InvalidPhoneNumber             = -103 // returned from authHandler before usecase call, or usecase if message can't be sent to it
+	CertifyCodeSentButNotPersisted = -104 // returned if code is sent but not stored, so that user must request code again
 
-	// use in authUsecase.CertifyPhoneWithCode
-	PhoneAlreadyCertified = -111
-	IncorrectCertifyCode  = -112
+	// use in authUsecase.CertifyPhoneWithCode (PhoneAlreadyCertified also in RestartCertification)
+	PhoneAlreadyCertified     = -111
+	IncorrectCertifyCode      = -112
+	PhoneOwnedByAnotherParent = -113
 
-	// use in authUsecase.SignUpParent
-	UncertifiedPhone     = -121
-	ParentIDAlreadyInUse = -122
+	// use in authUsecase.SignUpParent (PasswordBreached also in ChangeParentPW, ParentID* also in ReserveParentID)
+	UncertifiedPhone          = -121
+	ParentIDAlreadyInUse      = -122
+	PasswordBreached          = -123
+	TooManyAccountsForPhone   = -124
+	ParentIDReservedByAnother = -125
 
 	// use in authUsecase.LoginParentAuth
 	NotExistParentID  = -131
 	IncorrectParentPW = -132
+	AccountSuspended  = -133
+	TokenIssueFailed  = -134
+	AccountLocked     = -135
+
+	// use in authUsecase.LoginParentAuth & CompleteDeviceVerification (IncorrectCertifyCode also)
+	DeviceVerificationRequired = -136
+	DeviceVerificationExpired  = -137
+
+	// use in authUsecase.LoginParentAuth, FinishParentPasskeyLogin & CompleteDeviceVerification
+	TooManySessions = -138
+
+	// use in authUsecase.ChangeParentID
+	ParentIDChangeTooSoon = -141
+
+	// use in authUsecase.GetPhoneCertifyStatuses
+	TooManyPhoneNumbers = -151
+
+	// use in authUsecase.ExportParentData
+	DataExportTooSoon = -161
+
+	// use in authUsecase passkey method (BeginParentPasskeyRegistration, FinishParentPasskeyLogin, etc)
+	PasskeyUnavailable        = -171
+	PasskeyVerificationFailed = -172
+	PasskeyCeremonyExpired    = -173
+	NoPasskeyRegistered       = -174
+
+	// use in authUsecase.LinkPhoneToParent (PhoneAlreadyInUse, IncorrectCertifyCode also)
+	ParentPhoneAlreadyLinked = -181
+
+	// use in authUsecase.StepUpWithPhoneCode (UncertifiedPhone, IncorrectCertifyCode also) & jwt.RequireScope, jwt.RequireScopeIfPhoneCertified
+	StepUpRequired = -191
+
+	// use in authUsecase.VerifyParentPassword
+	TooManyPasswordAttempts = -201
+
+	// use in authUsecase.SignUpParent, authUsecase.SendCertifyCodeToPhone (operation disabled in maintenance mode)
+	UnderMaintenance = -211
+
+	// use in authUsecase.VerifyCertifyCodeOnly
+	TooManyCodeAttempts = -221
+
+	// use in middleware.NoRoute, middleware.NoMethod
+	RouteNotFound    = -901
+	MethodNotAllowed = -902
+
+	// use in middleware.CSRF
+	CSRFTokenMismatch = -903
 )
+
+// conflictCodeKeys is stable key of each conflict code, used for finding localized message of it
+var conflictCodeKeys = map[int]string{
+	PhoneAlreadyInUse:              "phone_already_in_use",
+	UnsupportedCertifyChannel:      "unsupported_certify_channel",
+	InvalidPhoneNumber:             "invalid_phone_number",
+	CertifyCodeSentButNotPersisted: "certify_code_not_persisted",
+	PhoneAlreadyCertified:          "phone_already_certified",
+	IncorrectCertifyCode:           "incorrect_certify_code",
+	PhoneOwnedByAnotherParent:      "phone_owned_by_another_parent",
+	UncertifiedPhone:               "uncertified_phone",
+	ParentIDAlreadyInUse:           "parent_id_already_in_use",
+	PasswordBreached:               "password_breached",
+	TooManyAccountsForPhone:        "too_many_accounts_for_phone",
+	ParentIDReservedByAnother:      "parent_id_reserved_by_another",
+	NotExistParentID:               "not_exist_parent_id",
+	IncorrectParentPW:              "incorrect_parent_pw",
+	AccountSuspended:               "account_suspended",
+	TokenIssueFailed:               "token_issue_failed",
+	AccountLocked:                  "account_locked",
+	DeviceVerificationRequired:     "device_verification_required",
+	DeviceVerificationExpired:      "device_verification_expired",
+	TooManySessions:                "too_many_sessions",
+	ParentIDChangeTooSoon:          "parent_id_change_too_soon",
+	TooManyPhoneNumbers:            "too_many_phone_numbers",
+	DataExportTooSoon:              "data_export_too_soon",
+	PasskeyUnavailable:             "passkey_unavailable",
+	PasskeyVerificationFailed:      "passkey_verification_failed",
+	PasskeyCeremonyExpired:         "passkey_ceremony_expired",
+	NoPasskeyRegistered:            "no_passkey_registered",
+	ParentPhoneAlreadyLinked:       "parent_phone_already_linked",
+	StepUpRequired:                 "step_up_required",
+	TooManyPasswordAttempts:        "too_many_password_attempts",
+	UnderMaintenance:               "under_maintenance",
+	TooManyCodeAttempts:            "too_many_code_attempts",
+	RouteNotFound:                  "route_not_found",
+	MethodNotAllowed:               "method_not_allowed",
+	CSRFTokenMismatch:              "csrf_token_mismatch",
+}
+
+// ErrorKey return stable key of error response with status & code (key of code is prior to one of status)
+func ErrorKey(status, code int) string {
+	if key, ok := conflictCodeKeys[code]; ok {
+		return key
+	}
+
+	switch {
+	case status == 400:
+		return "bad_request"
+	case status == 401:
+		return "unauthorized"
+	case status == 403:
+		return "forbidden"
+	case status == 404:
+		return "not_found"
+	case status == 405:
+		return "method_not_allowed"
+	case status == 409:
+		return "conflict"
+	case status == 429:
+		return "too_many_requests"
+	case status == 503:
+		return "service_unavailable"
+	case status == 504:
+		return "gateway_timeout"
+	case status >= 500:
+		return "internal_error"
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+)
+
+// direction value of Cursor
+const (
+	CursorDirectionNext = "next"
+	CursorDirectionPrev = "prev"
+)
+
+// Cursor represent position of page in list paginated by key, encoded to opaque string in API
+// page of next direction has items after Key, and one of prev direction has items before Key (first page if Key is empty)
+type Cursor struct {
+	Key       string `json:"k"`
+	Direction string `json:"d"`
+}
+
+// Encode method return opaque string of cursor, used in API
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// IsPrev method return if cursor is for page before Key
+func (c Cursor) IsPrev() bool {
+	return c.Direction == CursorDirectionPrev
+}
+
+// DecodeCursor function return Cursor decoded from opaque string (return cursor of first page if s is empty)
+func DecodeCursor(s string) (c Cursor, err error) {
+	if s == "" {
+		return Cursor{Direction: CursorDirectionNext}, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, errors.Wrap(err, "failed to decode cursor")
+	}
+	if err = json.Unmarshal(b, &c); err != nil {
+		return Cursor{}, errors.Wrap(err, "failed to unmarshal cursor")
+	}
+	if c.Direction != CursorDirectionNext && c.Direction != CursorDirectionPrev {
+		return Cursor{}, errors.Errorf("unknown cursor direction %s", c.Direction)
+	}
+	return
+}
+
+// Paginate function return range [from, to) of page in keys & encoded next, prev cursor of the page
+// keys must be in ascending order, and fetched with cursor & limit + 1 to know if there is more item
+// (extra item is at the end in next direction, and at the beginning in prev direction)
+func Paginate(cursor Cursor, keys []string, limit int) (from, to int, next, prev string) {
+	more := len(keys) > limit
+	if cursor.IsPrev() {
+		if to = len(keys); more {
+			from = to - limit
+		}
+		if from < to {
+			// page before cursor always has page after it, which it came from
+			next = Cursor{Key: keys[to-1], Direction: CursorDirectionNext}.Encode()
+			if more {
+				prev = Cursor{Key: keys[from], Direction: CursorDirectionPrev}.Encode()
+			}
+		}
+		return
+	}
+
+	if to = len(keys); more {
+		to = limit
+	}
+	if from < to {
+		if more {
+			next = Cursor{Key: keys[to-1], Direction: CursorDirectionNext}.Encode()
+		}
+		if cursor.Key != "" {
+			prev = Cursor{Key: keys[from], Direction: CursorDirectionPrev}.Encode()
+		}
+	}
+	return
+}
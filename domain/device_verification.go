@@ -0,0 +1,68 @@
+package domain
+
+import (
+	cryptoRand "crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// DeviceVerificationRepository is repository interface about DeviceVerification model
+type DeviceVerificationRepository interface {
+	GetByID(ctx tx.Context, id string) (DeviceVerification, error)
+	Store(ctx tx.Context, dv *DeviceVerification) error
+	Delete(ctx tx.Context, id string) error
+}
+
+// DeviceVerification is model represent login from unrecognized device waiting for certify code sent to parent phone
+// device inform is kept, so that session is recorded with device which passed password when verification is completed
+// certify code is kept in verification apart from phone, so that code of other purpose (ex, step-up) isn't replaced
+type DeviceVerification struct {
+	ID          *string    `db:"id" validate:"not_empty,len=32"`
+	ParentUUID  *string    `db:"parent_uuid" validate:"not_empty,uuid=parent"`
+	DeviceID    *string    `db:"device_id" validate:"max=100"`
+	UserAgent   *string    `db:"user_agent" validate:"max=255"`
+	Platform    *string    `db:"platform" validate:"max=20"`
+	CertifyCode *string    `db:"certify_code" validate:"not_empty,min=4,max=10"`
+	ExpiresAt   *time.Time `db:"expires_at"`
+}
+
+// TableName return table name about DeviceVerification model
+func (dv DeviceVerification) TableName() string {
+	return "device_verification"
+}
+
+// Schema return schema SQL about DeviceVerification model
+func (dv DeviceVerification) Schema() string {
+	return `CREATE TABLE device_verification (
+		id           CHAR(32)     NOT NULL,
+		parent_uuid  CHAR(11)     NOT NULL,
+		device_id    VARCHAR(100),
+		user_agent   VARCHAR(255),
+		platform     VARCHAR(20),
+		certify_code VARCHAR(10)  NOT NULL DEFAULT '',
+		expires_at   DATETIME     NOT NULL,
+		PRIMARY KEY (id),
+		FOREIGN KEY (parent_uuid)
+			REFERENCES parent_auth(uuid)
+			ON DELETE CASCADE
+	);`
+}
+
+// GenerateRandomID method return random ID value, which is unguessable since it identify verification without token
+func (dv DeviceVerification) GenerateRandomID() string {
+	b := make([]byte, 16)
+	_, _ = cryptoRand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// IsExpired method return if verification is expired at now
+func (dv DeviceVerification) IsExpired(now time.Time) bool {
+	return dv.ExpiresAt == nil || !now.Before(*dv.ExpiresAt)
+}
+
+// DeviceInfo method return device inform of login waiting for verification
+func (dv DeviceVerification) DeviceInfo() DeviceInfo {
+	return DeviceInfo{DeviceID: StringValue(dv.DeviceID), UserAgent: StringValue(dv.UserAgent), Platform: StringValue(dv.Platform)}
+}
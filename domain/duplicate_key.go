@@ -0,0 +1,19 @@
+package domain
+
+// DuplicateKey is unique key violated in ErrEntryDuplicate, mapped from name of key in DB error by repository
+// usecase switch on it rather than name of key, so that name of key (ex, with or without table prefix) stay in repository
+type DuplicateKey int
+
+// value of DuplicateKey, and unique key not mapped in repository is DuplicateKeyUnknown
+const (
+	DuplicateKeyUnknown DuplicateKey = iota
+
+	// DuplicateKeyPrimary is primary key of table (ex, phone_number_hash of parent_phone_certify)
+	DuplicateKeyPrimary
+
+	// DuplicateKeyParentID is unique key of id in parent_auth
+	DuplicateKeyParentID
+
+	// DuplicateKeyParentUUID is unique key of parent_uuid in table linked to one parent (ex, parent_phone_certify)
+	DuplicateKeyParentUUID
+)
@@ -11,7 +11,7 @@ type ErrRowNotExist struct {
 // ErrEntryDuplicate is error type & used for row not exist error
 type ErrEntryDuplicate struct {
 	RepoErr
-	DuplicateKey string
+	DuplicateKey DuplicateKey
 }
 
 // ErrNoReferencedRow is error type & used for no referenced row error
@@ -32,4 +32,7 @@ type UsecaseErr error
 type UsecaseError struct {
 	UsecaseErr
 	Status, Code int
+
+	// Detail is structured detail of error responded with it (ex, next step of client), nil if there is no detail
+	Detail interface{}
 }
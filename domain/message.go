@@ -0,0 +1,28 @@
+package domain
+
+// status value of SMSResult
+const (
+	// SMSStatusSent represent message confirmed to be sent by provider
+	SMSStatusSent = "sent"
+
+	// SMSStatusQueued represent message accepted by provider but not sent yet, which is not failure
+	SMSStatusQueued = "queued"
+
+	// SMSStatusFailed represent message failed to be sent, which is returned with error
+	SMSStatusFailed = "failed"
+)
+
+// SMSResult is result of sending SMS message through message agency
+type SMSResult struct {
+	// Status represent if message is sent, queued in provider or failed (SMSStatusSent, SMSStatusQueued, SMSStatusFailed)
+	Status string
+
+	// MsgType represent type of message sent (SMS or LMS)
+	MsgType string
+
+	// ContentLength represent number of character in message content
+	ContentLength int
+
+	// Segments represent number of segment that message content spans (accounting for GSM-7 or UCS-2 encoding)
+	Segments int
+}
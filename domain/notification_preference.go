@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// ParentNotificationPreferenceRepository is repository interface about ParentNotificationPreference model
+type ParentNotificationPreferenceRepository interface {
+	GetByParentUUID(ctx tx.Context, parentUUID string) (ParentNotificationPreference, error)
+	Store(ctx tx.Context, np *ParentNotificationPreference) error
+	Update(ctx tx.Context, np *ParentNotificationPreference) error
+}
+
+// ParentNotificationPreference is model represent whether parent receive non-essential message (ex, welcome, marketing)
+// parent not having row receive every message, and security message (ex, lockout, step-up) is sent regardless of it
+type ParentNotificationPreference struct {
+	ParentUUID *string `db:"parent_uuid" validate:"not_empty,uuid=parent"`
+	Marketing  *bool   `db:"marketing"`
+}
+
+// TableName return table name about ParentNotificationPreference model
+func (np ParentNotificationPreference) TableName() string {
+	return "parent_notification_preference"
+}
+
+// Schema return schema SQL about ParentNotificationPreference model
+func (np ParentNotificationPreference) Schema() string {
+	return `CREATE TABLE parent_notification_preference (
+		parent_uuid CHAR(11) NOT NULL,
+		marketing   TINYINT  NOT NULL DEFAULT 1,
+		PRIMARY KEY (parent_uuid),
+		FOREIGN KEY (parent_uuid)
+			REFERENCES parent_auth(uuid)
+			ON DELETE CASCADE
+	);`
+}
+
+// NotificationPreferences is notification preferences of parent returned from AuthUsecase
+// Security is always true, since security message can't be opted out
+type NotificationPreferences struct {
+	Marketing bool `json:"marketing"`
+	Security  bool `json:"security"`
+}
+
+// DefaultNotificationPreferences return preferences of parent who never changed it
+func DefaultNotificationPreferences() NotificationPreferences {
+	return NotificationPreferences{Marketing: true, Security: true}
+}
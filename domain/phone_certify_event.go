@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// PhoneCertifyEventRepository is repository interface about PhoneCertifyEvent model
+type PhoneCertifyEventRepository interface {
+	ListAfter(ctx tx.Context, pn string, cursor Cursor, limit int) ([]PhoneCertifyEvent, error)
+	Store(ctx tx.Context, pce *PhoneCertifyEvent) error
+}
+
+// type value of PhoneCertifyEvent model
+const (
+	PhoneCertifyEventSent            = "sent"
+	PhoneCertifyEventVerifySucceeded = "verify_succeeded"
+	PhoneCertifyEventVerifyFailed    = "verify_failed"
+)
+
+// PhoneCertifyEvent is model represent event in certification of phone (code sent, code verified or not),
+// which is appended only & read as history of phone in fraud investigation, unlike mutable ParentPhoneCertify row
+// event has no reference to parent_phone_certify, so that history remain after row is deleted
+type PhoneCertifyEvent struct {
+	ID          *int64     `db:"id"`
+	PhoneNumber *string    `db:"phone_number" validate:"not_empty,len=11"`
+	Type        *string    `db:"type" validate:"not_empty,max=20"`
+	Channel     *string    `db:"channel" validate:"max=10"`
+	OccurredAt  *time.Time `db:"occurred_at"`
+}
+
+// TableName return table name about PhoneCertifyEvent model
+func (pce PhoneCertifyEvent) TableName() string {
+	return "phone_certify_event"
+}
+
+// Schema return schema SQL about PhoneCertifyEvent model
+func (pce PhoneCertifyEvent) Schema() string {
+	return `CREATE TABLE phone_certify_event (
+		id           BIGINT      NOT NULL AUTO_INCREMENT,
+		phone_number CHAR(11)    NOT NULL,
+		type         VARCHAR(20) NOT NULL,
+		channel      VARCHAR(10),
+		occurred_at  DATETIME    NOT NULL,
+		PRIMARY KEY (id),
+		INDEX (phone_number, id)
+	);`
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Timestamp is time serialized in JSON as RFC3339 in UTC (ex, 2021-05-01T09:00:00Z), used as time in response
+// so that client handle only one format regardless of location of server clock & DB
+type Timestamp time.Time
+
+// TimestampOf function return Timestamp pointer of t, or nil if t is nil (used for optional time field)
+func TimestampOf(t *time.Time) *Timestamp {
+	if t == nil {
+		return nil
+	}
+	ts := Timestamp(*t)
+	return &ts
+}
+
+// MarshalJSON method marshal timestamp into RFC3339 string in UTC, truncated to second
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Time(t).UTC().Format(time.RFC3339))
+}
+
+// UnmarshalJSON method unmarshal RFC3339 string into timestamp
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return err
+	}
+	*t = Timestamp(parsed.UTC())
+	return nil
+}
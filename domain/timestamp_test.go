@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	pst := time.FixedZone("PST", -8*60*60)
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		expected string
+	}{
+		{"UTC", time.Date(2021, 5, 1, 9, 0, 0, 0, time.UTC), `"2021-05-01T09:00:00Z"`},
+		{"east of UTC converted to UTC", time.Date(2021, 5, 1, 9, 0, 0, 0, kst), `"2021-05-01T00:00:00Z"`},
+		{"west of UTC converted to UTC over date", time.Date(2021, 5, 1, 20, 30, 0, 0, pst), `"2021-05-02T04:30:00Z"`},
+		{"fraction of second truncated", time.Date(2021, 5, 1, 9, 0, 0, 999999999, time.UTC), `"2021-05-01T09:00:00Z"`},
+		{"zero time", time.Time{}, `"0001-01-01T00:00:00Z"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(Timestamp(tt.t))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+
+			var parsed Timestamp
+			assert.NoError(t, json.Unmarshal(b, &parsed))
+			assert.True(t, time.Time(parsed).Equal(tt.t.Truncate(time.Second)), "timestamp must be same after round trip")
+			assert.Equal(t, time.UTC, time.Time(parsed).Location())
+		})
+	}
+}
+
+func TestTimestampOf(t *testing.T) {
+	createdAt := time.Date(2021, 5, 1, 9, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+	resp := struct {
+		CreatedAt   *Timestamp `json:"created_at"`
+		CertifiedAt *Timestamp `json:"certified_at"`
+		ExpiresAt   *Timestamp `json:"expires_at,omitempty"`
+	}{
+		CreatedAt:   TimestampOf(&createdAt),
+		CertifiedAt: TimestampOf(nil),
+		ExpiresAt:   TimestampOf(nil),
+	}
+
+	b, err := json.Marshal(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"created_at":"2021-05-01T00:00:00Z","certified_at":null}`, string(b))
+}
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	var ts Timestamp
+	assert.NoError(t, json.Unmarshal([]byte(`"2021-05-01T09:00:00+09:00"`), &ts))
+	assert.Equal(t, time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), time.Time(ts))
+
+	assert.Error(t, json.Unmarshal([]byte(`"2021-05-01 09:00:00"`), &ts), "only RFC3339 is accepted")
+	assert.Error(t, json.Unmarshal([]byte(`1619827200`), &ts), "unix time is not accepted")
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	cryptoRand "crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/tx"
+)
+
+// ParentWebAuthnCredentialRepository is repository interface about ParentWebAuthnCredential model
+type ParentWebAuthnCredentialRepository interface {
+	GetByParentUUID(ctx tx.Context, parentUUID string) ([]ParentWebAuthnCredential, error)
+	Store(ctx tx.Context, cred *ParentWebAuthnCredential) error
+	Update(ctx tx.Context, cred *ParentWebAuthnCredential) error
+}
+
+// WebAuthnCeremonyRepository is repository interface about WebAuthnCeremony model
+type WebAuthnCeremonyRepository interface {
+	GetByID(ctx tx.Context, id string) (WebAuthnCeremony, error)
+	Store(ctx tx.Context, wc *WebAuthnCeremony) error
+	Delete(ctx tx.Context, id string) error
+}
+
+// WebAuthnUser is user of WebAuthn ceremony, which is parent with credentials registered before
+type WebAuthnUser struct {
+	// UUID is used as user handle of WebAuthn, which is stable & not including personal inform
+	UUID        string
+	ID          string
+	Name        string
+	Credentials []ParentWebAuthnCredential
+}
+
+// ParentWebAuthnCredential is model represent WebAuthn (passkey) credential public key registered by parent
+type ParentWebAuthnCredential struct {
+	ID         *string    `db:"id" validate:"not_empty,max=255"` // credential ID encoded in base64url
+	ParentUUID *string    `db:"parent_uuid" validate:"not_empty,uuid=parent"`
+	PublicKey  []byte     `db:"public_key"`
+	SignCount  *int64     `db:"sign_count"`
+	CreatedAt  *time.Time `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
+
+// TableName return table name about ParentWebAuthnCredential model
+func (pc ParentWebAuthnCredential) TableName() string {
+	return "parent_webauthn_credential"
+}
+
+// Schema return schema SQL about ParentWebAuthnCredential model
+func (pc ParentWebAuthnCredential) Schema() string {
+	return `CREATE TABLE parent_webauthn_credential (
+		id           VARCHAR(255) NOT NULL,
+		parent_uuid  CHAR(11)     NOT NULL,
+		public_key   BLOB         NOT NULL,
+		sign_count   BIGINT       NOT NULL DEFAULT 0,
+		created_at   DATETIME     NOT NULL,
+		last_used_at DATETIME,
+		PRIMARY KEY (id),
+		INDEX (parent_uuid),
+		FOREIGN KEY (parent_uuid)
+			REFERENCES parent_auth(uuid)
+			ON DELETE CASCADE
+	);`
+}
+
+// type value of WebAuthnCeremony model
+const (
+	WebAuthnCeremonyRegistration = "registration"
+	WebAuthnCeremonyLogin        = "login"
+)
+
+// WebAuthnCeremony is model represent WebAuthn ceremony in progress, having session data kept between begin & finish
+type WebAuthnCeremony struct {
+	ID         *string    `db:"id" validate:"not_empty,len=32"`
+	ParentUUID *string    `db:"parent_uuid" validate:"not_empty,uuid=parent"`
+	Type       *string    `db:"type" validate:"not_empty,oneof=registration login"`
+	Session    []byte     `db:"session"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+}
+
+// TableName return table name about WebAuthnCeremony model
+func (wc WebAuthnCeremony) TableName() string {
+	return "webauthn_ceremony"
+}
+
+// Schema return schema SQL about WebAuthnCeremony model
+func (wc WebAuthnCeremony) Schema() string {
+	return `CREATE TABLE webauthn_ceremony (
+		id          CHAR(32)    NOT NULL,
+		parent_uuid CHAR(11)    NOT NULL,
+		type        VARCHAR(20) NOT NULL,
+		session     BLOB        NOT NULL,
+		expires_at  DATETIME    NOT NULL,
+		PRIMARY KEY (id),
+		FOREIGN KEY (parent_uuid)
+			REFERENCES parent_auth(uuid)
+			ON DELETE CASCADE
+	);`
+}
+
+// GenerateRandomID method return random ID value, which is unguessable since it identify ceremony without token
+func (wc WebAuthnCeremony) GenerateRandomID() string {
+	b := make([]byte, 16)
+	_, _ = cryptoRand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// IsExpired method return if ceremony is expired at now
+func (wc WebAuthnCeremony) IsExpired(now time.Time) bool {
+	return wc.ExpiresAt == nil || !now.Before(*wc.ExpiresAt)
+}
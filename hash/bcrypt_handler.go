@@ -1,25 +1,55 @@
+// Package hash provide handler about hashing password, optionally combined with server-side pepper.
+//
+// bcryptHandler combine pepper (kept outside of DB, in config/secret) with password before hashing, if it is set.
+//
+// Migration path of pepper is like below
+//  1. set PASSWORD_PEPPER in environment variable & redeploy.
+//     new hash is generated with pepper, and hash generated before is still verified without pepper (fallback)
+//  2. whenever parent login with hash generated without pepper, CompareHashAndPW return needRehashErr,
+//     so usecase rehash password with pepper & update it (rehash on login)
+//  3. hash of parent not logged in after step 1 is not rehashed, but is kept verified without pepper
+//
+// DO NOT change or remove pepper after setting it. all hash generated with pepper can't be verified then.
 package hash
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // bcryptHandler is hash handler using bcrypt algorithm
-type bcryptHandler struct{}
+type bcryptHandler struct {
+	// pepper is server-side secret combined with password before hashing (not used if empty)
+	pepper string
+}
 
-func BcryptHandler() *bcryptHandler {
-	return &bcryptHandler{}
+func BcryptHandler(pepper string) *bcryptHandler {
+	return &bcryptHandler{
+		pepper: pepper,
+	}
 }
 
 // GenerateHashWithMinSalt generate & return hashed value from password with minimum salt
 func (bh *bcryptHandler) GenerateHashWithMinSalt(pw string) (string, error) {
-	return bh.generateHashFromPW(pw, bcrypt.MinCost)
+	return bh.generateHashFromPW(bh.combineWithPepper(pw), bcrypt.MinCost)
 }
 
 // CompareHashAndPW compare hashed value and password & return error
+// if pepper is set and hash is matched without pepper, return needRehashErr
 func (bh *bcryptHandler) CompareHashAndPW(hash, pw string) (err error) {
-	switch err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(pw)); err {
+	err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(bh.combineWithPepper(pw)))
+	if err == bcrypt.ErrMismatchedHashAndPassword && bh.pepper != "" {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(pw)) == nil {
+			return needRehashErr{errors.New("hash is generated without pepper")}
+		}
+	}
+
+	switch err {
+	case nil:
+		break
 	case bcrypt.ErrMismatchedHashAndPassword:
 		err = mismatchErr{errors.Wrap(err, "failed to CompareHashAndPassword")}
 	default:
@@ -33,9 +63,27 @@ func (bh *bcryptHandler) generateHashFromPW(pw string, salt int) (string, error)
 	return string(b), err
 }
 
+// combineWithPepper return password combined with pepper by HMAC-SHA256 (return password as it is if pepper is empty)
+// base64 encoding keep the result shorter than 72 byte, which is max length of bcrypt input
+func (bh *bcryptHandler) combineWithPepper(pw string) string {
+	if bh.pepper == "" {
+		return pw
+	}
+	mac := hmac.New(sha256.New, []byte(bh.pepper))
+	mac.Write([]byte(pw))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
 // mismatchErr is error type represent hash & password mismatch error
 type mismatchErr struct {
 	error
 }
 
 func (_ mismatchErr) Mismatch() {}
+
+// needRehashErr is error type represent hash & password is matched, but hash need to be regenerated with pepper
+type needRehashErr struct {
+	error
+}
+
+func (_ needRehashErr) NeedRehash() {}
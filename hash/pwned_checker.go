@@ -0,0 +1,66 @@
+package hash
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// pwnedRangeAPI is URL of range API of HaveIBeenPwned, to which first 5 character of SHA-1 hash is appended
+const pwnedRangeAPI = "https://api.pwnedpasswords.com/range/"
+
+// pwnedChecker is checker finding if password is breached with range API of HaveIBeenPwned
+// only first 5 character of SHA-1 hash is sent to API (k-anonymity), so password & full hash never leave server
+type pwnedChecker struct {
+	client *http.Client
+}
+
+// PwnedChecker return checker finding breached password with HaveIBeenPwned, which call API with timeout
+func PwnedChecker(timeout time.Duration) *pwnedChecker {
+	return &pwnedChecker{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// IsBreached method return if password is found in breached password of HaveIBeenPwned
+func (pc *pwnedChecker) IsBreached(pw string) (breached bool, err error) {
+	sum := sha1.Sum([]byte(pw))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequest(http.MethodGet, pwnedRangeAPI+prefix, nil)
+	if err != nil {
+		err = errors.Wrap(err, "failed to create request to range API")
+		return
+	}
+	// response is padded with fake suffix, so that size of response doesn't reveal prefix
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := pc.client.Do(req)
+	if err != nil {
+		err = errors.Wrap(err, "failed to call range API")
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = errors.Errorf("range API respond with status %d", resp.StatusCode)
+		return
+	}
+
+	// each line of response is SUFFIX:COUNT, and padded suffix has count 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		s := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(s) == 2 && s[0] == suffix && s[1] != "0" {
+			return true, nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		err = errors.Wrap(err, "failed to read response of range API")
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// mode of delivering token to client, set with SetTokenCookieMode
+const (
+	// TokenInBody deliver token only in JSON body of response (default, for app client)
+	TokenInBody = "body"
+
+	// TokenInCookie deliver token only in httpOnly cookie, which JS of web client can't read
+	TokenInCookie = "cookie"
+
+	// TokenInBoth deliver token in both JSON body & cookie, ex, while web client migrate to cookie
+	TokenInBoth = "both"
+)
+
+// AccessTokenCookie is name of cookie having access token in cookie mode
+// __Host- prefix make browser accept cookie only if it is Secure, has Path=/ and has no Domain
+const AccessTokenCookie = "__Host-access_token"
+
+// SetTokenCookieMode method set mode of delivering token (TokenInBody, TokenInCookie, TokenInBoth)
+// if token is delivered in cookie, it is also read from cookie in ParseUUIDFromToken when Authorization is absent
+//
+// cookie is sent by browser automatically, so middleware.CSRF must be applied together in cookie mode.
+// cross-origin web client also need CORS allowing credential of its origin (not all origins) & X-CSRF-Token header
+func (uh *uuidHandler) SetTokenCookieMode(mode string) error {
+	switch mode {
+	case TokenInBody, TokenInCookie, TokenInBoth:
+		uh.tokenCookieMode = mode
+	case "":
+		uh.tokenCookieMode = TokenInBody
+	default:
+		return errors.Errorf("unknown token cookie mode %s", mode)
+	}
+	return nil
+}
+
+// WriteToken method set token in cookie of response if token is delivered in cookie
+// & return if token should be written in JSON body of response also
+func (uh *uuidHandler) WriteToken(c *gin.Context, token string) (inBody bool) {
+	if uh.tokenCookieMode != TokenInCookie && uh.tokenCookieMode != TokenInBoth {
+		return true
+	}
+
+	// expiry of cookie follow exp of token, which is signed by uuidHandler itself
+	var expires time.Time
+	claims := new(uuidClaims)
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err == nil {
+		expires = time.Unix(claims.ExpiresAt, 0)
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{Name: AccessTokenCookie, Value: token, Path: "/", Expires: expires,
+		Secure: true, HttpOnly: true, SameSite: http.SameSiteLaxMode})
+	return uh.tokenCookieMode == TokenInBoth
+}
+
+// tokenFromCookie method return token in cookie if token is delivered in cookie (empty string if not set)
+// request changing state with the cookie is checked with csrf token in middleware.CSRF before
+func (uh *uuidHandler) tokenFromCookie(c *gin.Context) string {
+	if uh.tokenCookieMode != TokenInCookie && uh.tokenCookieMode != TokenInBoth {
+		return ""
+	}
+	token, _ := c.Cookie(AccessTokenCookie)
+	return token
+}
@@ -1,82 +1,387 @@
 package jwt
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"log"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/locale"
+	"github.com/MyFirstBabyTime/Server/negotiate"
 )
 
 // uuidHandler is jwt handler about uuid token
 type uuidHandler struct {
 	jwtKey string
+
+	// accountChecker is used for checking account of token uuid in ParseUUIDFromToken (not checked if nil)
+	accountChecker accountChecker
+
+	// clock is used for getting current time in issuing & validating token (instead of time.Now)
+	clock clock
+
+	// tokenCookieMode is mode of delivering token to client, set with SetTokenCookieMode
+	tokenCookieMode string
+
+	// requirePhoneCertified is if account without certified phone is rejected in RequireCertifiedAccount
+	requirePhoneCertified bool
+
+	// refreshThreshold is remaining duration of token under which NeedsRefresh respond true, set with SetRefreshThreshold
+	refreshThreshold time.Duration
 }
 
-func UUIDHandler(key string) *uuidHandler {
+func UUIDHandler(key string, cl clock) *uuidHandler {
 	return &uuidHandler{
-		jwtKey: key,
+		jwtKey:           key,
+		clock:            cl,
+		tokenCookieMode:  TokenInBody,
+		refreshThreshold: defaultRefreshThreshold,
 	}
 }
 
+// clock is interface about clock returning current time (ex, fake clock advanced manually in test)
+type clock interface {
+	// Now method return current time
+	Now() time.Time
+}
+
+// accountChecker is interface used for checking if account of uuid is available & get role of account
+type accountChecker interface {
+	// CheckParentAccount check if parent account is available (not suspended) for token issued at iat
+	// & return status of parent account (ex, role)
+	CheckParentAccount(ctx context.Context, uuid string, iat time.Time) (status domain.ParentAccountStatus, err error)
+}
+
+// SetAccountChecker method set accountChecker used for rejecting token of unavailable account (ex, suspended)
+func (uh *uuidHandler) SetAccountChecker(ac accountChecker) {
+	uh.accountChecker = ac
+}
+
+// SetRequirePhoneCertified method set if account without certified phone is rejected in RequireCertifiedAccount
+// it is set false if phone certification is not required in sign up, since account may have no phone then
+func (uh *uuidHandler) SetRequirePhoneCertified(require bool) {
+	uh.requirePhoneCertified = require
+}
+
+// defaultRefreshThreshold is refreshThreshold used if it is not set with SetRefreshThreshold
+const defaultRefreshThreshold = time.Minute * 5
+
+// SetRefreshThreshold method set remaining duration of token under which NeedsRefresh respond true
+func (uh *uuidHandler) SetRefreshThreshold(threshold time.Duration) {
+	uh.refreshThreshold = threshold
+}
+
 // uuidClaims is used for generate JWT including uuid inform
 type uuidClaims struct {
 	UUID string `json:"uuid"`
 	Type string `json:"type"`
+
+	// Scope is scope of token like acr claim (ex, elevated with step-up auth), omitted in normal token
+	Scope string `json:"scope,omitempty"`
 	jwt.StandardClaims
 }
 
+// CheckSigningKey check if jwt key is present & valid to sign token, used for startup check
+func (uh *uuidHandler) CheckSigningKey() (err error) {
+	if uh.jwtKey == "" {
+		return errors.New("jwt key is empty")
+	}
+	if len(uh.jwtKey) < minRecommendedKeyLength {
+		log.Printf("jwt key is shorter than %d byte, which is recommended minimum length for HS512", minRecommendedKeyLength)
+	}
+	_, err = uh.GenerateUUIDJWT("", "check", time.Second)
+	return
+}
+
+// minRecommendedKeyLength is recommended minimum length of jwt key
+const minRecommendedKeyLength = 32
+
 // GenerateUUIDJWT generate & return JWT UUID token with type & time
+// return error implementing SigningFailed() if failed to sign token
 func (uh *uuidHandler) GenerateUUIDJWT(uuid, _type string, t time.Duration) (token string, err error) {
+	return uh.GenerateScopedUUIDJWT(uuid, _type, "", t)
+}
+
+// GenerateScopedUUIDJWT generate & return JWT UUID token like GenerateUUIDJWT, having scope claim
+func (uh *uuidHandler) GenerateScopedUUIDJWT(uuid, _type, scope string, t time.Duration) (token string, err error) {
+	if uh.jwtKey == "" {
+		return "", signingFailedErr{errors.New("jwt key is not available")}
+	}
+
 	token, err = jwt.NewWithClaims(jwt.SigningMethodHS512, uuidClaims{
-		UUID: uuid,
-		Type: _type,
+		UUID:  uuid,
+		Type:  _type,
+		Scope: scope,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(t).Unix(),
+			ExpiresAt: uh.clock.Now().Add(t).Unix(),
+			IssuedAt:  uh.clock.Now().Unix(),
 		},
 	}).SignedString([]byte(uh.jwtKey))
+	if err != nil {
+		err = signingFailedErr{errors.Wrap(err, "failed to sign token")}
+	}
 	return
 }
 
+// signingFailedErr is error type represent failure of signing token
+type signingFailedErr struct {
+	error
+}
+
+func (_ signingFailedErr) SigningFailed() {}
+
 // ParseUUIDFromToken is middleware that parse uuid & type from token received from request header
 func (uh *uuidHandler) ParseUUIDFromToken(c *gin.Context) {
+	claims, msg := uh.parseClaims(c)
+	if claims == nil {
+		negotiate.AbortWithStatus(c, http.StatusUnauthorized, localizedResp(c, http.StatusUnauthorized, 0, msg))
+		return
+	}
+
+	if now := uh.clock.Now().Unix(); !claims.VerifyExpiresAt(now, true) || !claims.VerifyIssuedAt(now, false) {
+		negotiate.AbortWithStatus(c, http.StatusUnauthorized, localizedResp(c, http.StatusUnauthorized, 0, "token is expired or not valid yet"))
+		return
+	}
+
+	if uh.accountChecker != nil {
+		switch status, err := uh.accountChecker.CheckParentAccount(c.Request.Context(), claims.UUID, time.Unix(claims.IssuedAt, 0)); tErr := err.(type) {
+		case nil:
+			c.Set("role", status.Role)
+			c.Set("phone_certified", status.PhoneCertified)
+		case domain.UsecaseError:
+			negotiate.AbortWithStatus(c, tErr.Status, usecaseErrorResp(c, tErr))
+			return
+		default:
+			negotiate.AbortWithStatus(c, http.StatusInternalServerError, internalErrorResp(c, err))
+			return
+		}
+	}
+
+	c.Set("uuid", claims.UUID)
+	c.Set("_type", claims.Type)
+	c.Set("scope", claims.Scope)
+	c.Next() // middleware로 쓰인다는 것을 명시하기 위해 c.Next() 호출 (호출 안해도 다음으로 등록된 handler 실행되긴 함)
+}
+
+// parseClaims method parse claims of token in Authorization header (or cookie if absent), verifying only signature of it
+// return nil claims & message about why it is failed if token is not set or signature is invalid
+func (uh *uuidHandler) parseClaims(c *gin.Context) (claims *uuidClaims, msg string) {
 	var tokenStr string
 	if tokens := c.Request.Header["Authorization"]; len(tokens) >= 1 {
 		tokenStr = tokens[0]
-	} else {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, defaultResp(http.StatusUnauthorized, 0, "Authorization not set"))
-		return
+	} else if tokenStr = uh.tokenFromCookie(c); tokenStr == "" {
+		return nil, "Authorization not set"
 	}
 
 	if strings.Contains(tokenStr, "Bearer") {
 		tokenStr = strings.Join(strings.Split(strings.TrimPrefix(tokenStr, "Bearer"), " "), "")
 	}
 
-	token, err := jwt.ParseWithClaims(tokenStr, &uuidClaims{}, func(t *jwt.Token) (interface{}, error) {
+	// claims is validated with clock by caller, instead of time.Now used in jwt package
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.ParseWithClaims(tokenStr, &uuidClaims{}, func(t *jwt.Token) (interface{}, error) {
 		return []byte(uh.jwtKey), nil
 	})
-
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, defaultResp(http.StatusUnauthorized, 0, err.Error()))
-		return
+		return nil, err.Error()
 	}
 
 	claims, ok := token.Claims.(*uuidClaims)
 	if !ok || !token.Valid {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, defaultResp(http.StatusUnauthorized, 0, "failed to assert token Claim"))
+		return nil, "failed to assert token Claim"
+	}
+	return claims, ""
+}
+
+// IntrospectToken is handler that return exp, iat of token & if it is currently valid, without DB lookup
+// revocation of token (ex, by password change) is checked with accountChecker only if check_revocation query is true
+// expired token is responded with valid false rather than 401, so that client schedule refresh with its exp
+func (uh *uuidHandler) IntrospectToken(c *gin.Context) {
+	claims, msg := uh.parseClaims(c)
+	if claims == nil {
+		negotiate.Render(c, http.StatusUnauthorized, localizedResp(c, http.StatusUnauthorized, 0, msg))
 		return
 	}
 
-	c.Set("uuid", claims.UUID)
-	c.Set("_type", claims.Type)
-	c.Next() // middleware로 쓰인다는 것을 명시하기 위해 c.Next() 호출 (호출 안해도 다음으로 등록된 handler 실행되긴 함)
+	resp := introspectTokenResponse{response: defaultResp(http.StatusOK, 0, "succeed to introspect token")}
+	resp.UUID, resp.Type, resp.ExpiresAt, resp.IssuedAt = claims.UUID, claims.Type, claims.ExpiresAt, claims.IssuedAt
+	resp.Scope = claims.Scope
+
+	now := uh.clock.Now().Unix()
+	resp.Valid = claims.VerifyExpiresAt(now, true) && claims.VerifyIssuedAt(now, false)
+
+	if resp.Valid && c.Query("check_revocation") == "true" && uh.accountChecker != nil {
+		switch _, err := uh.accountChecker.CheckParentAccount(c.Request.Context(), claims.UUID, time.Unix(claims.IssuedAt, 0)); tErr := err.(type) {
+		case nil:
+			break
+		case domain.UsecaseError:
+			if tErr.Status >= http.StatusInternalServerError {
+				negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+				return
+			}
+			resp.Valid = false
+		default:
+			negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, err))
+			return
+		}
+	}
+
+	negotiate.Render(c, http.StatusOK, resp)
+}
+
+// NeedsRefresh is handler that return if token is expired or will expire within refreshThreshold, without DB lookup
+// so that client decide when to refresh with clock of server, instead of calculating it with exp & its own clock
+// revocation of token is checked with accountChecker only if check_revocation query is true, and revoked token is
+// responded with error of the check, since it can't be refreshed but client must login again
+func (uh *uuidHandler) NeedsRefresh(c *gin.Context) {
+	claims, msg := uh.parseClaims(c)
+	if claims == nil {
+		negotiate.Render(c, http.StatusUnauthorized, localizedResp(c, http.StatusUnauthorized, 0, msg))
+		return
+	}
+
+	if c.Query("check_revocation") == "true" && uh.accountChecker != nil {
+		switch _, err := uh.accountChecker.CheckParentAccount(c.Request.Context(), claims.UUID, time.Unix(claims.IssuedAt, 0)); tErr := err.(type) {
+		case nil:
+			break
+		case domain.UsecaseError:
+			negotiate.Render(c, tErr.Status, usecaseErrorResp(c, tErr))
+			return
+		default:
+			negotiate.Render(c, http.StatusInternalServerError, internalErrorResp(c, err))
+			return
+		}
+	}
+
+	resp := needsRefreshResponse{response: defaultResp(http.StatusOK, 0, "succeed to check if token needs refresh")}
+	resp.NeedsRefresh = !claims.VerifyExpiresAt(uh.clock.Now().Add(uh.refreshThreshold).Unix(), true)
+	negotiate.Render(c, http.StatusOK, resp)
+}
+
+// RequireRole return middleware that reject request if role of token account is not equal to role
+// must be used after ParseUUIDFromToken, which set role of token account
+func (uh *uuidHandler) RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != role {
+			negotiate.AbortWithStatus(c, http.StatusForbidden, localizedResp(c, http.StatusForbidden, 0, "you don't have role to access"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireCertifiedAccount is middleware that reject request of account without certified phone, if it is required
+// suspended or deleted account is rejected in ParseUUIDFromToken already, so that account passing both of them is
+// in good standing. must be used after ParseUUIDFromToken, which set if phone of token account is certified
+func (uh *uuidHandler) RequireCertifiedAccount(c *gin.Context) {
+	if uh.requirePhoneCertified && !c.GetBool("phone_certified") {
+		negotiate.AbortWithStatus(c, http.StatusForbidden, localizedResp(c, http.StatusForbidden, domain.UncertifiedPhone, "certified phone is required to access"))
+		return
+	}
+	c.Next()
+}
+
+// RequireScope return middleware that reject request if scope of token is not equal to scope
+// must be used after ParseUUIDFromToken, which set scope of token
+func (uh *uuidHandler) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("scope") != scope {
+			negotiate.AbortWithStatus(c, http.StatusForbidden, localizedResp(c, http.StatusForbidden, domain.StepUpRequired, "token of this scope is required to access"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScopeIfPhoneCertified return middleware like RequireScope, but only for account having certified phone
+// scope is granted only with certify code sent to phone (StepUpWithPhoneCode), so account without certified phone
+// can't get it & handler must re-authenticate that account in other way (ex, password in request)
+// must be used after ParseUUIDFromToken, which set scope of token & if phone of token account is certified
+func (uh *uuidHandler) RequireScopeIfPhoneCertified(scope string) gin.HandlerFunc {
+	requireScope := uh.RequireScope(scope)
+	return func(c *gin.Context) {
+		if !c.GetBool("phone_certified") {
+			c.Next()
+			return
+		}
+		requireScope(c)
+	}
+}
+
+// introspectTokenResponse is response for uuidHandler.IntrospectToken
+type introspectTokenResponse struct {
+	response
+	UUID      string `json:"uuid"`
+	Type      string `json:"type"`
+	Scope     string `json:"scope,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+	IssuedAt  int64  `json:"iat"`
+	Valid     bool   `json:"valid"`
+}
+
+// needsRefreshResponse is response for uuidHandler.NeedsRefresh
+type needsRefreshResponse struct {
+	response
+	NeedsRefresh bool `json:"needs_refresh"`
+}
+
+// response is response envelope having status, code, message inform
+type response struct {
+	Status  int    `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+
+	// Key is stable key of error, which message is localized with (set only in error response)
+	Key string `json:"key,omitempty"`
+
+	// Ref is reference id of internal error, set only in response of internal error
+	Ref string `json:"ref,omitempty"`
 }
 
 // defaultResp return response have status, code, message inform
-func defaultResp(status, code int, msg string) (resp gin.H) {
-	resp = gin.H{}
-	resp["status"] = status
-	resp["code"] = code
-	resp["message"] = msg
-	return
+func defaultResp(status, code int, msg string) response {
+	return response{Status: status, Code: code, Message: msg}
+}
+
+// localizedResp return response with message localized in locale negotiated from Accept-Language of c
+// msg is used as it is if catalog doesn't have message about key of status & code
+func localizedResp(c *gin.Context, status, code int, msg string) response {
+	key := domain.ErrorKey(status, code)
+	if localized, ok := locale.Message(locale.Negotiate(c.GetHeader("Accept-Language")), key); ok {
+		msg = localized
+	}
+	resp := defaultResp(status, code, msg)
+	resp.Key = key
+	return resp
+}
+
+// internalErrorResp log err with generated reference id & return 500 response having the id instead of err,
+// so that internal error (ex, DB error text) is not exposed to client, and operator find log with the id
+func internalErrorResp(c *gin.Context, err error) response {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	ref := hex.EncodeToString(b)
+
+	log.Printf("internal error, ref: %s, err: %v", ref, err)
+	resp := localizedResp(c, http.StatusInternalServerError, 0, "internal error,")
+	resp.Message += " ref: " + ref
+	resp.Ref = ref
+	return resp
+}
+
+// usecaseErrorResp return localized response about domain.UsecaseError, hiding error with internalErrorResp if status is 5xx
+func usecaseErrorResp(c *gin.Context, err domain.UsecaseError) response {
+	if err.Status >= http.StatusInternalServerError {
+		resp := internalErrorResp(c, err)
+		resp.Status, resp.Code, resp.Key = err.Status, err.Code, domain.ErrorKey(err.Status, err.Code)
+		return resp
+	}
+	return localizedResp(c, err.Status, err.Code, err.Error())
 }
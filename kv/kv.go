@@ -0,0 +1,27 @@
+// Package kv provide store keeping small & fast-changing state (ex, counter of rate limit, flag of cooldown) with TTL
+// state is kept in process with Memory for single node, or shared across server instances with Redis
+package kv
+
+import "time"
+
+// Store is interface implemented by every store in this package, used where store is selected by config
+// consumer (ex, usecase, middleware) is recommended to declare interface having only method it use
+type Store interface {
+	// Incr method increase integer value of key by 1 & return increased value, and ttl is set only if key is created
+	Incr(key string, ttl time.Duration) (int64, error)
+
+	// Get method return value of key & if key exist (not expired)
+	Get(key string) (string, bool, error)
+
+	// SetNX method set value of key with ttl only if key doesn't exist, and return if value is set
+	SetNX(key, value string, ttl time.Duration) (bool, error)
+
+	// Set method set value of key with ttl, overwriting existing value (never expire if 0)
+	Set(key, value string, ttl time.Duration) error
+
+	// Delete method delete key, and do nothing if key doesn't exist
+	Delete(key string) error
+
+	// Close method release resource of store (ex, connection), which must be called in shutdown
+	Close()
+}
@@ -0,0 +1,145 @@
+package kv
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is minimum interval between sweep of expired entry in memoryStore
+const memorySweepInterval = time.Minute
+
+// clock is interface about clock returning current time (ex, clock.Real)
+type clock interface {
+	Now() time.Time
+}
+
+// memoryStore is store keeping state in map of process, which is not shared across server instances
+type memoryStore struct {
+	clock clock
+
+	mutex     sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// memoryEntry is value of memoryStore with time it expire at (never expire if zero)
+type memoryEntry struct {
+	value    string
+	expireAt time.Time
+}
+
+// Memory return store keeping state in memory of process, used as default in single node deployment
+func Memory(cl clock) *memoryStore {
+	return &memoryStore{
+		clock:     cl,
+		entries:   map[string]memoryEntry{},
+		lastSweep: cl.Now(),
+	}
+}
+
+// Incr method increase integer value of key by 1 & return increased value
+// ttl is set only if key is created by this call, so that counter expire in fixed window (never expire if 0)
+func (ms *memoryStore) Incr(key string, ttl time.Duration) (int64, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := ms.clock.Now()
+	ms.sweep(now)
+	entry, ok := ms.entry(key, now)
+	if !ok {
+		entry = memoryEntry{value: "0"}
+		if ttl > 0 {
+			entry.expireAt = now.Add(ttl)
+		}
+	}
+
+	n, err := strconv.ParseInt(entry.value, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("value of key %s is not integer", key)
+	}
+	n++
+	entry.value = strconv.FormatInt(n, 10)
+	ms.entries[key] = entry
+	return n, nil
+}
+
+// Get method return value of key & if key exist (not expired)
+func (ms *memoryStore) Get(key string) (string, bool, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	entry, ok := ms.entry(key, ms.clock.Now())
+	return entry.value, ok, nil
+}
+
+// SetNX method set value of key with ttl only if key doesn't exist, and return if value is set
+func (ms *memoryStore) SetNX(key, value string, ttl time.Duration) (bool, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := ms.clock.Now()
+	ms.sweep(now)
+	if _, ok := ms.entry(key, now); ok {
+		return false, nil
+	}
+
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expireAt = now.Add(ttl)
+	}
+	ms.entries[key] = entry
+	return true, nil
+}
+
+// Set method set value of key with ttl, overwriting existing value (never expire if 0)
+func (ms *memoryStore) Set(key, value string, ttl time.Duration) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := ms.clock.Now()
+	ms.sweep(now)
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expireAt = now.Add(ttl)
+	}
+	ms.entries[key] = entry
+	return nil
+}
+
+// Delete method delete key, and do nothing if key doesn't exist
+func (ms *memoryStore) Delete(key string) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	delete(ms.entries, key)
+	return nil
+}
+
+// Close method do nothing, since memoryStore hold no resource outside of process
+func (ms *memoryStore) Close() {}
+
+// entry method return entry of key if it is not expired at now (mutex must be locked by caller)
+func (ms *memoryStore) entry(key string, now time.Time) (memoryEntry, bool) {
+	entry, ok := ms.entries[key]
+	if ok && !entry.expireAt.IsZero() && !now.Before(entry.expireAt) {
+		delete(ms.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, ok
+}
+
+// sweep method delete every expired entry at most once in memorySweepInterval (mutex must be locked by caller)
+// key never read again (ex, counter of client gone) is deleted here, so that map doesn't grow unboundedly
+func (ms *memoryStore) sweep(now time.Time) {
+	if now.Sub(ms.lastSweep) < memorySweepInterval {
+		return
+	}
+	for key, entry := range ms.entries {
+		if !entry.expireAt.IsZero() && !now.Before(entry.expireAt) {
+			delete(ms.entries, key)
+		}
+	}
+	ms.lastSweep = now
+}
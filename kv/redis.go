@@ -0,0 +1,246 @@
+package kv
+
+import (
+	"bufio"
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// redisTimeout is timeout of dial & each command to redis, so that slow redis doesn't hang request
+const redisTimeout = time.Second
+
+// redisIncrScript is lua script increasing counter & setting ttl with it atomically (ttl is set only in creation)
+const redisIncrScript = `local n = redis.call('INCR', KEYS[1])
+if n == 1 and tonumber(ARGV[1]) > 0 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return n`
+
+// redisStore is store keeping state in redis, which is shared across server instances
+// command is sent in RESP protocol over pooled connection, since only few command are needed
+type redisStore struct {
+	addr     string
+	password string
+
+	// conns is pool of idle connection, and connection is dialed if pool is empty
+	conns chan *redisConn
+}
+
+// redisConn is connection to redis with reader of reply
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Redis return store keeping state in redis at addr, authenticated with password (not authenticated if empty)
+// at most poolSize connection is kept idle, and connection over it is closed after command
+func Redis(addr, password string, poolSize int) *redisStore {
+	if poolSize <= 0 {
+		poolSize = 1
+	}
+	return &redisStore{
+		addr:     addr,
+		password: password,
+		conns:    make(chan *redisConn, poolSize),
+	}
+}
+
+// Incr method increase integer value of key by 1 & return increased value
+// ttl is set only if key is created by this call, so that counter expire in fixed window (never expire if 0)
+func (rs *redisStore) Incr(key string, ttl time.Duration) (int64, error) {
+	reply, err := rs.do("EVAL", redisIncrScript, "1", key, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, errors.Errorf("unexpected reply of INCR script, reply: %v", reply)
+	}
+	return n, nil
+}
+
+// Get method return value of key & if key exist (not expired)
+func (rs *redisStore) Get(key string) (string, bool, error) {
+	reply, err := rs.do("GET", key)
+	if err != nil || reply == nil {
+		return "", false, err
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return "", false, errors.Errorf("unexpected reply of GET, reply: %v", reply)
+	}
+	return value, true, nil
+}
+
+// SetNX method set value of key with ttl only if key doesn't exist, and return if value is set
+func (rs *redisStore) SetNX(key, value string, ttl time.Duration) (bool, error) {
+	args := []string{"SET", key, value, "NX"}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	reply, err := rs.do(args...)
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// Set method set value of key with ttl, overwriting existing value (never expire if 0)
+func (rs *redisStore) Set(key, value string, ttl time.Duration) error {
+	args := []string{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := rs.do(args...)
+	return err
+}
+
+// Delete method delete key, and do nothing if key doesn't exist
+func (rs *redisStore) Delete(key string) error {
+	_, err := rs.do("DEL", key)
+	return err
+}
+
+// Close method close every idle connection, which must be called in shutdown
+func (rs *redisStore) Close() {
+	for {
+		select {
+		case conn := <-rs.conns:
+			_ = conn.Close()
+		default:
+			return
+		}
+	}
+}
+
+// do method send command to redis & return reply (string, int64, []interface{} or nil)
+// connection is discarded if command fail in network, since reply of it may be left unread in connection
+func (rs *redisStore) do(args ...string) (reply interface{}, err error) {
+	conn, err := rs.conn()
+	if err != nil {
+		return
+	}
+
+	if reply, err = conn.command(args...); err != nil {
+		if _, ok := err.(redisErr); !ok {
+			_ = conn.Close()
+			return
+		}
+	}
+
+	select {
+	case rs.conns <- conn:
+	default:
+		_ = conn.Close()
+	}
+	return
+}
+
+// conn method return idle connection in pool, or dial new one if pool is empty
+func (rs *redisStore) conn() (*redisConn, error) {
+	select {
+	case conn := <-rs.conns:
+		return conn, nil
+	default:
+	}
+
+	c, err := net.DialTimeout("tcp", rs.addr, redisTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to redis at %s", rs.addr)
+	}
+	conn := &redisConn{Conn: c, reader: bufio.NewReader(c)}
+	if rs.password != "" {
+		if _, err = conn.command("AUTH", rs.password); err != nil {
+			_ = conn.Close()
+			return nil, errors.Wrap(err, "failed to authenticate to redis")
+		}
+	}
+	return conn, nil
+}
+
+// command method write command as RESP array of bulk string & read reply of it
+func (rc *redisConn) command(args ...string) (interface{}, error) {
+	if err := rc.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return nil, errors.Wrap(err, "failed to set deadline of redis connection")
+	}
+
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	if _, err := rc.Write(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to write command to redis")
+	}
+	return rc.readReply()
+}
+
+// readReply method read one RESP reply, and return redisErr if it is error reply
+func (rc *redisConn) readReply() (interface{}, error) {
+	line, err := rc.reader.ReadString('\n')
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read reply from redis")
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.Errorf("malformed reply from redis, line: %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisErr{errors.New(body)}
+	case ':':
+		n, err := strconv.ParseInt(body, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "malformed integer reply from redis")
+		}
+		return n, nil
+	case '$':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "malformed bulk reply from redis")
+		}
+		if size < 0 {
+			// null bulk reply, which is returned if key doesn't exist (ex, GET) or not set (ex, SET NX)
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err = io.ReadFull(rc.reader, data); err != nil {
+			return nil, errors.Wrap(err, "failed to read bulk reply from redis")
+		}
+		return string(data[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "malformed array reply from redis")
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		replies := make([]interface{}, size)
+		for i := range replies {
+			if replies[i], err = rc.readReply(); err != nil {
+				if _, ok := err.(redisErr); !ok {
+					return nil, err
+				}
+			}
+		}
+		return replies, nil
+	default:
+		return nil, errors.Errorf("unknown type of reply from redis, line: %q", line)
+	}
+}
+
+// redisErr is error type represent error reply of redis (ex, WRONGTYPE), after which connection is still usable
+type redisErr struct {
+	error
+}
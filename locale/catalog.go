@@ -0,0 +1,116 @@
+package locale
+
+// catalog is message catalog having localized message by locale & stable key
+// key of error response is from domain.ErrorKey, and key of validation error is "validation." + tag
+// format of validation message get field name & param (or tag in validation.default) of tag as argument
+// English don't have message about status only key (ex, not_found), so that specific message of usecase is used
+var catalog = map[string]map[string]string{
+	Korean: {
+		"bad_request":         "요청이 올바르지 않습니다.",
+		"unauthorized":        "인증이 필요합니다.",
+		"forbidden":           "접근 권한이 없습니다.",
+		"not_found":           "요청한 대상이 존재하지 않습니다.",
+		"conflict":            "요청이 현재 상태와 충돌합니다.",
+		"internal_error":      "서버 내부 오류가 발생했습니다.",
+		"service_unavailable": "잠시 후 다시 시도해주세요.",
+		"too_many_requests":   "요청이 너무 많습니다. 잠시 후 다시 시도해주세요.",
+		"gateway_timeout":     "요청 처리 시간이 초과되었습니다. 잠시 후 다시 시도해주세요.",
+
+		"phone_already_in_use":          "이미 사용 중인 전화번호입니다.",
+		"unsupported_certify_channel":   "지원하지 않는 인증 번호 전송 방식입니다.",
+		"invalid_phone_number":          "전화번호 형식이 올바르지 않습니다.",
+		"certify_code_not_persisted":    "인증 번호 처리 중 오류가 발생했습니다. 방금 받은 인증 번호는 사용할 수 없으니 인증 번호를 다시 요청해 주세요.",
+		"phone_already_certified":       "이미 인증된 전화번호입니다.",
+		"incorrect_certify_code":        "인증 번호가 올바르지 않습니다.",
+		"phone_owned_by_another_parent": "다른 계정에 연결된 전화번호입니다.",
+		"uncertified_phone":             "인증되지 않은 전화번호입니다.",
+		"parent_id_already_in_use":      "이미 사용 중인 아이디입니다.",
+		"password_breached":             "유출된 적이 있는 비밀번호입니다. 다른 비밀번호를 사용해 주세요.",
+		"too_many_accounts_for_phone":   "이 전화번호로 가입할 수 있는 계정 수를 초과했습니다. 나중에 다시 시도해 주세요.",
+		"parent_id_reserved_by_another": "다른 사용자가 가입 중인 아이디입니다. 다른 아이디를 선택해 주세요.",
+		"not_exist_parent_id":           "존재하지 않는 아이디입니다.",
+		"incorrect_parent_pw":           "비밀번호가 올바르지 않습니다.",
+		"account_suspended":             "정지된 계정입니다.",
+		"token_issue_failed":            "토큰 발급에 실패했습니다.",
+		"account_locked":                "로그인 실패가 반복되어 계정이 일시적으로 잠겼습니다.",
+		"device_verification_required":  "새로운 기기에서 로그인하려면 전화번호로 전송된 인증 번호를 입력해 주세요.",
+		"device_verification_expired":   "기기 인증 시간이 만료되었습니다. 다시 로그인해 주세요.",
+		"too_many_sessions":             "동시에 로그인할 수 있는 기기 수를 초과했습니다. 다른 기기의 세션이 만료된 후 다시 시도해 주세요.",
+		"parent_id_change_too_soon":     "아이디를 변경한 지 얼마 되지 않아 다시 변경할 수 없습니다.",
+		"too_many_phone_numbers":        "한 번에 조회할 수 있는 전화번호 수를 초과했습니다.",
+		"data_export_too_soon":          "데이터 내보내기는 잠시 후 다시 요청할 수 있습니다.",
+		"passkey_unavailable":           "패스키를 사용할 수 없습니다.",
+		"passkey_verification_failed":   "패스키 인증에 실패했습니다.",
+		"passkey_ceremony_expired":      "패스키 요청이 만료되었습니다. 다시 시도해 주세요.",
+		"no_passkey_registered":         "등록된 패스키가 없습니다.",
+		"parent_phone_already_linked":   "이미 전화번호가 연결된 계정입니다.",
+		"step_up_required":              "전화번호 재인증이 필요한 요청입니다.",
+		"too_many_password_attempts":    "비밀번호 확인 시도가 너무 많습니다. 잠시 후 다시 시도해 주세요.",
+		"under_maintenance":             "서비스 점검 중에는 이용할 수 없는 기능입니다. 점검이 끝난 후 다시 시도해 주세요.",
+		"too_many_code_attempts":        "인증 번호 확인 시도가 너무 많습니다. 잠시 후 다시 시도해 주세요.",
+		"route_not_found":               "존재하지 않는 API 입니다.",
+		"method_not_allowed":            "허용되지 않는 메소드입니다.",
+		"csrf_token_mismatch":           "CSRF 토큰이 없거나 올바르지 않습니다.",
+
+		"validation.required":    "%[1]s 값은 필수입니다.",
+		"validation.required_if": "%[1]s 값은 필수입니다.",
+		"validation.min":         "%s 값은 최소 %s 이상이어야 합니다.",
+		"validation.max":         "%s 값은 최대 %s 이하여야 합니다.",
+		"validation.len":         "%s 값의 길이는 %s 이어야 합니다.",
+		"validation.oneof":       "%s 값은 [%s] 중 하나여야 합니다.",
+		"validation.email":       "%[1]s 값은 이메일 형식이어야 합니다.",
+		"validation.safe_text":   "%[1]s 값에 사용할 수 없는 문자가 포함되어 있습니다.",
+		"validation.default":     "%s 값이 올바르지 않습니다. (%s)",
+	},
+	English: {
+		"phone_already_in_use":          "This phone number is already in use.",
+		"unsupported_certify_channel":   "This channel is not supported to send certify code.",
+		"invalid_phone_number":          "Phone number must be 11 digits.",
+		"certify_code_not_persisted":    "Failed to process certify code, the code just received can't be used, please request certify code again.",
+		"phone_already_certified":       "This phone number is already certified.",
+		"incorrect_certify_code":        "Certify code is incorrect.",
+		"phone_owned_by_another_parent": "This phone number is linked to another account.",
+		"uncertified_phone":             "This phone number is not certified.",
+		"parent_id_already_in_use":      "This ID is already in use.",
+		"password_breached":             "This password has appeared in a data breach, please choose another one.",
+		"too_many_accounts_for_phone":   "Too many accounts were created with this phone number recently, please retry later.",
+		"parent_id_reserved_by_another": "This ID is being used in sign up of another user, please choose another ID.",
+		"not_exist_parent_id":           "This ID does not exist.",
+		"incorrect_parent_pw":           "Password is incorrect.",
+		"account_suspended":             "This account is suspended.",
+		"token_issue_failed":            "Failed to issue token.",
+		"account_locked":                "Account is temporarily locked due to repeated login failures.",
+		"device_verification_required":  "Enter the certify code sent to your phone to log in from new device.",
+		"device_verification_expired":   "Device verification is expired. Please log in again.",
+		"too_many_sessions":             "Too many devices are logged in to this account. Please retry after a session of another device expires.",
+		"parent_id_change_too_soon":     "ID was changed recently, so cannot be changed again yet.",
+		"too_many_phone_numbers":        "Too many phone numbers are requested at once.",
+		"data_export_too_soon":          "Data export was requested recently, please retry later.",
+		"passkey_unavailable":           "Passkey is not available.",
+		"passkey_verification_failed":   "Failed to verify passkey.",
+		"passkey_ceremony_expired":      "Passkey request is expired, please try again.",
+		"no_passkey_registered":         "No passkey is registered.",
+		"parent_phone_already_linked":   "Phone number is already linked to this account.",
+		"step_up_required":              "Phone re-verification is required for this request.",
+		"too_many_password_attempts":    "Too many password attempts, please retry after a while.",
+		"under_maintenance":             "This feature is unavailable during maintenance, please retry after maintenance.",
+		"too_many_code_attempts":        "Too many certify code attempts, please retry after a while.",
+		"route_not_found":               "This route does not exist.",
+		"method_not_allowed":            "This method is not allowed for this route.",
+		"csrf_token_mismatch":           "CSRF token is missing or invalid.",
+		"internal_error":                "Internal error occurred.",
+		"service_unavailable":           "Please retry after a while.",
+		"too_many_requests":             "Too many requests, please retry after a while.",
+		"gateway_timeout":               "Request timed out, please retry after a while.",
+
+		"validation.required":    "%[1]s is required.",
+		"validation.required_if": "%[1]s is required.",
+		"validation.min":         "%s must be at least %s.",
+		"validation.max":         "%s must be at most %s.",
+		"validation.len":         "%s must have length %s.",
+		"validation.oneof":       "%s must be one of [%s].",
+		"validation.email":       "%[1]s must be email.",
+		"validation.safe_text":   "%[1]s contains character not allowed.",
+		"validation.default":     "%s is invalid. (%s)",
+	},
+}
@@ -0,0 +1,82 @@
+// Package locale provide locale negotiation with Accept-Language & message catalog used for localizing response
+package locale
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// supported locale
+const (
+	Korean  = "ko"
+	English = "en"
+
+	// Default is locale used if Accept-Language has no supported locale
+	Default = Korean
+)
+
+// Negotiate return supported locale having highest quality in Accept-Language header value (Default if not exist)
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang := strings.ToLower(strings.SplitN(strings.TrimSpace(fields[0]), "-", 2)[0])
+		if _, ok := catalog[lang]; !ok {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v := strings.TrimSpace(param); strings.HasPrefix(v, "q=") {
+				if parsed, err := strconv.ParseFloat(v[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// Message return message of key localized in lang & ok false if catalog doesn't have it
+// (caller should use its own message in that case, rather than one of other locale)
+func Message(lang, key string) (msg string, ok bool) {
+	msg, ok = catalog[lang][key]
+	return
+}
+
+// ValidationMessage return localized message about validation error in err, caused by validator.ValidationErrors
+// ok is false if err is not caused by validation (ex, failed to bind JSON)
+func ValidationMessage(lang string, err error) (msg string, ok bool) {
+	vErrs, ok := errors.Cause(err).(validator.ValidationErrors)
+	if !ok {
+		return "", false
+	}
+
+	messages := catalog[lang]
+	msgs := make([]string, 0, len(vErrs))
+	for _, vErr := range vErrs {
+		if format, ok := messages["validation."+vErr.Tag()]; ok {
+			msgs = append(msgs, fmt.Sprintf(format, vErr.Field(), vErr.Param()))
+		} else {
+			msgs = append(msgs, fmt.Sprintf(messages["validation.default"], vErr.Field(), vErr.Tag()))
+		}
+	}
+	return strings.Join(msgs, ", "), true
+}
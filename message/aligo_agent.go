@@ -5,12 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/MyFirstBabyTime/Server/domain"
 )
 
 // aligoAgent is struct that agent API about aligo including sending message, get message list, etc ...
 type aligoAgent struct {
 	apiKey, id, sender string
+
+	// client is HTTP client shared by every call, so that connection to aligo API is reused
+	client *http.Client
 }
 
 func AligoAgent(apiKey, id, sender string) *aligoAgent {
@@ -18,15 +25,68 @@ func AligoAgent(apiKey, id, sender string) *aligoAgent {
 		apiKey: apiKey,
 		id:     id,
 		sender: sender,
+		client: &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()},
 	}
 }
 
-// SendSMSToOne method send SMS message to one receiver
-func (aa *aligoAgent) SendSMSToOne(receiver, content string) (err error) {
-	return aa.sendMsgToReceivers([]string{receiver}, "", content, "SMS")
+// Close method close idle connection of HTTP client, which should be called in shutdown of server
+func (aa *aligoAgent) Close() {
+	aa.client.CloseIdleConnections()
+}
+
+// SMSOptions is option about sending SMS message used in SendSMSToOneWithOptions
+type SMSOptions struct {
+	// Title is title of message, used only if message is sent as LMS
+	Title string
+
+	// MsgType force message type (SMS or LMS), selected with byte length of content if empty
+	MsgType string
+}
+
+// SendSMSToOne method send SMS message to one receiver & return result including segment count
+func (aa *aligoAgent) SendSMSToOne(receiver, content string) (result domain.SMSResult, err error) {
+	return aa.SendSMSToOneWithOptions(receiver, content, SMSOptions{})
+}
+
+// SendSMSToOneWithOptions method send SMS message to one receiver with options
+// message type is selected with byte length of content, if not set in options
+func (aa *aligoAgent) SendSMSToOneWithOptions(receiver, content string, opts SMSOptions) (result domain.SMSResult, err error) {
+	if !domesticMobileRegex.MatchString("0" + strings.TrimPrefix(toE164(receiver), "+"+domesticCallingCode)) {
+		err = invalidReceiverErr{errors.New(fmt.Sprintf("receiver %s is not domestic mobile number", domain.MaskPhoneNumber(receiver)))}
+		return domain.SMSResult{Status: domain.SMSStatusFailed}, err
+	}
+	if opts.MsgType == "" {
+		opts.MsgType = msgTypeFor(content)
+	}
+
+	result = domain.SMSResult{
+		MsgType:       opts.MsgType,
+		ContentLength: utf8.RuneCountInString(content),
+		Segments:      smsSegments(content),
+	}
+	if result.Status, err = aa.sendMsgToReceivers([]string{receiver}, opts.Title, content, opts.MsgType); err != nil {
+		return
+	}
+
+	smsSentMetric.Add(result.MsgType, 1)
+	smsSegmentsMetric.Add(result.MsgType, int64(result.Segments))
+	return
+}
+
+// SendVoiceCode method send certify code by voice call to one receiver (not supported in aligo)
+func (aa *aligoAgent) SendVoiceCode(receiver, content string) (err error) {
+	return unsupportedErr{errors.New("aligo API doesn't support voice call")}
 }
 
-func (aa *aligoAgent) sendMsgToReceivers(receivers []string, title, content, _type string) (err error) {
+// SendEmail method send email to one receiver (not supported in aligo)
+func (aa *aligoAgent) SendEmail(receiver, title, content string) (err error) {
+	return unsupportedErr{errors.New("aligo API doesn't support email")}
+}
+
+// sendMsgToReceivers method send message to receivers with aligo API & return status of message
+// status is SMSStatusQueued if aligo accepted message but counted neither success nor error of it yet
+func (aa *aligoAgent) sendMsgToReceivers(receivers []string, title, content, _type string) (status string, err error) {
+	status = domain.SMSStatusFailed
 	req, err := http.NewRequest("POST", "https://apis.aligo.in/send/", nil)
 	if err != nil {
 		err = errors.New(fmt.Sprintf("some error occurs while creating request, err: %v", err))
@@ -47,26 +107,73 @@ func (aa *aligoAgent) sendMsgToReceivers(receivers []string, title, content, _ty
 	q.Add("msg", content)
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := (&http.Client{}).Do(req)
+	resp, err := aa.client.Do(req)
 	if err != nil {
-		err = errors.New(fmt.Sprintf("some error occurs while sending request, err: %v", err))
+		err = transientErr{errors.New(fmt.Sprintf("some error occurs while sending request, err: %v", err))}
 		return
 	}
+	// body must be closed, so that connection is returned to client & reused
+	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		err = errors.New(fmt.Sprintf("aligo API dosen't return 200, status code: %d", resp.StatusCode))
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		break
+	case resp.StatusCode >= http.StatusInternalServerError, resp.StatusCode == http.StatusTooManyRequests:
+		err = transientErr{errors.New(fmt.Sprintf("aligo API dosen't return 200, status code: %d", resp.StatusCode))}
+		return
+	default:
+		err = permanentErr{errors.New(fmt.Sprintf("aligo API dosen't return 200, status code: %d", resp.StatusCode))}
 		return
 	}
 
 	respBody := struct {
-		Code int    `json:"result_code"`
-		Msg  string `json:"message"`
+		Code       int    `json:"result_code"`
+		Msg        string `json:"message"`
+		SuccessCnt int    `json:"success_cnt"`
+		ErrorCnt   int    `json:"error_cnt"`
 	}{}
 	_ = json.NewDecoder(resp.Body).Decode(&respBody)
 
+	// request rejected by aligo (ex, wrong key, not enough point) fail again in retry, so it is permanent
 	if respBody.Code != 0 {
-		err = errors.New(fmt.Sprintf("aligo API return unexpected result code, result code: %d, message: %s", respBody.Code, respBody.Msg))
+		err = permanentErr{errors.New(fmt.Sprintf("aligo API return unexpected result code, result code: %d, message: %s", respBody.Code, respBody.Msg))}
 		return
 	}
-	return
+
+	if respBody.SuccessCnt == 0 && respBody.ErrorCnt == 0 {
+		return domain.SMSStatusQueued, nil
+	}
+	return domain.SMSStatusSent, nil
 }
+
+// domesticMobileRegex is regexp of domestic mobile number, which is only number aligo can send SMS to
+var domesticMobileRegex = regexp.MustCompile(`^01[016789]\d{7,8}$`)
+
+// unsupportedErr is error type represent message channel not supported in agent
+type unsupportedErr struct {
+	error
+}
+
+func (_ unsupportedErr) Unsupported() {}
+
+// transientErr is error type represent failure which may succeed if retried (ex, network error, 5xx of provider)
+type transientErr struct {
+	error
+}
+
+func (_ transientErr) Transient() {}
+
+// permanentErr is error type represent failure which fail again if retried (ex, request rejected by provider)
+type permanentErr struct {
+	error
+}
+
+func (_ permanentErr) Permanent() {}
+
+// invalidReceiverErr is permanent error type represent receiver number message can't be sent to
+type invalidReceiverErr struct {
+	error
+}
+
+func (_ invalidReceiverErr) Permanent()       {}
+func (_ invalidReceiverErr) InvalidReceiver() {}
@@ -0,0 +1,89 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// rateLimitMaxWait is max duration for which call wait token of rate limiter, over which call fail without sending
+const rateLimitMaxWait = 3 * time.Second
+
+// rateLimitedProvider is provider wrapping another one to pace call to it with token bucket at provider QPS
+// it is about respecting provider contract (reject burst over QPS), separated from cooldown of user request
+type rateLimitedProvider struct {
+	provider
+
+	// qps is number of token filled per second, and interval is duration taken to fill one token
+	qps      float64
+	interval time.Duration
+
+	mutex sync.Mutex
+	// tokens is token left in bucket at last, which is negative if token is reserved by waiting call
+	tokens float64
+	last   time.Time
+}
+
+// RateLimited return provider calling p at most qps per second (not limited if qps is not positive)
+// call exceeding QPS wait for token briefly, and fail with throttledErr if token is not filled in rateLimitMaxWait
+func RateLimited(p provider, qps float64) provider {
+	if qps <= 0 {
+		return p
+	}
+	return &rateLimitedProvider{
+		provider: p,
+		qps:      qps,
+		interval: time.Duration(float64(time.Second) / qps),
+		tokens:   1,
+		last:     time.Now(),
+	}
+}
+
+// SendSMSToOne method send SMS message to one receiver after waiting token of rate limiter
+func (rp *rateLimitedProvider) SendSMSToOne(receiver, content string) (result domain.SMSResult, err error) {
+	if err = rp.wait(); err != nil {
+		return
+	}
+	return rp.provider.SendSMSToOne(receiver, content)
+}
+
+// SendVoiceCode method send certify code by voice call to one receiver after waiting token of rate limiter
+func (rp *rateLimitedProvider) SendVoiceCode(receiver, content string) (err error) {
+	if err = rp.wait(); err != nil {
+		return
+	}
+	return rp.provider.SendVoiceCode(receiver, content)
+}
+
+// wait method reserve one token of bucket & sleep until it is filled
+// token is not reserved if it would be filled after rateLimitMaxWait, so that failed call don't delay others
+func (rp *rateLimitedProvider) wait() (err error) {
+	rp.mutex.Lock()
+	now := time.Now()
+	// bucket hold one token at most, so that call is not sent in burst over QPS
+	if rp.tokens += now.Sub(rp.last).Seconds() * rp.qps; rp.tokens > 1 {
+		rp.tokens = 1
+	}
+	rp.last = now
+
+	delay := time.Duration((1 - rp.tokens) * float64(rp.interval))
+	if delay > rateLimitMaxWait {
+		rp.mutex.Unlock()
+		return throttledErr{errors.New(fmt.Sprintf("token of rate limiter isn't filled in %s (QPS: %g)", rateLimitMaxWait, rp.qps))}
+	}
+	rp.tokens--
+	rp.mutex.Unlock()
+
+	time.Sleep(delay)
+	return
+}
+
+// throttledErr is error type represent call not sent because it exceed QPS of provider
+type throttledErr struct {
+	error
+}
+
+func (_ throttledErr) Throttled() {}
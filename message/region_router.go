@@ -0,0 +1,125 @@
+package message
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"strings"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// region of receiver, derived from country calling code of E.164 normalized number
+const (
+	RegionDomestic      = "domestic"
+	RegionInternational = "international"
+)
+
+// domesticCallingCode is country calling code of domestic number (Korea)
+const domesticCallingCode = "82"
+
+// sentByRegionMetric is metric counting message sent for each region & provider (key is region/provider)
+var sentByRegionMetric = expvar.NewMap("message_sent_by_region")
+
+// failedByRegionMetric is metric counting message failed to send for each region & provider (key is region/provider)
+var failedByRegionMetric = expvar.NewMap("message_failed_by_region")
+
+// provider is interface about message provider API (ex, aligoAgent)
+type provider interface {
+	SendSMSToOne(receiver, content string) (result domain.SMSResult, err error)
+	SendVoiceCode(receiver, content string) (err error)
+	SendEmail(receiver, title, content string) (err error)
+}
+
+// namedProvider is provider with name used in metric
+type namedProvider struct {
+	name string
+	provider
+}
+
+// regionRouter route message to providers of receiver region, trying them in order until one succeed
+// it has same method set as provider, so that usecase use it as message agency without knowing provider
+type regionRouter struct {
+	// providers is failover list of providers for each region
+	providers map[string][]namedProvider
+}
+
+// RegionRouter return regionRouter having no provider, which should be registered with Register
+func RegionRouter() *regionRouter {
+	return &regionRouter{providers: map[string][]namedProvider{}}
+}
+
+// Register method append provider with name to failover list of region (registered earlier is tried first)
+func (rr *regionRouter) Register(region, name string, p provider) *regionRouter {
+	rr.providers[region] = append(rr.providers[region], namedProvider{name: name, provider: p})
+	return rr
+}
+
+// SendSMSToOne method send SMS message to one receiver through providers of receiver region
+func (rr *regionRouter) SendSMSToOne(receiver, content string) (result domain.SMSResult, err error) {
+	err = rr.failover(regionOf(receiver), func(p provider) (err error) {
+		result, err = p.SendSMSToOne(receiver, content)
+		return
+	})
+	return
+}
+
+// SendVoiceCode method send certify code by voice call to one receiver through providers of receiver region
+func (rr *regionRouter) SendVoiceCode(receiver, content string) (err error) {
+	return rr.failover(regionOf(receiver), func(p provider) error {
+		return p.SendVoiceCode(receiver, content)
+	})
+}
+
+// SendEmail method send email to one receiver through providers of domestic region (email has no region)
+func (rr *regionRouter) SendEmail(receiver, title, content string) (err error) {
+	return rr.failover(RegionDomestic, func(p provider) error {
+		return p.SendEmail(receiver, title, content)
+	})
+}
+
+// failover method call send with providers of region in order until it succeed & return error of last provider
+// error of provider is returned without wrapping, so that caller can assert type of it (ex, Unsupported)
+// invalid receiver is not tried with next provider, since receiver is same in every provider
+func (rr *regionRouter) failover(region string, send func(p provider) error) (err error) {
+	providers := rr.providers[region]
+	if len(providers) == 0 {
+		return unsupportedErr{errors.New(fmt.Sprintf("no message provider is registered for %s region", region))}
+	}
+
+	for _, p := range providers {
+		key := region + "/" + p.name
+		if err = send(p.provider); err == nil {
+			sentByRegionMetric.Add(key, 1)
+			return
+		}
+		failedByRegionMetric.Add(key, 1)
+		if _, ok := err.(interface{ InvalidReceiver() }); ok {
+			return
+		}
+	}
+	return
+}
+
+// toE164 function return receiver number normalized in E.164 format (ex, 01012345678 -> +821012345678)
+// number not starting with + is regarded as domestic number
+func toE164(receiver string) string {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(receiver))
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		return digits
+	case strings.HasPrefix(digits, "00"):
+		return "+" + digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		return "+" + domesticCallingCode + digits[1:]
+	}
+	return "+" + domesticCallingCode + digits
+}
+
+// regionOf function return region of receiver number from country calling code of it
+func regionOf(receiver string) string {
+	if strings.HasPrefix(toE164(receiver), "+"+domesticCallingCode) {
+		return RegionDomestic
+	}
+	return RegionInternational
+}
@@ -0,0 +1,60 @@
+package message
+
+import (
+	"time"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// retriedProvider is provider wrapping another one to retry call failed with transient error (ex, network error)
+// permanent error (ex, invalid receiver) is returned at once, since it fail again however many times it is retried
+type retriedProvider struct {
+	provider
+
+	// attempts is max number of call including first one, and backoff is delay before first retry (doubled after)
+	attempts int
+	backoff  time.Duration
+}
+
+// Retried return provider calling p at most attempts times while it fail with error having Transient method
+// (not retried if attempts is less than 2), and error of last call is returned without wrapping
+func Retried(p provider, attempts int, backoff time.Duration) provider {
+	if attempts < 2 {
+		return p
+	}
+	return &retriedProvider{provider: p, attempts: attempts, backoff: backoff}
+}
+
+// SendSMSToOne method send SMS message to one receiver, retrying on transient error
+func (rp *retriedProvider) SendSMSToOne(receiver, content string) (result domain.SMSResult, err error) {
+	err = rp.retry(func() (err error) {
+		result, err = rp.provider.SendSMSToOne(receiver, content)
+		return
+	})
+	return
+}
+
+// SendVoiceCode method send certify code by voice call to one receiver, retrying on transient error
+func (rp *retriedProvider) SendVoiceCode(receiver, content string) (err error) {
+	return rp.retry(func() error {
+		return rp.provider.SendVoiceCode(receiver, content)
+	})
+}
+
+// retry method call send until it succeed, fail with error not transient or is called attempts times
+func (rp *retriedProvider) retry(send func() error) (err error) {
+	delay := rp.backoff
+	for i := 0; i < rp.attempts; i++ {
+		if i != 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		if err = send(); err == nil {
+			return
+		}
+		if _, ok := err.(interface{ Transient() }); !ok {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package message
+
+import (
+	"expvar"
+	"strings"
+)
+
+// smsSentMetric is metric counting SMS sent for each message type
+var smsSentMetric = expvar.NewMap("message_sms_sent")
+
+// smsSegmentsMetric is metric counting SMS segment sent for each message type (for cost analysis)
+var smsSegmentsMetric = expvar.NewMap("message_sms_segments")
+
+// gsm7Charset is basic character set of GSM-7 encoding
+const gsm7Charset = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7ExtCharset is extension character set of GSM-7 encoding, which take 2 septet
+const gsm7ExtCharset = "^{}\\[~]|€\f"
+
+// smsSegments return number of SMS segment that content spans
+// content having character out of GSM-7 (ex, korean) is encoded in UCS-2
+func smsSegments(content string) int {
+	var septets, units int
+	ucs2 := false
+	for _, r := range content {
+		switch {
+		case strings.ContainsRune(gsm7Charset, r):
+			septets++
+		case strings.ContainsRune(gsm7ExtCharset, r):
+			septets += 2
+		default:
+			ucs2 = true
+		}
+
+		// number of UTF-16 code unit, used as length in UCS-2 encoding
+		if units++; r >= 0x10000 {
+			units++
+		}
+	}
+
+	single, multi, length := 160, 153, septets
+	if ucs2 {
+		single, multi, length = 70, 67, units
+	}
+
+	if length <= single {
+		return 1
+	}
+	return (length + multi - 1) / multi
+}
+
+// maxSMSBytes is max byte length (EUC-KR) sent as SMS. message longer than it is sent as LMS
+const maxSMSBytes = 90
+
+// msgTypeFor return aligo msg_type (SMS or LMS) for content
+func msgTypeFor(content string) string {
+	if eucKRByteLen(content) > maxSMSBytes {
+		return "LMS"
+	}
+	return "SMS"
+}
+
+// eucKRByteLen return byte length of content in EUC-KR encoding (ASCII 1 byte, others 2 byte)
+func eucKRByteLen(content string) (n int) {
+	for _, r := range content {
+		if r < 0x80 {
+			n++
+		} else {
+			n += 2
+		}
+	}
+	return
+}
@@ -0,0 +1,99 @@
+// Package middleware provide gin middleware applied globally in server bootstrap
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"net"
+	"strings"
+)
+
+// clientIPKey is key of client IP value set in gin context by TrustedProxy middleware
+const clientIPKey = "client_ip"
+
+// forwardedProtoKey is key of X-Forwarded-Proto value set in gin context by TrustedProxy middleware
+const forwardedProtoKey = "forwarded_proto"
+
+// TrustedProxy return middleware that resolve real client IP & set it in gin context, to be get with ClientIP
+// X-Forwarded-For is honored only from trusted hops in cidrs, since header is set by client as it want.
+// (trusting header blindly let client spoof its IP, and bypass rate limit or forge audit log)
+// address in X-Forwarded-For is read from right to left, and first address not in trusted hops is client IP
+func TrustedProxy(cidrs []string) (gin.HandlerFunc, error) {
+	trusted := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse trusted proxy %s", cidr)
+		}
+		trusted = append(trusted, ipNet)
+	}
+
+	isTrusted := func(ip net.IP) bool {
+		for _, ipNet := range trusted {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		ip := remoteIP(c)
+		if ip != nil && isTrusted(ip) {
+			// protocol is set by proxy terminating TLS, so it is also honored only from trusted proxy
+			if proto := strings.Split(c.GetHeader("X-Forwarded-Proto"), ",")[0]; proto != "" {
+				c.Set(forwardedProtoKey, strings.ToLower(strings.TrimSpace(proto)))
+			}
+			hops := strings.Split(c.GetHeader("X-Forwarded-For"), ",")
+			for i := len(hops) - 1; i >= 0; i-- {
+				hop := net.ParseIP(strings.TrimSpace(hops[i]))
+				if hop == nil {
+					break
+				}
+				if ip = hop; !isTrusted(hop) {
+					break
+				}
+			}
+		}
+
+		if ip != nil {
+			c.Set(clientIPKey, ip.String())
+		}
+		c.Next()
+	}, nil
+}
+
+// ClientIP return client IP resolved in TrustedProxy middleware (return remote address if not resolved)
+func ClientIP(c *gin.Context) string {
+	if ip := c.GetString(clientIPKey); ip != "" {
+		return ip
+	}
+	if ip := remoteIP(c); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// IsHTTPS return if request is received with HTTPS, directly or through trusted proxy terminating TLS
+func IsHTTPS(c *gin.Context) bool {
+	if c.Request.TLS != nil {
+		return true
+	}
+	return c.GetString(forwardedProtoKey) == "https"
+}
+
+// remoteIP return IP parsed from remote address of request
+func remoteIP(c *gin.Context) net.IP {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(c.Request.RemoteAddr))
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"net/http"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// name of cookie & header having CSRF token
+// __Host- prefix make browser accept cookie only if it is Secure, has Path=/ and has no Domain
+const (
+	csrfTokenCookie = "__Host-csrf_token"
+	csrfTokenHeader = "X-CSRF-Token"
+)
+
+// CSRFConfig is config about CSRF middleware
+type CSRFConfig struct {
+	// Enabled represent if CSRF token is issued & checked (should be true only if token is delivered in cookie)
+	Enabled bool
+
+	// AuthCookie is name of cookie having token, request having which is authenticated by browser automatically
+	AuthCookie string
+}
+
+// CSRF return middleware that protect request authenticated with cookie from CSRF with double-submit token
+// CSRF token is issued in cookie readable by JS (not httpOnly) if request doesn't have it, and request changing
+// state with AuthCookie must send it again in X-CSRF-Token header. safe method (GET, HEAD, OPTIONS) and request
+// having Authorization header (not sent by browser automatically) are exempt, so header-token client isn't affected
+func CSRF(cfg CSRFConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		expected, _ := c.Cookie(csrfTokenCookie)
+		if expected == "" {
+			issueCSRFToken(c)
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if _, err := c.Cookie(cfg.AuthCookie); err != nil || c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+
+		received := c.GetHeader(csrfTokenHeader)
+		if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(received)) != 1 {
+			c.AbortWithStatusJSON(http.StatusForbidden, localizedResp(c, http.StatusForbidden, domain.CSRFTokenMismatch,
+				"csrf token is not set in X-CSRF-Token header or not matched with cookie"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// issueCSRFToken set random CSRF token in cookie of response
+func issueCSRFToken(c *gin.Context) {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	http.SetCookie(c.Writer, &http.Cookie{Name: csrfTokenCookie, Value: hex.EncodeToString(b), Path: "/",
+		Secure: true, HttpOnly: false, SameSite: http.SameSiteLaxMode})
+}
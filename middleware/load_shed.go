@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// LoadShedConfig is config of LoadShedder middleware (each threshold is disabled if 0)
+type LoadShedConfig struct {
+	// MaxInFlight is max number of request in process, over which sheddable request is rejected
+	MaxInFlight int64
+
+	// DBLatencySLO is latency of DB, over which sheddable request is rejected
+	DBLatencySLO time.Duration
+
+	// RetryAfter is value of Retry-After header in response of rejected request
+	RetryAfter time.Duration
+}
+
+// latencySource is interface return latest measured latency (ex, DBLatencyMonitor)
+type latencySource interface {
+	Latency() time.Duration
+}
+
+// LoadShedder return middleware that reject request to sheddable route with 503, if server is overloaded
+// every request is counted as in-flight, but only route whose full path is in sheddable is rejected,
+// so that critical route (ex, login) keep working under overload while non-critical one (ex, resend) is shed
+func LoadShedder(cfg LoadShedConfig, db latencySource, sheddable []string) gin.HandlerFunc {
+	var inFlight int64
+	shed := make(map[string]bool, len(sheddable))
+	for _, path := range sheddable {
+		shed[path] = true
+	}
+	retryAfter := strconv.Itoa(int(cfg.RetryAfter.Seconds()))
+
+	overloaded := func(n int64) bool {
+		if cfg.MaxInFlight > 0 && n > cfg.MaxInFlight {
+			return true
+		}
+		return cfg.DBLatencySLO > 0 && db != nil && db.Latency() > cfg.DBLatencySLO
+	}
+
+	return func(c *gin.Context) {
+		n := atomic.AddInt64(&inFlight, 1)
+		defer atomic.AddInt64(&inFlight, -1)
+
+		if shed[c.FullPath()] && overloaded(n) {
+			c.Header("Retry-After", retryAfter)
+			msg := "server is overloaded, please retry after a while"
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, localizedResp(c, http.StatusServiceUnavailable, 0, msg))
+			return
+		}
+		c.Next()
+	}
+}
+
+// pinger is interface about DB able to be pinged (ex, *sqlx.DB)
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// DBLatencyMonitor measure latency of DB by pinging periodically, and implement latencySource
+type DBLatencyMonitor struct {
+	latency int64
+}
+
+// MonitorDBLatency return DBLatencyMonitor pinging db every interval in background
+// failed or timed out ping is recorded as latency of interval, so that it is regarded as slow
+func MonitorDBLatency(db pinger, interval time.Duration) *DBLatencyMonitor {
+	m := &DBLatencyMonitor{}
+	go func() {
+		for range time.Tick(interval) {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			start := time.Now()
+			latency := interval
+			if err := db.PingContext(ctx); err == nil {
+				latency = time.Since(start)
+			}
+			cancel()
+			atomic.StoreInt64(&m.latency, int64(latency))
+		}
+	}()
+	return m
+}
+
+// Latency return latest measured latency of DB
+func (m *DBLatencyMonitor) Latency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&m.latency))
+}
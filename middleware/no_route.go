@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+)
+
+// NoRoute is handler registered with gin.Engine.NoRoute, responding 404 with response envelope instead of plain text
+func NoRoute(c *gin.Context) {
+	msg := "route not found: " + c.Request.URL.Path
+	c.JSON(http.StatusNotFound, localizedResp(c, http.StatusNotFound, domain.RouteNotFound, msg))
+}
+
+// NoMethod is handler registered with gin.Engine.NoMethod, responding 405 with response envelope instead of plain text
+// gin.Engine.HandleMethodNotAllowed must be set to true, otherwise request with wrong method is handled in NoRoute
+func NoMethod(c *gin.Context) {
+	msg := "method not allowed: " + c.Request.Method
+	c.JSON(http.StatusMethodNotAllowed, localizedResp(c, http.StatusMethodNotAllowed, domain.MethodNotAllowed, msg))
+}
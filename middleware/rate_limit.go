@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitConfig is config of RateLimiter middleware (disabled if Limit is 0)
+type RateLimitConfig struct {
+	// Limit is max number of request of one client to one route in Window, over which request is rejected
+	Limit int64
+
+	// Window is duration of fixed window in which request is counted
+	Window time.Duration
+}
+
+// counterStore is interface about store counting with TTL (ex, kv.Memory, kv.Redis)
+type counterStore interface {
+	Incr(key string, ttl time.Duration) (int64, error)
+}
+
+// RateLimiter return middleware that reject request to limited route with 429, if client exceed limit in window
+// request is counted by client IP (resolved in TrustedProxy) & full path of route in store, so that limit is
+// applied across server instances if store is shared, and request is allowed if store is not available
+func RateLimiter(cfg RateLimitConfig, store counterStore, limited []string) gin.HandlerFunc {
+	limit := make(map[string]bool, len(limited))
+	for _, path := range limited {
+		limit[path] = true
+	}
+	retryAfter := strconv.Itoa(int(cfg.Window.Seconds()))
+
+	return func(c *gin.Context) {
+		if cfg.Limit <= 0 || cfg.Window <= 0 || !limit[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		n, err := store.Incr("rate_limit:"+c.FullPath()+":"+ClientIP(c), cfg.Window)
+		if err != nil {
+			log.Printf("failed to count request for rate limit, err: %v", err)
+		} else if n > cfg.Limit {
+			c.Header("Retry-After", retryAfter)
+			msg := "too many request, please retry after a while"
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, localizedResp(c, http.StatusTooManyRequests, 0, msg))
+			return
+		}
+		c.Next()
+	}
+}
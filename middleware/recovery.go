@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recovery return middleware recovering panic in handler & responding 500 with response envelope, instead of
+// gin.Recovery responding plain 500. panic & stack is logged with generated reference id, and only the id is
+// responded like internal error of delivery handler, so that client report the id & operator find log with it
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// http.ErrAbortHandler is panicked to abort response deliberately, which is handled by net/http
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			b := make([]byte, 8)
+			_, _ = rand.Read(b)
+			ref := hex.EncodeToString(b)
+			log.Printf("panic recovered, ref: %s, route: %s %s, panic: %v\n%s", ref, c.Request.Method, c.FullPath(), rec, debug.Stack())
+
+			// envelope can't be written after part of response was written already
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			resp := localizedResp(c, http.StatusInternalServerError, 0, "internal error,")
+			resp.Message += " ref: " + ref
+			resp.Ref = ref
+			c.AbortWithStatusJSON(http.StatusInternalServerError, resp)
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/MyFirstBabyTime/Server/domain"
+	"github.com/MyFirstBabyTime/Server/locale"
+)
+
+// response is response envelope having status, code, message inform, same as response of delivery handler
+type response struct {
+	Status  int    `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+
+	// Key is stable key of error, which message is localized with (set only in error response)
+	Key string `json:"key,omitempty"`
+
+	// Ref is reference id of internal error, set only in response of recovered panic
+	Ref string `json:"ref,omitempty"`
+}
+
+// defaultResp return response have status, code, message inform
+func defaultResp(status, code int, msg string) response {
+	return response{Status: status, Code: code, Message: msg}
+}
+
+// localizedResp return response with message localized in locale negotiated from Accept-Language of c
+// msg is used as it is if catalog doesn't have message about key of status & code
+func localizedResp(c *gin.Context, status, code int, msg string) response {
+	key := domain.ErrorKey(status, code)
+	if localized, ok := locale.Message(locale.Negotiate(c.GetHeader("Accept-Language")), key); ok {
+		msg = localized
+	}
+	resp := defaultResp(status, code, msg)
+	resp.Key = key
+	return resp
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SecureHeaderConfig is config about SecureHeader middleware
+type SecureHeaderConfig struct {
+	// HSTSMaxAge is max-age of Strict-Transport-Security header (header is not set if 0)
+	HSTSMaxAge time.Duration
+
+	// RedirectHTTPS represent if redirect request received with HTTP to HTTPS
+	RedirectHTTPS bool
+}
+
+// SecureHeader return middleware that set security headers, and redirect HTTP request to HTTPS if configured
+// request to exemptPaths (ex, health check of load balancer) is not redirected, but headers are set as well
+// HTTPS must be able to be detected with IsHTTPS, so TrustedProxy must be applied before if TLS is terminated in proxy
+func SecureHeader(cfg SecureHeaderConfig, exemptPaths []string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+	hsts := "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge/time.Second), 10) + "; includeSubDomains"
+
+	return func(c *gin.Context) {
+		https := IsHTTPS(c)
+		if cfg.RedirectHTTPS && !https && !exempt[c.Request.URL.Path] {
+			// 308 keeps method & body of request, unlike 301
+			c.Redirect(http.StatusPermanentRedirect, "https://"+c.Request.Host+c.Request.URL.RequestURI())
+			c.Abort()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "no-referrer")
+		// browser ignore HSTS received over HTTP, so it is set only in HTTPS response
+		if https && cfg.HSTSMaxAge > 0 {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		c.Next()
+	}
+}
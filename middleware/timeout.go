@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// TimeoutConfig is config of Timeout middleware
+type TimeoutConfig struct {
+	// Default is timeout of route not in Routes (no timeout if 0)
+	Default time.Duration
+
+	// Routes is timeout of each route keyed by full path, which override Default (no timeout if 0)
+	Routes map[string]time.Duration
+}
+
+// Timeout return middleware setting deadline of request context by timeout of route, so that usecase & repository
+// using the context (ex, transaction) stop at deadline, and respond 504 if handler didn't respond until then
+// route waiting slow dependency (ex, message provider) can have longer timeout, while others keep short one
+func Timeout(cfg TimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := cfg.Routes[c.FullPath()]
+		if !ok {
+			timeout = cfg.Default
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if ctx.Err() == context.DeadlineExceeded && !c.Writer.Written() {
+			msg := "request is timed out, please retry after a while"
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, localizedResp(c, http.StatusGatewayTimeout, 0, msg))
+		}
+	}
+}
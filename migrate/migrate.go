@@ -0,0 +1,190 @@
+package migrate
+
+import (
+	"database/sql"
+	"github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationTable is name of table recording version of migration applied
+const migrationTable = "schema_migration"
+
+// fileNameRegexp is regexp of migration file name, ex) 0001_create_parent_auth.up.sql
+var fileNameRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// migration is struct represent one version of migration read from file
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// Up apply migration in fsys not applied yet to db in order of version
+// migration file is named as {version}_{name}.up.sql & {version}_{name}.down.sql, and statements in file is split by ';'
+//
+// MySQL doesn't roll back DDL with transaction, so version is recorded as dirty before migration is applied and
+// cleared after that. if migration failed at the middle, Up return error until dirty version is fixed manually
+func Up(db *sqlx.DB, fsys fs.FS) (err error) {
+	migrations, err := readMigrations(fsys)
+	if err != nil {
+		return
+	}
+	current, err := currentVersion(db)
+	if err != nil {
+		return
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if _, err = db.Exec("INSERT INTO "+migrationTable+" (version, dirty) VALUES (?, 1)", m.version); err != nil {
+			return errors.Wrapf(err, "failed to record version %d as dirty", m.version)
+		}
+		if err = execStatements(db, m.up); err != nil {
+			return errors.Wrapf(err, "failed to apply migration %04d_%s", m.version, m.name)
+		}
+		if _, err = db.Exec("UPDATE "+migrationTable+" SET dirty = 0 WHERE version = ?", m.version); err != nil {
+			return errors.Wrapf(err, "failed to clear dirty of version %d", m.version)
+		}
+	}
+	return nil
+}
+
+// Down revert steps number of migration applied to db in reverse order of version
+func Down(db *sqlx.DB, fsys fs.FS, steps int) (err error) {
+	migrations, err := readMigrations(fsys)
+	if err != nil {
+		return
+	}
+	current, err := currentVersion(db)
+	if err != nil {
+		return
+	}
+
+	for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := migrations[i]
+		if m.version > current {
+			continue
+		}
+		if _, err = db.Exec("UPDATE "+migrationTable+" SET dirty = 1 WHERE version = ?", m.version); err != nil {
+			return errors.Wrapf(err, "failed to record version %d as dirty", m.version)
+		}
+		if err = execStatements(db, m.down); err != nil {
+			return errors.Wrapf(err, "failed to revert migration %04d_%s", m.version, m.name)
+		}
+		if _, err = db.Exec("DELETE FROM "+migrationTable+" WHERE version = ?", m.version); err != nil {
+			return errors.Wrapf(err, "failed to delete version %d", m.version)
+		}
+		steps--
+	}
+	return nil
+}
+
+// currentVersion return last version of migration applied to db, or 0 if nothing is applied
+// it create table recording version if not exist, and return error if last version is dirty
+func currentVersion(db *sqlx.DB) (version int, err error) {
+	if _, err = db.Exec(`CREATE TABLE IF NOT EXISTS ` + migrationTable + ` (
+		version BIGINT  NOT NULL,
+		dirty   TINYINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (version)
+	);`); err != nil {
+		return 0, errors.Wrap(err, "failed to create migration table")
+	}
+
+	var last struct {
+		Version int  `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+	switch err = db.Get(&last, "SELECT version, dirty FROM "+migrationTable+" ORDER BY version DESC LIMIT 1"); err {
+	case nil:
+		break
+	case sql.ErrNoRows:
+		return 0, nil
+	default:
+		return 0, errors.Wrap(err, "failed to select last version of migration")
+	}
+
+	if last.Dirty {
+		return 0, errors.Errorf("migration version %d is dirty, please fix schema and %s table manually", last.Version, migrationTable)
+	}
+	return last.Version, nil
+}
+
+// readMigrations return migration read from file in root of fsys, in order of version
+func readMigrations(fsys fs.FS) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read migration directory")
+	}
+
+	byVersion := map[int]*migration{}
+	for _, e := range entries {
+		match := fileNameRegexp.FindStringSubmatch(e.Name())
+		if e.IsDir() || match == nil {
+			continue
+		}
+		content, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read migration file %s", e.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: match[2]}
+			byVersion[version] = m
+		}
+		if m.name != match[2] {
+			return nil, errors.Errorf("migration version %d has different name, %s & %s", version, m.name, match[2])
+		}
+		if match[3] == "up" {
+			m.up = string(content)
+		} else {
+			m.down = string(content)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, errors.Errorf("migration %04d_%s must have both up & down file", m.version, m.name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// execStatements execute statements in SQL split by ';' one by one, since driver doesn't allow multi statements
+// statement adding column or index already exist (or dropping one not exist) is regarded as applied, since schema
+// may be changed by repository already (it migrate table itself) before migration is applied
+func execStatements(db *sqlx.DB, statements string) error {
+	for _, stmt := range strings.Split(statements, ";") {
+		if stmt = strings.TrimSpace(stmt); stmt == "" {
+			continue
+		}
+		switch _, err := db.Exec(stmt); tErr := err.(type) {
+		case nil:
+			break
+		case *mysql.MySQLError:
+			switch tErr.Number {
+			case mysqlerr.ER_DUP_FIELDNAME, mysqlerr.ER_DUP_KEYNAME, mysqlerr.ER_CANT_DROP_FIELD_OR_KEY:
+				break
+			default:
+				return err
+			}
+		default:
+			return err
+		}
+	}
+	return nil
+}
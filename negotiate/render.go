@@ -0,0 +1,125 @@
+// Package negotiate provide writing response envelope in format negotiated from Accept header, used by auth handler
+// & jwt middleware in front of it, so that client preferring protobuf get it also in error rejecting token
+package negotiate
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/golang/protobuf/proto"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+	"log"
+)
+
+// Render write resp in format negotiated from Accept header of c, which is JSON by default
+// protobuf is written only if client prefer application/x-protobuf, and JSON is written if resp can't be converted
+func Render(c *gin.Context, status int, resp interface{}) {
+	c.Header("Vary", "Accept")
+	if c.NegotiateFormat(binding.MIMEJSON, binding.MIMEPROTOBUF) == binding.MIMEPROTOBUF {
+		pb, err := protoResp(resp)
+		if err == nil {
+			c.ProtoBuf(status, pb)
+			return
+		}
+		log.Printf("failed to convert response to protobuf, respond in JSON instead, err: %v", err)
+	}
+	c.JSON(status, resp)
+}
+
+// AbortWithStatus abort remaining handlers of c & write resp like Render (used instead of c.AbortWithStatusJSON)
+func AbortWithStatus(c *gin.Context, status int, resp interface{}) {
+	c.Abort()
+	Render(c, status, resp)
+}
+
+// envelope is field of response envelope embedded in every response type, set in field of protoResponse
+type envelope struct {
+	Status     int    `json:"status"`
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+	Key        string `json:"key"`
+	Ref        string `json:"ref"`
+	APIVersion string `json:"api_version"`
+}
+
+// protoResponse is Response message of response.proto, declared by hand since it is only message of the file
+// field must be kept in sync with response.proto
+type protoResponse struct {
+	Status     int32            `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Code       int32            `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message    string           `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Key        string           `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	Ref        string           `protobuf:"bytes,5,opt,name=ref,proto3" json:"ref,omitempty"`
+	APIVersion string           `protobuf:"bytes,6,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	Data       *structpb.Struct `protobuf:"bytes,7,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *protoResponse) Reset()         { *m = protoResponse{} }
+func (m *protoResponse) String() string { return proto.CompactTextString(m) }
+func (*protoResponse) ProtoMessage()    {}
+
+// protoResp convert response type embedding response envelope into protoResponse through JSON of it, so that field of
+// envelope is set in field of message, and every other field is set in data with same name & value as in JSON
+func protoResp(resp interface{}) (*protoResponse, error) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal response into JSON")
+	}
+	var fields map[string]interface{}
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal JSON of response into object")
+	}
+
+	var env envelope
+	if err = json.Unmarshal(b, &env); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal JSON of response into envelope")
+	}
+	for _, key := range []string{"status", "code", "message", "key", "ref", "api_version"} {
+		delete(fields, key)
+	}
+
+	pb := &protoResponse{
+		Status:     int32(env.Status),
+		Code:       int32(env.Code),
+		Message:    env.Message,
+		Key:        env.Key,
+		Ref:        env.Ref,
+		APIVersion: env.APIVersion,
+	}
+	if len(fields) > 0 {
+		pb.Data = protoStruct(fields)
+	}
+	return pb, nil
+}
+
+// protoStruct convert JSON object into protobuf Struct
+func protoStruct(fields map[string]interface{}) *structpb.Struct {
+	s := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(fields))}
+	for k, v := range fields {
+		s.Fields[k] = protoValue(v)
+	}
+	return s
+}
+
+// protoValue convert JSON value (object, array, string, number, bool, null) into protobuf Value
+func protoValue(v interface{}) *structpb.Value {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return &structpb.Value{Kind: &structpb.Value_StructValue{StructValue: protoStruct(v)}}
+	case []interface{}:
+		list := &structpb.ListValue{Values: make([]*structpb.Value, len(v))}
+		for i, e := range v {
+			list.Values[i] = protoValue(e)
+		}
+		return &structpb.Value{Kind: &structpb.Value_ListValue{ListValue: list}}
+	case string:
+		return &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: v}}
+	case float64:
+		return &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: v}}
+	case bool:
+		return &structpb.Value{Kind: &structpb.Value_BoolValue{BoolValue: v}}
+	default:
+		return &structpb.Value{Kind: &structpb.Value_NullValue{}}
+	}
+}
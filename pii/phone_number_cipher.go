@@ -0,0 +1,92 @@
+// Package pii provide cipher protecting personal information (ex, phone number) stored in DB.
+//
+// phoneNumberCipher keep phone number out of DB as plaintext, with two value derived from normalized phone number.
+//   - hash: HMAC-SHA256 of phone number, which is deterministic so that row is looked up by phone number with it
+//   - encrypted: AES-GCM encrypted phone number, which is decrypted when phone number itself is needed (ex, sending SMS)
+//
+// Migration path of plaintext phone number is like below
+//  1. set PHONE_NUMBER_HMAC_KEY & PHONE_NUMBER_ENCRYPTION_KEY in environment variable & redeploy.
+//  2. repository hash & encrypt every plaintext phone number stored before in its constructor,
+//     and drop plaintext column after that, so that both are never mixed in lookup
+//
+// DO NOT change or remove key after setting it. phone number stored before can't be looked up or decrypted then.
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/pkg/errors"
+)
+
+// minHMACKeyLength is minimum length of HMAC key, which is same as output length of SHA-256
+const minHMACKeyLength = 32
+
+// phoneNumberCipher is cipher hashing & encrypting phone number
+type phoneNumberCipher struct {
+	hmacKey []byte
+	aead    cipher.AEAD
+}
+
+// PhoneNumberCipher return phoneNumberCipher using hmacKey & encryptionKey, which are encoded in base64
+// encryptionKey must be 16, 24 or 32 byte to select AES-128, AES-192 or AES-256
+func PhoneNumberCipher(hmacKey, encryptionKey string) (*phoneNumberCipher, error) {
+	hk, err := base64.StdEncoding.DecodeString(hmacKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode HMAC key in base64")
+	}
+	if len(hk) < minHMACKeyLength {
+		return nil, errors.Errorf("HMAC key must be at least %d byte", minHMACKeyLength)
+	}
+
+	ek, err := base64.StdEncoding.DecodeString(encryptionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode encryption key in base64")
+	}
+	block, err := aes.NewCipher(ek)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AES cipher with encryption key")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create GCM of AES cipher")
+	}
+
+	return &phoneNumberCipher{
+		hmacKey: hk,
+		aead:    aead,
+	}, nil
+}
+
+// Hash return HMAC-SHA256 of pn in hex, which is 64 character. pn must be normalized before, to be looked up with it
+func (pc *phoneNumberCipher) Hash(pn string) string {
+	mac := hmac.New(sha256.New, pc.hmacKey)
+	mac.Write([]byte(pn))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Encrypt return pn encrypted with AES-GCM, having random nonce in front of it
+func (pc *phoneNumberCipher) Encrypt(pn string) ([]byte, error) {
+	nonce := make([]byte, pc.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	return pc.aead.Seal(nonce, nonce, []byte(pn), nil), nil
+}
+
+// Decrypt return phone number decrypted from encrypted returned by Encrypt
+func (pc *phoneNumberCipher) Decrypt(encrypted []byte) (string, error) {
+	size := pc.aead.NonceSize()
+	if len(encrypted) < size {
+		return "", errors.New("encrypted phone number is shorter than nonce")
+	}
+	pn, err := pc.aead.Open(nil, encrypted[:size], encrypted[size:], nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt phone number")
+	}
+	return string(pn), nil
+}
@@ -1,6 +1,10 @@
 package tx
 
-import "context"
+import (
+	"context"
+	"database/sql"
+	"time"
+)
 
 // Context is interface have func about get & set TX value with embedded context.Context
 type Context interface {
@@ -13,6 +17,9 @@ type Context interface {
 type txContext struct {
 	context.Context
 	txKey interface{}
+
+	// cancel is called when transaction end, if transaction begin with timeout
+	cancel context.CancelFunc
 }
 
 // Tx method Get TX value from context
@@ -20,3 +27,47 @@ func (tc *txContext) Tx() (tx interface{}) { return tc.Value(tc.txKey) }
 
 // SetTx method Set TX value in context
 func (tc *txContext) SetTx(tx interface{}) { tc.Context = context.WithValue(tc.Context, tc.txKey, tx) }
+
+// contextKey is used for key for Context value stashed in context.Context
+type contextKey struct{}
+
+// WithContext return copy of ctx carrying txCtx, so that txCtx need not to be passed to every helper function
+// passing txCtx explicitly to repository is still the main way, and this is for flow spanning several repository
+func WithContext(ctx context.Context, txCtx Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, txCtx)
+}
+
+// FromContext return Context stashed in ctx by WithContext & ok false if not exist
+func FromContext(ctx context.Context) (txCtx Context, ok bool) {
+	txCtx, ok = ctx.Value(contextKey{}).(Context)
+	return
+}
+
+// Options is transaction options put in context with WithOptions, and used by usecase beginning transaction
+type Options struct {
+	// Isolation is isolation level of transaction (default level of DB is used if zero)
+	Isolation sql.IsolationLevel
+
+	// ReadOnly represent if transaction is read-only
+	ReadOnly bool
+
+	// Timeout is duration after which transaction is canceled (not limited if 0)
+	Timeout time.Duration
+}
+
+// optionsKey is used for key for Options value stashed in context.Context
+type optionsKey struct{}
+
+// WithOptions return copy of ctx carrying opts, so that caller (ex, test or handler) change option of transaction
+// begun by usecase without changing method signature (ex, force serializable isolation to reproduce race condition)
+func WithOptions(ctx context.Context, opts Options) context.Context {
+	return context.WithValue(ctx, optionsKey{}, opts)
+}
+
+// OptionsFromContext return Options stashed in ctx by WithOptions & nil if not exist (passed to BeginTx as it is)
+func OptionsFromContext(ctx context.Context) *Options {
+	if opts, ok := ctx.Value(optionsKey{}).(Options); ok {
+		return &opts
+	}
+	return nil
+}
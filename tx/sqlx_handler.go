@@ -16,16 +16,32 @@ func NewSqlxHandler(db *sqlx.DB) *sqlxHandler { return &sqlxHandler{db} }
 type sqlxTxKey struct{}
 
 // BeginTx method start transaction (get option from ctx)
+// opts can be *sql.TxOptions or *Options, and Timeout of *Options cancel transaction after it
 func (sh *sqlxHandler) BeginTx(ctx context.Context, opts interface{}) (txCtx Context, err error) {
-	var tx *sqlx.Tx
-	switch opts.(type) {
+	var (
+		tx     *sqlx.Tx
+		cancel context.CancelFunc
+	)
+	switch opts := opts.(type) {
 	case *sql.TxOptions:
-		tx, err = sh.db.BeginTxx(ctx, opts.(*sql.TxOptions))
+		tx, err = sh.db.BeginTxx(ctx, opts)
+	case *Options:
+		if opts == nil {
+			tx, err = sh.db.BeginTxx(ctx, nil)
+			break
+		}
+		if opts.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		}
+		tx, err = sh.db.BeginTxx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
 	default:
 		tx, err = sh.db.BeginTxx(ctx, nil)
 	}
 
 	if err != nil {
+		if cancel != nil {
+			cancel()
+		}
 		err = errors.Wrap(err, "failed to begin sqlx transaction")
 		return
 	}
@@ -33,6 +49,7 @@ func (sh *sqlxHandler) BeginTx(ctx context.Context, opts interface{}) (txCtx Con
 	txCtx = &txContext{
 		Context: context.Background(),
 		txKey:   sqlxTxKey{},
+		cancel:  cancel,
 	}
 	txCtx.SetTx(tx)
 	return
@@ -40,10 +57,19 @@ func (sh *sqlxHandler) BeginTx(ctx context.Context, opts interface{}) (txCtx Con
 
 // Commit method commit transaction
 func (sh *sqlxHandler) Commit(ctx Context) (err error) {
+	defer endTxContext(ctx)
 	return ctx.Tx().(*sqlx.Tx).Commit()
 }
 
 // Rollback method rollback transaction
 func (sh *sqlxHandler) Rollback(ctx Context) (err error) {
+	defer endTxContext(ctx)
 	return ctx.Tx().(*sqlx.Tx).Rollback()
 }
+
+// endTxContext release timeout of transaction context, if it begin with timeout
+func endTxContext(ctx Context) {
+	if tc, ok := ctx.(*txContext); ok && tc.cancel != nil {
+		tc.cancel()
+	}
+}
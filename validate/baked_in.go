@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // isValidateUUID function return if uuid format is validate
@@ -21,6 +22,8 @@ func isValidateUUID(fl validator.FieldLevel) bool {
 		return itemUUIDRegex.MatchString(fl.Field().String())
 	case "children":
 		return childrenRegex.MatchString(fl.Field().String())
+	case "session":
+		return sessionRegex.MatchString(fl.Field().String())
 	}
 	return false
 }
@@ -65,6 +68,24 @@ func isNotEmptyValue(fl validator.FieldLevel) bool {
 	return true
 }
 
+// isSafeText function return if string has no control or invisible format character (ex, bidi override),
+// and no HTML-significant character if strictness of text is TextStrictnessHTML
+func isSafeText(fl validator.FieldLevel) bool {
+	if fl.Field().Kind() != reflect.String {
+		return true
+	}
+
+	for _, r := range fl.Field().String() {
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return false
+		}
+		if textStrictness == TextStrictnessHTML && strings.ContainsRune(htmlSignificantChars, r) {
+			return false
+		}
+	}
+	return true
+}
+
 // sqlNullStringTypeConverter function assert driver.Valuer & return Value()
 func sqlNullStringTypeConverter(field reflect.Value) (v interface{}) {
 	v = ""
@@ -3,7 +3,9 @@ package validate
 import (
 	"database/sql"
 	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
 	"reflect"
+	"strings"
 )
 
 // validatorInstance is global variable returned in customValidator function
@@ -16,9 +18,20 @@ func init() {
 	_ = v.RegisterValidation("uuid", isValidateUUID)
 	_ = v.RegisterValidation("range", isWithinRange)
 	_ = v.RegisterValidation("not_empty", isNotEmptyValue)
+	_ = v.RegisterValidation("safe_text", isSafeText)
 
 	v.RegisterCustomTypeFunc(sqlNullStringTypeConverter, sql.NullString{})
 
+	// use field name in request (json, uri, form tag) in validation error, so that client know which field is invalid
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "uri", "form"} {
+			if name := strings.Split(f.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+				return name
+			}
+		}
+		return f.Name
+	})
+
 	validatorInstance = &customValidator{v}
 }
 
@@ -27,6 +40,36 @@ func New() *customValidator {
 	return validatorInstance
 }
 
+// value of strictness about text validated with safe_text tag
+const (
+	// TextStrictnessControl reject control & invisible format character only, so that punctuation in name is allowed
+	TextStrictnessControl = "control"
+
+	// TextStrictnessHTML reject HTML-significant character also, as defense-in-depth of client not escaping text
+	TextStrictnessHTML = "html"
+)
+
+// htmlSignificantChars is character rejected in text validated with safe_text tag in TextStrictnessHTML
+const htmlSignificantChars = "<>&\"'`"
+
+// textStrictness is strictness of safe_text tag, set with SetTextStrictness in server bootstrap
+var textStrictness = TextStrictnessControl
+
+// SetTextStrictness method set strictness of text validated with safe_text tag (TextStrictnessControl if empty)
+// it must be called before validating, since strictness is shared by every validation without lock
+func (mv *customValidator) SetTextStrictness(strictness string) error {
+	switch strictness {
+	case "":
+		strictness = TextStrictnessControl
+	case TextStrictnessControl, TextStrictnessHTML:
+		break
+	default:
+		return errors.Errorf("unknown text strictness %s", strictness)
+	}
+	textStrictness = strictness
+	return nil
+}
+
 // customValidator is struct embedding *validator.Validate which is registered custom validation
 type customValidator struct {
 	*validator.Validate